@@ -19,6 +19,11 @@ type Cmd struct {
 	Stdin  *os.File
 	Stdout *os.File
 	Stderr *os.File
+	// Env lists "KEY=value" entries that the command gets in addition to the
+	// environment of hub itself
+	Env []string
+	// Dir is the working directory of the command unless it's run with `Exec`
+	Dir string
 }
 
 func (cmd Cmd) String() string {
@@ -39,16 +44,35 @@ func (cmd *Cmd) WithArgs(args ...string) *Cmd {
 	return cmd
 }
 
+func (cmd *Cmd) WithEnv(key, value string) *Cmd {
+	cmd.Env = append(cmd.Env, key+"="+value)
+
+	return cmd
+}
+
+func (cmd *Cmd) environ() []string {
+	return append(os.Environ(), cmd.Env...)
+}
+
+func (cmd *Cmd) command() *exec.Cmd {
+	c := exec.Command(cmd.Name, cmd.Args...)
+	if len(cmd.Env) > 0 {
+		c.Env = cmd.environ()
+	}
+	c.Dir = cmd.Dir
+	return c
+}
+
 func (cmd *Cmd) CombinedOutput() (string, error) {
 	verboseLog(cmd)
-	output, err := exec.Command(cmd.Name, cmd.Args...).CombinedOutput()
+	output, err := cmd.command().CombinedOutput()
 
 	return string(output), err
 }
 
 func (cmd *Cmd) Success() bool {
 	verboseLog(cmd)
-	err := exec.Command(cmd.Name, cmd.Args...).Run()
+	err := cmd.command().Run()
 	return err == nil
 }
 
@@ -87,7 +111,7 @@ func detectWSL() bool {
 // Spawn runs command with spawn(3)
 func (cmd *Cmd) Spawn() error {
 	verboseLog(cmd)
-	c := exec.Command(cmd.Name, cmd.Args...)
+	c := cmd.command()
 	c.Stdin = cmd.Stdin
 	c.Stdout = cmd.Stdout
 	c.Stderr = cmd.Stderr
@@ -95,6 +119,32 @@ func (cmd *Cmd) Spawn() error {
 	return c.Run()
 }
 
+// FilterOutput runs command with input fed via stdin and returns its stdout.
+// Without Stderr, the stderr of a failed command is kept in the returned
+// *exec.ExitError instead.
+func (cmd *Cmd) FilterOutput(input string) (string, error) {
+	verboseLog(cmd)
+	c := cmd.command()
+	c.Stdin = strings.NewReader(input)
+	if cmd.Stderr != nil {
+		c.Stderr = cmd.Stderr
+	}
+	output, err := c.Output()
+
+	return string(output), err
+}
+
+// SpawnWithInput runs command with spawn(3), feeding it input via stdin
+func (cmd *Cmd) SpawnWithInput(input string) error {
+	verboseLog(cmd)
+	c := cmd.command()
+	c.Stdin = strings.NewReader(input)
+	c.Stdout = cmd.Stdout
+	c.Stderr = cmd.Stderr
+
+	return c.Run()
+}
+
 // Exec runs command with exec(3)
 // Note that Windows doesn't support exec(3): http://golang.org/src/pkg/syscall/exec_windows.go#L339
 func (cmd *Cmd) Exec() error {
@@ -111,7 +161,7 @@ func (cmd *Cmd) Exec() error {
 	args := []string{binary}
 	args = append(args, cmd.Args...)
 
-	return syscall.Exec(binary, args, os.Environ())
+	return syscall.Exec(binary, args, cmd.environ())
 }
 
 func New(cmd string) *Cmd {
@@ -128,8 +178,17 @@ func NewWithArray(cmd []string) *Cmd {
 	return &Cmd{Name: cmd[0], Args: cmd[1:], Stdin: os.Stdin, Stdout: os.Stdout, Stderr: os.Stderr}
 }
 
+// NewShell runs command with the shell of the platform: `sh` everywhere but
+// on Windows, where it's `cmd`
+func NewShell(command string) *Cmd {
+	if runtime.GOOS == "windows" {
+		return NewWithArray([]string{"cmd", "/c", command})
+	}
+	return NewWithArray([]string{"sh", "-c", command})
+}
+
 func verboseLog(cmd *Cmd) {
-	if os.Getenv("HUB_VERBOSE") != "" {
+	if utils.VerboseLevel() > 0 {
 		msg := fmt.Sprintf("$ %s %s", cmd.Name, strings.Join(cmd.Args, " "))
 		if ui.IsTerminal(os.Stderr) {
 			msg = fmt.Sprintf("\033[35m%s\033[0m", msg)
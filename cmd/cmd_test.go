@@ -1,6 +1,10 @@
 package cmd
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
 	"testing"
 
 	"github.com/bmizerany/assert"
@@ -19,3 +23,30 @@ func TestWithArg(t *testing.T) {
 	assert.Equal(t, "git", execCmd.Name)
 	assert.Equal(t, 4, len(execCmd.Args))
 }
+
+func TestNewShell(t *testing.T) {
+	execCmd := NewShell("echo hi")
+	if runtime.GOOS == "windows" {
+		assert.Equal(t, "cmd", execCmd.Name)
+		assert.Equal(t, []string{"/c", "echo hi"}, execCmd.Args)
+	} else {
+		assert.Equal(t, "sh", execCmd.Name)
+		assert.Equal(t, []string{"-c", "echo hi"}, execCmd.Args)
+	}
+}
+
+func TestEnvAndDir(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs sh")
+	}
+	dir, _ := ioutil.TempDir("", "cmd-test")
+	defer os.RemoveAll(dir)
+
+	execCmd := NewShell(`echo "$HUB_CMD_TEST"; pwd`).WithEnv("HUB_CMD_TEST", "hello")
+	execCmd.Dir = dir
+	output, err := execCmd.CombinedOutput()
+	assert.Equal(t, nil, err)
+	resolved, _ := filepath.EvalSymlinks(dir)
+	assert.Equal(t, "hello\n"+resolved+"\n", output)
+	assert.Equal(t, "", os.Getenv("HUB_CMD_TEST"))
+}
@@ -1,13 +1,20 @@
 package commands
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net/http"
+	"net/url"
 	"os"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"text/template"
+	"time"
 
 	"github.com/github/hub/github"
 	"github.com/github/hub/ui"
@@ -15,15 +22,22 @@ import (
 )
 
 var cmdApi = &Command{
-	Run:   apiCommand,
-	Usage: "api [-it] [-X <METHOD>] [-H <HEADER>] [--cache <TTL>] <ENDPOINT> [-F <FIELD>|--input <FILE>]",
+	Run: apiCommand,
+	Usage: `
+api [-it] [-X <METHOD>] [-H <HEADER>] [--no-preview] [--cache <TTL>] [--connect-timeout <DURATION>] [--paginate [--slurp] [--keep-going] [--max-pages <N>] [--max-items <N>]] [--silent] [--include-rate-limit-in-error] [-o <FILE>] [--template <TEMPLATE>|--jq <EXPR>] [--expect-status <STATUSES>] [--expect-jq <EXPR>] <ENDPOINT> [-F <FIELD>|--input <FILE> [--content-type <TYPE>]]
+api [-it] [-H <HEADER>] [--cache <TTL>] [--silent] [--include-rate-limit-in-error] --graphql-file <FILE> [-F <FIELD>]
+`,
 	Long: `Low-level GitHub API request interface.
 
 ## Options:
 	-X, --method <METHOD>
-		The HTTP method to use for the request (default: "GET"). The method is
-		automatically set to "POST" if '--field', '--raw-field', or '--input' are
-		used.
+		The HTTP method to use for the request (default: "GET"): one of "GET",
+		"HEAD", "POST", "PUT", "PATCH", "DELETE", or "OPTIONS", in any case. An
+		explicit <METHOD> always takes precedence; otherwise, the method is
+		automatically set to "POST" if '--field', '--raw-field', '--input', or
+		'--graphql-file' are used. hub warns when that sends a POST request to a
+		well-known endpoint that only serves reads, such as "search/issues",
+		since the fields were most likely meant for the query string.
 
 		Use '-XGET' to force serializing fields into the query string for the GET
 		request instead of JSON body of the POST request.
@@ -35,8 +49,9 @@ var cmdApi = &Command{
 		If <VALUE> starts with "@", the rest of the value is interpreted as a
 		filename to read the value from. Use "@-" to read from standard input.
 
-		If <VALUE> is "true", "false", "null", or looks like a number, an
-		appropriate JSON type is used instead of a string.
+		If <VALUE> is "true", "false", "null", or looks like an integer, an
+		appropriate JSON type is used instead of a string. This is what makes it
+		possible to pass GraphQL variables declared as "Int" or "Boolean".
 
 		It is not possible to serialize <VALUE> as a nested JSON array or hash.
 		Instead, construct the request payload externally and pass it via
@@ -50,26 +65,180 @@ var cmdApi = &Command{
 	-f, --raw-field <KEY>=<VALUE>
 		Same as '--field', except that it allows values starting with "@", literal
 		strings "true", "false", and "null", as well as strings that look like
-		numbers.
+		numbers. The value is always sent as a string, including when used as a
+		GraphQL variable.
+
+		The meaning of '-F' (typed) and '-f' (raw string) is the same as in the
+		'gh api' command of GitHub CLI.
 
 	--input <FILE>
 		The filename to read the raw request body from. Use "-" to read from standard
 		input. Use this when you want to manually construct the request payload.
+		The body is sent as-is, byte for byte, which makes it possible to upload
+		binary files such as release assets. A body read from standard input is
+		sent without a Content-Length header, which some endpoints don't accept.
+
+	--content-type <TYPE>
+		The media type of the request body given with '--input' (default:
+		"application/json"). This is a shorthand for '--header "Content-Type:
+		<TYPE>"', which takes precedence. It can only be used with '--input'.
+
+	--graphql-file <FILE>
+		The filename to read a GraphQL query from. Use "-" to read from standard
+		input. This implies "graphql" as <ENDPOINT>, and any '--field' or
+		'--raw-field' values are sent as query variables.
 
 	-H, --header <KEY>:<VALUE>
-		Set an HTTP request header.
+		Set an HTTP request header. This takes precedence over headers configured
+		for the host in the hub configuration file. See hub(1).
+
+	--no-preview
+		Don't pick a preview media type for the "Accept" header automatically.
+
+		Some REST endpoints, such as repository topics, reactions, projects, and
+		check runs, were only available on older GitHub Enterprise versions when
+		the request asked for a preview media type. For such endpoints, hub sends
+		the "Accept" header that they require unless one was given with
+		'--header' or configured for the host.
 
 	-i, --include
 		Include HTTP response headers in the output.
 
 	-t, --flat
 		Parse response JSON and output the data in a line-based key-value format
-		suitable for use in shell scripts.
+		suitable for use in shell scripts. Responses that aren't JSON are output
+		as-is.
+
+	--template <TEMPLATE>
+		Format the JSON response using the Go template <TEMPLATE>, with the
+		decoded response as data. See <https://golang.org/pkg/text/template/> for
+		the template syntax. In addition to the built-in template functions, the
+		following are available:
+
+		json <VALUE>: render <VALUE> as JSON
+
+		pluck <FIELD> <LIST>: collect the values of <FIELD> of each object in <LIST>
+
+		join <SEPARATOR> <LIST>: join the elements of <LIST> with <SEPARATOR>
+
+		color <STYLE> <TEXT>: set the color of <TEXT> to <STYLE>, one of "red",
+		"green", "yellow", "blue", "magenta", "cyan", or "bold", if colored output
+		is enabled (see '--color')
+
+		Nothing is formatted if the response isn't JSON or is an HTTP error. This
+		can't be combined with '--flat'.
+
+	--jq <EXPR>
+		Filter the JSON response with the jq expression <EXPR> and print each
+		result on its own line. Strings are printed without quotes; other values
+		are printed as compact JSON. Only this subset of the jq language is
+		supported, and other syntax is an error:
+
+		.: the input itself
+
+		.foo, ."foo", .["foo"]: the value of a field, or null if it's missing
+
+		.[N]: the array element at index N; negative indices count from the end
+
+		.[]: every element of an array, or every value of an object
+
+		A | B: feed each result of A into B
+
+		A, B: the results of A followed by the results of B
+
+		select(COND): pass the input on only if COND is neither false nor null
+
+		length, keys, not: the jq functions of the same name
+
+		==, !=, <, <=, >, >=, and, or: comparisons and boolean logic
+
+		"string", numbers, true, false, null: literal values
+
+		Parentheses group expressions. Object values iterated with ".[]" come in
+		key order. Nothing is filtered if the response isn't JSON or is an HTTP
+		error. This can't be combined with '--flat' or '--template'.
+
+	-o, --out <FILE>
+		Write the body of a successful response to <FILE> byte for byte instead of
+		standard output. No '--flat' formatting is applied, which makes this
+		suitable for downloading binary content such as archives. Response
+		headers requested with '--include' are still written to standard output.
 
 	--color[=<WHEN>]
 		Enable colored output even if stdout is not a terminal. <WHEN> can be one
 		of "always" (default for '--color'), "never", or "auto" (default).
 
+	--silent
+		Don't output the response body (nor headers when '--include' is used) if
+		the request was not successful. The exit status is nonzero regardless.
+
+	--expect-status <STATUSES>
+		Exit with a nonzero status unless the HTTP status of the response is one
+		of the comma-separated <STATUSES>, such as "200,201", and report the
+		mismatch to standard error. An expected status counts as success even if
+		it's an error status such as "404". This can't be combined with
+		'--paginate'.
+
+	--expect-jq <EXPR>
+		Evaluate <EXPR>, a filter in the same subset of jq syntax as '--jq', on
+		the JSON response and exit with a nonzero status if it produces no
+		results or its last result is "false" or "null". The response is printed
+		as usual. An error status still makes hub exit with a nonzero status
+		unless it's listed in '--expect-status'. This can't be combined with
+		'--paginate'.
+
+		Together with '--expect-status', this lets 'hub api' serve as an
+		assertion tool in smoke tests. A failed expectation makes hub exit with
+		status 1.
+
+	--include-rate-limit-in-error
+		When the request was not successful, print a summary of the failure to
+		standard error: the HTTP status, the "message" and "documentation_url"
+		fields of the JSON response, and the rate limit values reported in the
+		"X-RateLimit-Remaining" and "X-RateLimit-Reset" response headers. This
+		helps tell rate limiting apart from other causes of "403 Forbidden".
+
+	--paginate
+		Automatically request successive pages of results by following the
+		"next" links in the "Link" response header until the last page has been
+		fetched. The output of each page is printed as soon as it arrives. This
+		only works for GET requests to REST endpoints.
+
+		As with a single request, the exit status is 22 if a page wasn't fetched
+		successfully, even if '--keep-going' went on to fetch the pages after it.
+
+	--slurp
+		When paginating, collect the pages instead of printing each one as it
+		arrives, and print a single JSON array when done: the items of all pages
+		if every page is a JSON array, or else the page bodies themselves. This
+		guarantees that the combined output is valid JSON. '--template', '--jq',
+		and '--flat' then apply to the combined array. The bodies of pages that
+		couldn't be fetched are printed to standard error instead.
+
+	--fail-fast
+		When paginating, stop at the first page that couldn't be fetched. The
+		pages received up to that point have already been printed. This is the
+		default.
+
+	--keep-going
+		When paginating, report a page that couldn't be fetched to standard error
+		and carry on with the rest of the pages. Only pages known from the "last"
+		link of an earlier response can be requested after a failure, so this
+		stops early if the first page fails or the API doesn't advertise the last
+		page.
+
+	--max-pages <N>
+		When paginating, stop after fetching <N> pages even if there are more.
+
+	--max-items <N>
+		When paginating, stop once <N> items have been received, counting the
+		elements of JSON array responses. The page that reaches the limit is cut
+		short so that exactly <N> items are printed. Pages that aren't JSON
+		arrays can't be counted and make hub exit with an error.
+
+		Reaching either limit isn't an error: hub notes on standard error that it
+		stopped early and exits with status 0.
+
 	--cache <TTL>
 		Cache successful responses to GET requests for <TTL> seconds.
 
@@ -77,6 +246,14 @@ var cmdApi = &Command{
 		requests as well. Just make sure to not use '--cache' for any GraphQL
 		mutations.
 
+	--connect-timeout <DURATION>
+		Give up if connecting to the API host takes longer than <DURATION>, such
+		as "500ms" or "3s"; a plain number is taken as seconds (default: 30s).
+		This only bounds DNS resolution and establishing the TCP connection: a
+		response that is slow to arrive once connected is still waited for. The
+		HUB_CONNECT_TIMEOUT environment variable sets the same limit for every
+		hub command.
+
 	<ENDPOINT>
 		The GitHub API endpoint to send the HTTP request to (default: "/").
 		
@@ -101,6 +278,28 @@ var cmdApi = &Command{
 		# perform a GraphQL query read from a file
 		$ hub api graphql -F query=@path/to/myquery.graphql
 
+		# same as above, passing a query variable
+		$ hub api --graphql-file path/to/myquery.graphql -F login=octocat
+
+		# list the names of open issues, one per line
+		$ hub api repos/{owner}/{repo}/issues --template '{{range .}}{{println .title}}{{end}}'
+
+		# print the logins of repository contributors separated by commas
+		$ hub api repos/{owner}/{repo}/contributors --template '{{pluck "login" . | join ", "}}'
+
+		# print the titles of open pull requests that aren't drafts
+		$ hub api repos/{owner}/{repo}/pulls --jq '.[] | select(.draft == false) | .title'
+
+		# list the names of all repository labels, across every page
+		$ hub api --paginate repos/{owner}/{repo}/labels --jq '.[].name'
+
+		# upload a binary file as an asset of the release with ID 1234
+		$ hub api --input dist/app.tgz --content-type application/gzip \
+		  'https://uploads.github.com/repos/{owner}/{repo}/releases/1234/assets?name=app.tgz'
+
+		# download a tarball of the main branch
+		$ hub api repos/{owner}/{repo}/tarball/main -o out.tgz
+
 ## See also:
 
 hub(1)
@@ -117,14 +316,63 @@ func apiCommand(cmd *Command, args *Args) {
 		path = args.GetParam(0)
 	}
 
-	method := "GET"
-	if args.Flag.HasReceived("--method") {
-		method = args.Flag.Value("--method")
-	} else if args.Flag.HasReceived("--field") || args.Flag.HasReceived("--raw-field") || args.Flag.HasReceived("--input") {
-		method = "POST"
+	graphqlFile := args.Flag.Value("--graphql-file")
+	if graphqlFile != "" {
+		if path != "" && path != "graphql" {
+			utils.Check(fmt.Errorf("Error: cannot use --graphql-file with endpoint `%s'", path))
+		}
+		path = "graphql"
+	}
+
+	hasPayload := graphqlFile != "" || args.Flag.HasReceived("--field") || args.Flag.HasReceived("--raw-field") || args.Flag.HasReceived("--input")
+	method, err := apiMethod(args.Flag.Value("--method"), hasPayload)
+	utils.Check(err)
+	if !args.Flag.HasReceived("--method") && hasPayload && isReadOnlyEndpoint(path) {
+		ui.Errorf("Warning: sending a POST request to `%s' because fields were given; use -XGET to send them in the query string instead\n", path)
 	}
 	cacheTTL := args.Flag.Int("--cache")
 
+	if args.Flag.HasReceived("--connect-timeout") {
+		timeout, err := github.ParseConnectTimeout(args.Flag.Value("--connect-timeout"))
+		if err != nil {
+			utils.Check(fmt.Errorf("Error: %s", err))
+		}
+		github.ConnectTimeout = timeout
+	}
+
+	var responseTemplate *template.Template
+	if args.Flag.HasReceived("--template") {
+		if args.Flag.Bool("--flat") {
+			utils.Check(fmt.Errorf("Error: the `--template' and `--flat' flags can't be used together"))
+		}
+		var err error
+		responseTemplate, err = template.New("api").Funcs(apiTemplateFuncs(colorizeOutput(args.Flag.HasReceived("--color"), args.Flag.Value("--color")))).Parse(args.Flag.Value("--template"))
+		if err != nil {
+			utils.Check(fmt.Errorf("Error: invalid template: %s", err))
+		}
+	}
+
+	var expectFilter jqFilter
+	if args.Flag.HasReceived("--expect-jq") {
+		var err error
+		expectFilter, err = parseJQ(args.Flag.Value("--expect-jq"))
+		if err != nil {
+			utils.Check(fmt.Errorf("Error: invalid --expect-jq expression: %s", err))
+		}
+	}
+
+	var jqFilter jqFilter
+	if args.Flag.HasReceived("--jq") {
+		if args.Flag.Bool("--flat") || args.Flag.HasReceived("--template") {
+			utils.Check(fmt.Errorf("Error: the `--jq' flag can't be combined with `--flat' or `--template'"))
+		}
+		var err error
+		jqFilter, err = parseJQ(args.Flag.Value("--jq"))
+		if err != nil {
+			utils.Check(fmt.Errorf("Error: invalid --jq expression: %s", err))
+		}
+	}
+
 	params := make(map[string]interface{})
 	for _, val := range args.Flag.AllValues("--field") {
 		parts := strings.SplitN(val, "=", 2)
@@ -139,11 +387,15 @@ func apiCommand(cmd *Command, args *Args) {
 		}
 	}
 
+	if graphqlFile != "" {
+		params["query"] = readGraphQLFile(graphqlFile)
+	}
+
 	headers := make(map[string]string)
 	for _, val := range args.Flag.AllValues("--header") {
 		parts := strings.SplitN(val, ":", 2)
 		if len(parts) >= 2 {
-			headers[parts[0]] = strings.TrimLeft(parts[1], " ")
+			headers[http.CanonicalHeaderKey(parts[0])] = strings.TrimLeft(parts[1], " ")
 		}
 	}
 
@@ -187,6 +439,19 @@ func apiCommand(cmd *Command, args *Args) {
 		path = strings.Replace(path, "{repo}", repo, 1)
 	}
 
+	if args.Flag.HasReceived("--content-type") && !args.Flag.HasReceived("--input") {
+		utils.Check(fmt.Errorf("Error: --content-type can only be used with --input"))
+	}
+	if contentType := args.Flag.Value("--content-type"); contentType != "" && headers["Content-Type"] == "" {
+		headers["Content-Type"] = contentType
+	}
+
+	if path != "graphql" && !args.Flag.Bool("--no-preview") && headers["Accept"] == "" && !hasConfiguredAccept(host) {
+		if accept := previewAcceptHeader(path); accept != "" {
+			headers["Accept"] = accept
+		}
+	}
+
 	var body interface{}
 	if args.Flag.HasReceived("--input") {
 		fn := args.Flag.Value("--input")
@@ -202,40 +467,494 @@ func apiCommand(cmd *Command, args *Args) {
 		body = params
 	}
 
-	gh := github.NewClient(host)
-	response, err := gh.GenericAPIRequest(method, path, body, headers, cacheTTL)
-	utils.Check(err)
+	paginate := args.Flag.Bool("--paginate")
+	keepGoing := args.Flag.Bool("--keep-going")
+	if keepGoing && args.Flag.Bool("--fail-fast") {
+		utils.Check(fmt.Errorf("Error: the `--fail-fast' and `--keep-going' flags can't be used together"))
+	}
+	if paginate && method != "GET" {
+		utils.Check(fmt.Errorf("Error: --paginate can only be used with GET requests"))
+	}
+	expectedStatuses := []int{}
+	for _, value := range commaSeparated(args.Flag.AllValues("--expect-status")) {
+		status, err := strconv.Atoi(value)
+		if err != nil || status < 100 || status > 599 {
+			utils.Check(fmt.Errorf("Error: invalid --expect-status `%s'; expected HTTP status codes such as 200", value))
+		}
+		expectedStatuses = append(expectedStatuses, status)
+	}
+	expectations := len(expectedStatuses) > 0 || expectFilter != nil
+	if expectations && paginate {
+		utils.Check(fmt.Errorf("Error: --expect-status and --expect-jq can't be used with --paginate"))
+	}
+
+	slurp := args.Flag.Bool("--slurp")
+	if slurp && !paginate {
+		utils.Check(fmt.Errorf("Error: --slurp can only be used with --paginate"))
+	}
+	if slurp && args.Flag.Bool("--include") {
+		utils.Check(fmt.Errorf("Error: the `--slurp' and `--include' flags can't be used together"))
+	}
 
+	maxPages := parseAPILimit(args, "--max-pages")
+	maxItems := parseAPILimit(args, "--max-items")
+	if (maxPages > 0 || maxItems > 0) && !paginate {
+		utils.Check(fmt.Errorf("Error: --max-pages and --max-items can only be used with --paginate"))
+	}
+
+	gh := github.NewClient(host)
 	args.NoForward()
 
 	out := ui.Stdout
 	colorize := colorizeOutput(args.Flag.HasReceived("--color"), args.Flag.Value("--color"))
-	success := response.StatusCode < 300
-	parseJSON := args.Flag.Bool("--flat")
+	outFile := args.Flag.Value("--out")
+	var file *os.File
+	failed := false
+	lastPage := ""
+	slurped := []json.RawMessage{}
+	itemCount := 0
 
-	if !success {
+	var requestBody interface{} = body
+	for page := 1; ; page++ {
+		if maxPages > 0 && page > maxPages {
+			ui.Errorf("Stopped after reaching --max-pages %d\n", maxPages)
+			break
+		}
+		if maxItems > 0 && itemCount >= maxItems {
+			ui.Errorf("Stopped after reaching --max-items %d\n", maxItems)
+			break
+		}
+
+		response, err := gh.GenericAPIRequest(method, path, requestBody, headers, cacheTTL)
+		if err != nil && paginate && keepGoing && page > 1 {
+			ui.Errorf("Error fetching page %d: %s\n", page, err)
+			failed = true
+			if path = nextKnownPage(path, lastPage); path == "" {
+				break
+			}
+			continue
+		}
+		utils.Check(err)
+
+		success := response.StatusCode < 300
 		jsonType, _ := regexp.MatchString(`[/+]json(?:;|$)`, response.Header.Get("Content-Type"))
-		parseJSON = parseJSON && jsonType
-	}
+		parseJSON := args.Flag.Bool("--flat") && jsonType
+		pageTemplate := responseTemplate
+		pageFilter := jqFilter
+
+		var bodyOut io.Writer = out
+		if outFile != "" && success {
+			if file == nil {
+				file, err = os.Create(outFile)
+				utils.Check(err)
+				defer file.Close()
+			}
+			bodyOut = file
+			parseJSON = false
+			pageTemplate = nil
+			pageFilter = nil
+		}
+		// error bodies are printed as they are so that the API's message shows
+		if !jsonType || !success {
+			pageTemplate = nil
+			pageFilter = nil
+		}
 
-	if args.Flag.Bool("--include") {
-		fmt.Fprintf(out, "%s %s\r\n", response.Proto, response.Status)
-		response.Header.Write(out)
-		fmt.Fprintf(out, "\r\n")
+		var responseBody io.Reader = response.Body
+		var bodyData []byte
+		if !success || expectFilter != nil || maxItems > 0 {
+			bodyData, err = ioutil.ReadAll(response.Body)
+			utils.Check(err)
+			responseBody = bytes.NewReader(bodyData)
+		}
+		if maxItems > 0 && success {
+			items := []json.RawMessage{}
+			if !jsonType || json.Unmarshal(bodyData, &items) != nil {
+				utils.Check(fmt.Errorf("Error: --max-items requires JSON array responses, but page %d isn't one", page))
+			}
+			if len(items) > maxItems-itemCount {
+				items = items[:maxItems-itemCount]
+				bodyData, err = json.Marshal(items)
+				utils.Check(err)
+				responseBody = bytes.NewReader(bodyData)
+			}
+			itemCount += len(items)
+		}
+		if !success {
+			if args.Flag.Bool("--include-rate-limit-in-error") {
+				ui.Errorf("%s", apiErrorSummary(response.Status, response.Header, bodyData, jsonType))
+			}
+		}
+
+		if slurp && success {
+			if !jsonType {
+				utils.Check(fmt.Errorf("Error: --slurp requires JSON responses, but page %d is %s", page, response.Header.Get("Content-Type")))
+			}
+			var pageBody json.RawMessage
+			utils.Check(json.NewDecoder(responseBody).Decode(&pageBody))
+			slurped = append(slurped, pageBody)
+		} else if success || !args.Flag.Bool("--silent") {
+			if args.Flag.Bool("--include") {
+				fmt.Fprintf(out, "%s %s\r\n", response.Proto, response.Status)
+				response.Header.Write(out)
+				fmt.Fprintf(out, "\r\n")
+			}
+			if slurp {
+				bodyOut = ui.Stderr
+			}
+			utils.Check(printAPIResponseBody(bodyOut, responseBody, pageTemplate, pageFilter, parseJSON, colorize))
+		}
+		response.Body.Close()
+
+		if !paginate {
+			failed = !success
+			if expectations {
+				utils.Check(checkAPIExpectations(response.StatusCode, response.Status, bodyData, expectedStatuses, expectFilter, args.Flag.Value("--expect-jq")))
+				// only an explicitly expected status makes an HTTP error a success
+				if len(expectedStatuses) > 0 {
+					failed = false
+				}
+			}
+			break
+		}
+
+		requestBody = nil
+		if success {
+			if last := response.Link("last"); last != "" {
+				lastPage = last
+			}
+			path = response.Link("next")
+		} else {
+			ui.Errorf("Error fetching page %d: HTTP %s\n", page, response.Status)
+			failed = true
+			if !keepGoing {
+				break
+			}
+			path = nextKnownPage(path, lastPage)
+		}
+		if path == "" {
+			break
+		}
 	}
 
-	if parseJSON {
-		utils.JSONPath(out, response.Body, colorize)
-	} else {
-		io.Copy(out, response.Body)
+	if slurp {
+		combined, err := slurpPages(slurped)
+		utils.Check(err)
+		if file != nil {
+			_, err = file.Write(combined)
+			utils.Check(err)
+		} else {
+			utils.Check(printAPIResponseBody(out, bytes.NewReader(combined), responseTemplate, jqFilter, args.Flag.Bool("--flat"), colorize))
+		}
 	}
-	response.Body.Close()
 
-	if !success {
+	if failed {
+		if file != nil {
+			file.Close()
+		}
 		os.Exit(22)
 	}
 }
 
+// parseAPILimit reads the positive number given to a flag such as
+// '--max-pages', or 0 if the flag wasn't passed
+func parseAPILimit(args *Args, flag string) int {
+	if !args.Flag.HasReceived(flag) {
+		return 0
+	}
+	value := args.Flag.Value(flag)
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 1 {
+		utils.Check(fmt.Errorf("Error: invalid %s `%s'; expected a positive number", flag, value))
+	}
+	return limit
+}
+
+// checkAPIExpectations returns an error describing how a response didn't meet
+// the '--expect-status' and '--expect-jq' expectations
+func checkAPIExpectations(statusCode int, status string, body []byte, statuses []int, filter jqFilter, expr string) error {
+	if len(statuses) > 0 {
+		expected := false
+		names := []string{}
+		for _, s := range statuses {
+			expected = expected || s == statusCode
+			names = append(names, strconv.Itoa(s))
+		}
+		if !expected {
+			return fmt.Errorf("Error: expected HTTP status %s, got %s", strings.Join(names, " or "), status)
+		}
+	}
+
+	if filter != nil {
+		var data interface{}
+		decoder := json.NewDecoder(bytes.NewReader(body))
+		decoder.UseNumber()
+		if err := decoder.Decode(&data); err != nil {
+			return fmt.Errorf("Error: --expect-jq: could not parse JSON response: %s", err)
+		}
+		results, err := filter(data)
+		if err != nil {
+			return fmt.Errorf("Error: --expect-jq: %s", err)
+		}
+		if len(results) == 0 {
+			return fmt.Errorf("Error: expected `%s' to be true, but it produced no results", expr)
+		}
+		if last := results[len(results)-1]; last == nil || last == false {
+			encoded, _ := json.Marshal(last)
+			return fmt.Errorf("Error: expected `%s' to be true, got %s", expr, encoded)
+		}
+	}
+	return nil
+}
+
+func printAPIResponseBody(out io.Writer, body io.Reader, tmpl *template.Template, filter jqFilter, parseJSON, colorize bool) error {
+	if tmpl != nil {
+		return renderAPITemplate(out, tmpl, body)
+	} else if filter != nil {
+		return printJQResults(out, filter, body)
+	} else if parseJSON {
+		utils.JSONPath(out, body, colorize)
+		return nil
+	}
+	_, err := io.Copy(out, body)
+	return err
+}
+
+// slurpPages combines the bodies of paginated responses into a single JSON
+// array. If every page is an array, their items get concatenated.
+func slurpPages(pages []json.RawMessage) ([]byte, error) {
+	items := []json.RawMessage{}
+	for _, page := range pages {
+		pageItems := []json.RawMessage{}
+		if err := json.Unmarshal(page, &pageItems); err != nil {
+			return json.Marshal(pages)
+		}
+		items = append(items, pageItems...)
+	}
+	return json.Marshal(items)
+}
+
+var apiMethods = []string{"GET", "HEAD", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+
+// apiMethod resolves the HTTP method from the value of '--method', in any
+// case, or infers it from whether a request payload was given
+func apiMethod(flagMethod string, hasPayload bool) (string, error) {
+	if flagMethod == "" {
+		if hasPayload {
+			return "POST", nil
+		}
+		return "GET", nil
+	}
+	method := strings.ToUpper(flagMethod)
+	for _, m := range apiMethods {
+		if m == method {
+			return method, nil
+		}
+	}
+	return "", fmt.Errorf("Error: invalid --method `%s'; expected one of %s", flagMethod, strings.Join(apiMethods, ", "))
+}
+
+// apiReadOnlyEndpoints lists well-known REST endpoints that don't accept POST
+// requests, matched like apiPreviews
+var apiReadOnlyEndpoints = []*regexp.Regexp{
+	regexp.MustCompile(`^search/`),
+	regexp.MustCompile(`^(rate_limit|user|emojis|meta)$`),
+	regexp.MustCompile(`^(users|orgs)/[^/]+$`),
+	regexp.MustCompile(`^repos/[^/]+/[^/]+$`),
+	regexp.MustCompile(`^repos/[^/]+/[^/]+/(commits|contributors|languages|tags|branches|stargazers|compare/.+)$`),
+}
+
+// isReadOnlyEndpoint reports whether path is known to only serve reads
+func isReadOnlyEndpoint(path string) bool {
+	path = apiEndpointPath(path)
+	for _, pattern := range apiReadOnlyEndpoints {
+		if pattern.MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// apiPreviews lists the REST endpoints that required a preview media type in
+// the "Accept" header on older GitHub Enterprise versions. Patterns are matched
+// against the endpoint path relative to the API root, without a query string.
+var apiPreviews = []struct {
+	pattern *regexp.Regexp
+	accept  string
+}{
+	{regexp.MustCompile(`^repos/[^/]+/[^/]+/topics$`), "application/vnd.github.mercy-preview+json"},
+	{regexp.MustCompile(`/reactions(/\d+)?$`), "application/vnd.github.squirrel-girl-preview+json"},
+	{regexp.MustCompile(`^((repos/[^/]+/[^/]+|orgs/[^/]+|users/[^/]+)/)?projects(/|$)`), "application/vnd.github.inertia-preview+json"},
+	{regexp.MustCompile(`^repos/[^/]+/[^/]+/issues/\d+/timeline$`), "application/vnd.github.mockingbird-preview+json"},
+	{regexp.MustCompile(`^repos/[^/]+/[^/]+/(.+/)?check-(runs|suites)(/|$)`), "application/vnd.github.antiope-preview+json"},
+	{regexp.MustCompile(`^repos/[^/]+/[^/]+/generate$`), "application/vnd.github.baptiste-preview+json"},
+}
+
+var apiRootRe = regexp.MustCompile(`^(https?://[^/]+)?/*(api/v3/+)?`)
+
+// apiEndpointPath returns path relative to the API root, without a query
+// string
+func apiEndpointPath(path string) string {
+	path = apiRootRe.ReplaceAllString(path, "")
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path = path[:i]
+	}
+	return strings.TrimSuffix(path, "/")
+}
+
+// previewAcceptHeader returns the preview media type required by the endpoint
+// at path, if any
+func previewAcceptHeader(path string) string {
+	path = apiEndpointPath(path)
+	for _, preview := range apiPreviews {
+		if preview.pattern.MatchString(path) {
+			return preview.accept
+		}
+	}
+	return ""
+}
+
+func hasConfiguredAccept(hostname string) bool {
+	if host := github.CurrentConfig().Find(hostname); host != nil {
+		for name := range host.Headers {
+			if http.CanonicalHeaderKey(name) == "Accept" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// nextKnownPage returns the URL of the page after failedURL, provided that
+// the "last" link of an earlier response shows that there is one
+func nextKnownPage(failedURL, lastURL string) string {
+	if lastURL == "" {
+		return ""
+	}
+	failed, err := url.Parse(failedURL)
+	if err != nil {
+		return ""
+	}
+	last, err := url.Parse(lastURL)
+	if err != nil {
+		return ""
+	}
+
+	current := 1
+	if page := failed.Query().Get("page"); page != "" {
+		if current, err = strconv.Atoi(page); err != nil {
+			return ""
+		}
+	}
+	lastNumber, err := strconv.Atoi(last.Query().Get("page"))
+	if err != nil || current >= lastNumber {
+		return ""
+	}
+
+	query := last.Query()
+	query.Set("page", strconv.Itoa(current+1))
+	last.RawQuery = query.Encode()
+	return last.String()
+}
+
+func renderAPITemplate(out io.Writer, tmpl *template.Template, body io.Reader) error {
+	var data interface{}
+	decoder := json.NewDecoder(body)
+	decoder.UseNumber()
+	if err := decoder.Decode(&data); err != nil {
+		return fmt.Errorf("Error: could not parse JSON response: %s", err)
+	}
+	if err := tmpl.Execute(out, data); err != nil {
+		return fmt.Errorf("Error: could not render template: %s", err)
+	}
+	return nil
+}
+
+var apiTemplateColors = map[string]string{
+	"red":     "31",
+	"green":   "32",
+	"yellow":  "33",
+	"blue":    "34",
+	"magenta": "35",
+	"cyan":    "36",
+	"bold":    "1",
+}
+
+// apiTemplateFuncs returns the helper functions available to '--template'
+func apiTemplateFuncs(colorize bool) template.FuncMap {
+	return template.FuncMap{
+		"json": func(value interface{}) (string, error) {
+			data, err := json.Marshal(value)
+			return string(data), err
+		},
+		"pluck": func(field string, list []interface{}) []interface{} {
+			values := []interface{}{}
+			for _, item := range list {
+				if object, ok := item.(map[string]interface{}); ok {
+					values = append(values, object[field])
+				}
+			}
+			return values
+		},
+		"join": func(separator string, list []interface{}) string {
+			items := make([]string, len(list))
+			for i, item := range list {
+				items[i] = fmt.Sprint(item)
+			}
+			return strings.Join(items, separator)
+		},
+		"color": func(style string, text interface{}) (string, error) {
+			code, ok := apiTemplateColors[style]
+			if !ok {
+				return "", fmt.Errorf("unknown color `%s'", style)
+			}
+			if !colorize {
+				return fmt.Sprint(text), nil
+			}
+			return fmt.Sprintf("\033[%sm%v\033[m", code, text), nil
+		},
+	}
+}
+
+// apiErrorSummary describes a failed API response in a human-readable way,
+// including the rate limit status so that it's apparent whether the failure
+// was due to rate limiting
+func apiErrorSummary(status string, header http.Header, body []byte, jsonType bool) string {
+	summary := fmt.Sprintf("Error: HTTP %s\n", status)
+
+	if jsonType {
+		errorInfo := struct {
+			Message          string `json:"message"`
+			DocumentationURL string `json:"documentation_url"`
+		}{}
+		if json.Unmarshal(body, &errorInfo) == nil {
+			if errorInfo.Message != "" {
+				summary += fmt.Sprintf("Message: %s\n", errorInfo.Message)
+			}
+			if errorInfo.DocumentationURL != "" {
+				summary += fmt.Sprintf("Documentation: %s\n", errorInfo.DocumentationURL)
+			}
+		}
+	}
+
+	if remaining := header.Get("X-RateLimit-Remaining"); remaining != "" {
+		summary += fmt.Sprintf("Rate limit remaining: %s", remaining)
+		if limit := header.Get("X-RateLimit-Limit"); limit != "" {
+			summary += fmt.Sprintf(" of %s", limit)
+		}
+		summary += "\n"
+	}
+	if reset := header.Get("X-RateLimit-Reset"); reset != "" {
+		if resetUnix, err := strconv.ParseInt(reset, 10, 64); err == nil {
+			summary += fmt.Sprintf("Rate limit resets at: %s\n", time.Unix(resetUnix, 0).UTC().Format(time.RFC3339))
+		} else {
+			summary += fmt.Sprintf("Rate limit resets at: %s\n", reset)
+		}
+	}
+
+	return summary
+}
+
 const (
 	trueVal  = "true"
 	falseVal = "false"
@@ -272,6 +991,686 @@ func readFile(file string) (content []byte) {
 	return
 }
 
+func readGraphQLFile(file string) string {
+	var content []byte
+	var err error
+	if file == "-" {
+		content, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		content, err = ioutil.ReadFile(file)
+	}
+	if err != nil {
+		utils.Check(fmt.Errorf("Error: unable to read GraphQL query from `%s': %s", file, err))
+	}
+
+	query := string(content)
+	if strings.TrimSpace(query) == "" {
+		utils.Check(fmt.Errorf("Error: GraphQL query in `%s' is empty", file))
+	}
+	return query
+}
+
 func quote(s string) string {
 	return fmt.Sprintf("%q", s)
 }
+
+// jqFilter is a compiled '--jq' expression. Like in jq, a filter turns one
+// input into any number of outputs.
+type jqFilter func(input interface{}) ([]interface{}, error)
+
+func printJQResults(out io.Writer, filter jqFilter, body io.Reader) error {
+	var data interface{}
+	decoder := json.NewDecoder(body)
+	decoder.UseNumber()
+	if err := decoder.Decode(&data); err != nil {
+		return fmt.Errorf("Error: could not parse JSON response: %s", err)
+	}
+
+	results, err := filter(data)
+	if err != nil {
+		return fmt.Errorf("Error: --jq: %s", err)
+	}
+	for _, result := range results {
+		if s, ok := result.(string); ok {
+			fmt.Fprintln(out, s)
+			continue
+		}
+		encoded, err := json.Marshal(result)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, string(encoded))
+	}
+	return nil
+}
+
+type jqToken struct {
+	kind   string
+	value  string
+	pos    int
+	spaced bool
+}
+
+// text is how the token appeared in the expression, for error messages
+func (t jqToken) text() string {
+	switch t.kind {
+	case "field":
+		return "." + t.value
+	case "string":
+		return strconv.Quote(t.value)
+	}
+	return t.value
+}
+
+func isJQIdentChar(c byte, first bool) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (!first && c >= '0' && c <= '9')
+}
+
+// lexJQ splits a jq expression into tokens. A dot directly followed by a name
+// is a single "field" token.
+func lexJQ(expr string) ([]jqToken, error) {
+	tokens := []jqToken{}
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+		case c == '.':
+			j := i + 1
+			for j < len(expr) && isJQIdentChar(expr[j], j == i+1) {
+				j++
+			}
+			if j > i+1 {
+				tokens = append(tokens, jqToken{kind: "field", value: expr[i+1 : j], pos: i})
+			} else {
+				tokens = append(tokens, jqToken{kind: ".", value: ".", pos: i})
+			}
+			i = j
+		case isJQIdentChar(c, true):
+			j := i
+			for j < len(expr) && isJQIdentChar(expr[j], j == i) {
+				j++
+			}
+			tokens = append(tokens, jqToken{kind: "ident", value: expr[i:j], pos: i})
+			i = j
+		case c == '"':
+			j := i + 1
+			for j < len(expr) && expr[j] != '"' {
+				if expr[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(expr) {
+				return nil, fmt.Errorf("unterminated string at position %d", i+1)
+			}
+			s, err := strconv.Unquote(expr[i : j+1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid string at position %d", i+1)
+			}
+			tokens = append(tokens, jqToken{kind: "string", value: s, pos: i})
+			i = j + 1
+		case c == '-' || (c >= '0' && c <= '9'):
+			j := i + 1
+			for j < len(expr) && ((expr[j] >= '0' && expr[j] <= '9') || expr[j] == '.') {
+				j++
+			}
+			if _, err := strconv.ParseFloat(expr[i:j], 64); err != nil {
+				return nil, fmt.Errorf("unsupported syntax `%s' at position %d", expr[i:j], i+1)
+			}
+			tokens = append(tokens, jqToken{kind: "number", value: expr[i:j], pos: i})
+			i = j
+		case strings.IndexByte("[]()|,", c) >= 0:
+			tokens = append(tokens, jqToken{kind: string(c), value: string(c), pos: i})
+			i++
+		case strings.IndexByte("=!<>", c) >= 0:
+			op := string(c)
+			if i+1 < len(expr) && expr[i+1] == '=' {
+				op += "="
+			}
+			if op == "=" || op == "!" {
+				return nil, fmt.Errorf("unsupported syntax `%s' at position %d", op, i+1)
+			}
+			tokens = append(tokens, jqToken{kind: "op", value: op, pos: i})
+			i += len(op)
+		default:
+			return nil, fmt.Errorf("unsupported syntax `%c' at position %d", c, i+1)
+		}
+	}
+	for i := range tokens {
+		if pos := tokens[i].pos; pos > 0 && strings.IndexByte(" \t\n", expr[pos-1]) >= 0 {
+			tokens[i].spaced = true
+		}
+	}
+	return tokens, nil
+}
+
+type jqParser struct {
+	tokens []jqToken
+	pos    int
+}
+
+func parseJQ(expr string) (jqFilter, error) {
+	tokens, err := lexJQ(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &jqParser{tokens: tokens}
+	filter, err := p.parsePipe()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t != nil {
+		return nil, fmt.Errorf("unexpected `%s' at position %d", t.text(), t.pos+1)
+	}
+	return filter, nil
+}
+
+func (p *jqParser) peek() *jqToken {
+	if p.pos < len(p.tokens) {
+		return &p.tokens[p.pos]
+	}
+	return nil
+}
+
+func (p *jqParser) accept(kind, value string) bool {
+	if t := p.peek(); t != nil && t.kind == kind && t.value == value {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *jqParser) expect(kind string) (jqToken, error) {
+	t := p.peek()
+	if t == nil {
+		return jqToken{}, fmt.Errorf("unexpected end of expression, expected `%s'", kind)
+	}
+	if t.kind != kind {
+		return jqToken{}, fmt.Errorf("unexpected `%s' at position %d, expected `%s'", t.text(), t.pos+1, kind)
+	}
+	p.pos++
+	return *t, nil
+}
+
+func (p *jqParser) parsePipe() (jqFilter, error) {
+	left, err := p.parseComma()
+	for err == nil && p.accept("|", "|") {
+		var right jqFilter
+		if right, err = p.parseComma(); err == nil {
+			left = jqPipe(left, right)
+		}
+	}
+	return left, err
+}
+
+func (p *jqParser) parseComma() (jqFilter, error) {
+	left, err := p.parseOr()
+	for err == nil && p.accept(",", ",") {
+		var right jqFilter
+		if right, err = p.parseOr(); err == nil {
+			left = jqComma(left, right)
+		}
+	}
+	return left, err
+}
+
+func (p *jqParser) parseOr() (jqFilter, error) {
+	left, err := p.parseAnd()
+	for err == nil && p.accept("ident", "or") {
+		var right jqFilter
+		if right, err = p.parseAnd(); err == nil {
+			left = jqLogic(left, right, true)
+		}
+	}
+	return left, err
+}
+
+func (p *jqParser) parseAnd() (jqFilter, error) {
+	left, err := p.parseComparison()
+	for err == nil && p.accept("ident", "and") {
+		var right jqFilter
+		if right, err = p.parseComparison(); err == nil {
+			left = jqLogic(left, right, false)
+		}
+	}
+	return left, err
+}
+
+func (p *jqParser) parseComparison() (jqFilter, error) {
+	left, err := p.parsePostfix()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t != nil && t.kind == "op" {
+		p.pos++
+		right, err := p.parsePostfix()
+		if err != nil {
+			return nil, err
+		}
+		return jqCompare(t.value, left, right), nil
+	}
+	return left, nil
+}
+
+func (p *jqParser) parsePostfix() (jqFilter, error) {
+	filter, err := p.parsePrimary()
+	for err == nil {
+		// path suffixes such as ".foo" and "[0]" must directly follow what they
+		// apply to
+		t := p.peek()
+		if t == nil || t.spaced {
+			break
+		}
+		switch {
+		case t.kind == "field":
+			p.pos++
+			filter = jqPipe(filter, jqField(t.value))
+		case t.kind == "[":
+			var index jqFilter
+			if index, err = p.parseBracket(); err == nil {
+				filter = jqPipe(filter, index)
+			}
+		case t.kind == "." && p.pos+1 < len(p.tokens) && (p.tokens[p.pos+1].kind == "[" || p.tokens[p.pos+1].kind == "string"):
+			p.pos++
+			if p.peek().kind == "string" {
+				filter = jqPipe(filter, jqField(p.peek().value))
+				p.pos++
+			}
+		default:
+			return filter, nil
+		}
+	}
+	return filter, err
+}
+
+func (p *jqParser) parseBracket() (jqFilter, error) {
+	if _, err := p.expect("["); err != nil {
+		return nil, err
+	}
+	if p.accept("]", "]") {
+		return jqIterate, nil
+	}
+
+	t := p.peek()
+	var filter jqFilter
+	switch {
+	case t != nil && t.kind == "number":
+		n, err := strconv.Atoi(t.value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid index `%s' at position %d", t.value, t.pos+1)
+		}
+		filter = jqIndex(n)
+	case t != nil && t.kind == "string":
+		filter = jqField(t.value)
+	case t != nil:
+		return nil, fmt.Errorf("unsupported index `%s' at position %d", t.text(), t.pos+1)
+	default:
+		return nil, fmt.Errorf("unexpected end of expression, expected `]'")
+	}
+	p.pos++
+
+	if _, err := p.expect("]"); err != nil {
+		return nil, err
+	}
+	return filter, nil
+}
+
+func (p *jqParser) parsePrimary() (jqFilter, error) {
+	t := p.peek()
+	if t == nil {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	p.pos++
+
+	switch t.kind {
+	case ".":
+		if next := p.peek(); next != nil && next.kind == "string" {
+			p.pos++
+			return jqField(next.value), nil
+		}
+		return jqIdentity, nil
+	case "field":
+		return jqField(t.value), nil
+	case "string":
+		return jqLiteral(t.value), nil
+	case "number":
+		return jqLiteral(json.Number(t.value)), nil
+	case "(":
+		filter, err := p.parsePipe()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return filter, nil
+	case "ident":
+		switch t.value {
+		case "true":
+			return jqLiteral(true), nil
+		case "false":
+			return jqLiteral(false), nil
+		case "null":
+			return jqLiteral(nil), nil
+		case "length":
+			return jqLength, nil
+		case "keys":
+			return jqKeys, nil
+		case "not":
+			return jqNot, nil
+		case "select":
+			if _, err := p.expect("("); err != nil {
+				return nil, err
+			}
+			cond, err := p.parsePipe()
+			if err != nil {
+				return nil, err
+			}
+			if _, err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			return jqSelect(cond), nil
+		}
+		return nil, fmt.Errorf("unsupported function `%s' at position %d", t.value, t.pos+1)
+	}
+	return nil, fmt.Errorf("unexpected `%s' at position %d", t.text(), t.pos+1)
+}
+
+func jqIdentity(input interface{}) ([]interface{}, error) {
+	return []interface{}{input}, nil
+}
+
+func jqLiteral(value interface{}) jqFilter {
+	return func(interface{}) ([]interface{}, error) {
+		return []interface{}{value}, nil
+	}
+}
+
+func jqField(name string) jqFilter {
+	return func(input interface{}) ([]interface{}, error) {
+		switch v := input.(type) {
+		case nil:
+			return []interface{}{nil}, nil
+		case map[string]interface{}:
+			return []interface{}{v[name]}, nil
+		default:
+			return nil, fmt.Errorf("cannot index %s with \"%s\"", jqTypeName(input), name)
+		}
+	}
+}
+
+func jqIndex(n int) jqFilter {
+	return func(input interface{}) ([]interface{}, error) {
+		switch v := input.(type) {
+		case nil:
+			return []interface{}{nil}, nil
+		case []interface{}:
+			i := n
+			if i < 0 {
+				i += len(v)
+			}
+			if i < 0 || i >= len(v) {
+				return []interface{}{nil}, nil
+			}
+			return []interface{}{v[i]}, nil
+		default:
+			return nil, fmt.Errorf("cannot index %s with a number", jqTypeName(input))
+		}
+	}
+}
+
+func jqIterate(input interface{}) ([]interface{}, error) {
+	switch v := input.(type) {
+	case []interface{}:
+		return v, nil
+	case map[string]interface{}:
+		values := []interface{}{}
+		for _, key := range jqSortedKeys(v) {
+			values = append(values, v[key])
+		}
+		return values, nil
+	default:
+		return nil, fmt.Errorf("cannot iterate over %s", jqTypeName(input))
+	}
+}
+
+func jqPipe(left, right jqFilter) jqFilter {
+	return func(input interface{}) ([]interface{}, error) {
+		inner, err := left(input)
+		if err != nil {
+			return nil, err
+		}
+		results := []interface{}{}
+		for _, value := range inner {
+			outputs, err := right(value)
+			if err != nil {
+				return nil, err
+			}
+			results = append(results, outputs...)
+		}
+		return results, nil
+	}
+}
+
+func jqComma(left, right jqFilter) jqFilter {
+	return func(input interface{}) ([]interface{}, error) {
+		first, err := left(input)
+		if err != nil {
+			return nil, err
+		}
+		second, err := right(input)
+		if err != nil {
+			return nil, err
+		}
+		return append(first, second...), nil
+	}
+}
+
+func jqSelect(cond jqFilter) jqFilter {
+	return func(input interface{}) ([]interface{}, error) {
+		outputs, err := cond(input)
+		if err != nil {
+			return nil, err
+		}
+		results := []interface{}{}
+		for _, output := range outputs {
+			if jqTruthy(output) {
+				results = append(results, input)
+			}
+		}
+		return results, nil
+	}
+}
+
+func jqLogic(left, right jqFilter, isOr bool) jqFilter {
+	return func(input interface{}) ([]interface{}, error) {
+		lefts, err := left(input)
+		if err != nil {
+			return nil, err
+		}
+		results := []interface{}{}
+		for _, l := range lefts {
+			if jqTruthy(l) == isOr {
+				results = append(results, isOr)
+				continue
+			}
+			rights, err := right(input)
+			if err != nil {
+				return nil, err
+			}
+			for _, r := range rights {
+				results = append(results, jqTruthy(r))
+			}
+		}
+		return results, nil
+	}
+}
+
+func jqCompare(op string, left, right jqFilter) jqFilter {
+	return func(input interface{}) ([]interface{}, error) {
+		lefts, err := left(input)
+		if err != nil {
+			return nil, err
+		}
+		rights, err := right(input)
+		if err != nil {
+			return nil, err
+		}
+		results := []interface{}{}
+		for _, r := range rights {
+			for _, l := range lefts {
+				c := jqCompareValues(l, r)
+				var result bool
+				switch op {
+				case "==":
+					result = c == 0
+				case "!=":
+					result = c != 0
+				case "<":
+					result = c < 0
+				case "<=":
+					result = c <= 0
+				case ">":
+					result = c > 0
+				case ">=":
+					result = c >= 0
+				}
+				results = append(results, result)
+			}
+		}
+		return results, nil
+	}
+}
+
+func jqLength(input interface{}) ([]interface{}, error) {
+	switch v := input.(type) {
+	case nil:
+		return []interface{}{0}, nil
+	case string:
+		return []interface{}{len([]rune(v))}, nil
+	case []interface{}:
+		return []interface{}{len(v)}, nil
+	case map[string]interface{}:
+		return []interface{}{len(v)}, nil
+	case json.Number:
+		f, _ := v.Float64()
+		if f < 0 {
+			f = -f
+		}
+		return []interface{}{f}, nil
+	default:
+		return nil, fmt.Errorf("%s has no length", jqTypeName(input))
+	}
+}
+
+func jqKeys(input interface{}) ([]interface{}, error) {
+	switch v := input.(type) {
+	case map[string]interface{}:
+		keys := []interface{}{}
+		for _, key := range jqSortedKeys(v) {
+			keys = append(keys, key)
+		}
+		return []interface{}{keys}, nil
+	case []interface{}:
+		indices := []interface{}{}
+		for i := range v {
+			indices = append(indices, i)
+		}
+		return []interface{}{indices}, nil
+	default:
+		return nil, fmt.Errorf("%s has no keys", jqTypeName(input))
+	}
+}
+
+func jqNot(input interface{}) ([]interface{}, error) {
+	return []interface{}{!jqTruthy(input)}, nil
+}
+
+func jqTruthy(value interface{}) bool {
+	return value != nil && value != false
+}
+
+func jqSortedKeys(object map[string]interface{}) []string {
+	keys := []string{}
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func jqTypeName(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number, int, float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// jqCompareValues orders values the way jq does: null, false, true, numbers,
+// strings, arrays, and then objects
+func jqCompareValues(a, b interface{}) int {
+	rank := func(value interface{}) int {
+		switch v := value.(type) {
+		case nil:
+			return 0
+		case bool:
+			if v {
+				return 2
+			}
+			return 1
+		case json.Number, int, float64:
+			return 3
+		case string:
+			return 4
+		case []interface{}:
+			return 5
+		default:
+			return 6
+		}
+	}
+	if ra, rb := rank(a), rank(b); ra != rb {
+		return ra - rb
+	}
+
+	switch v := a.(type) {
+	case string:
+		return strings.Compare(v, b.(string))
+	case json.Number, int, float64:
+		fa, fb := jqFloat(a), jqFloat(b)
+		if fa < fb {
+			return -1
+		} else if fa > fb {
+			return 1
+		}
+		return 0
+	case []interface{}, map[string]interface{}:
+		ea, _ := json.Marshal(a)
+		eb, _ := json.Marshal(b)
+		return strings.Compare(string(ea), string(eb))
+	}
+	return 0
+}
+
+func jqFloat(value interface{}) float64 {
+	switch v := value.(type) {
+	case json.Number:
+		f, _ := v.Float64()
+		return f
+	case int:
+		return float64(v)
+	case float64:
+		return v
+	}
+	return 0
+}
@@ -0,0 +1,166 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func jqOutput(t *testing.T, expr, input string) string {
+	filter, err := parseJQ(expr)
+	assert.Equal(t, nil, err)
+
+	var out bytes.Buffer
+	err = printJQResults(&out, filter, strings.NewReader(input))
+	assert.Equal(t, nil, err)
+	return out.String()
+}
+
+func TestJQ(t *testing.T) {
+	pulls := `[
+		{"number": 12, "title": "Fix bug", "draft": false, "user": {"login": "mislav"}, "labels": [{"name": "bug"}]},
+		{"number": 13, "title": "WIP", "draft": true, "user": {"login": "octocat"}, "labels": []}
+	]`
+
+	assert.Equal(t, "Fix bug\nWIP\n", jqOutput(t, ".[].title", pulls))
+	assert.Equal(t, "13\n", jqOutput(t, ".[-1].number", pulls))
+	assert.Equal(t, "mislav\n", jqOutput(t, `.[0]["user"]."login"`, pulls))
+	assert.Equal(t, "Fix bug\n", jqOutput(t, ".[] | select(.draft == false) | .title", pulls))
+	assert.Equal(t, "13\n", jqOutput(t, ".[] | select(.number > 12 and (.labels | length) == 0) | .number", pulls))
+	assert.Equal(t, "12\nmislav\n", jqOutput(t, ".[] | select(.draft | not) | .number, .user.login", pulls))
+	assert.Equal(t, "2\n", jqOutput(t, "length", pulls))
+	assert.Equal(t, `["draft","labels","number","title","user"]`+"\n", jqOutput(t, ".[0] | keys", pulls))
+	assert.Equal(t, `{"name":"bug"}`+"\n", jqOutput(t, ".[0].labels[]", pulls))
+	assert.Equal(t, "null\n", jqOutput(t, ".[5].missing", pulls))
+	assert.Equal(t, "true\n", jqOutput(t, `.[1].title == "WIP" or false`, pulls))
+}
+
+func TestJQ_Errors(t *testing.T) {
+	for expr, message := range map[string]string{
+		".foo + 1":    "unsupported syntax `+' at position 6",
+		"map(.foo)":   "unsupported function `map' at position 1",
+		".[":          "unexpected end of expression, expected `]'",
+		".[.foo]":     "unsupported index `.foo' at position 3",
+		"select(.a":   "unexpected end of expression, expected `)'",
+		".a .b":       "unexpected `.b' at position 4",
+		".a = 1":      "unsupported syntax `=' at position 4",
+		`"unfinished`: "unterminated string at position 1",
+	} {
+		_, err := parseJQ(expr)
+		assert.Equal(t, message, err.Error())
+	}
+
+	filter, _ := parseJQ(".[0]")
+	var out bytes.Buffer
+	err := printJQResults(&out, filter, strings.NewReader(`{"a": 1}`))
+	assert.Equal(t, "Error: --jq: cannot index object with a number", err.Error())
+}
+
+func TestNextKnownPage(t *testing.T) {
+	last := "https://api.github.com/repositories/123/labels?page=4&per_page=30"
+
+	assert.Equal(t, "https://api.github.com/repositories/123/labels?page=3&per_page=30",
+		nextKnownPage("https://api.github.com/repositories/123/labels?page=2&per_page=30", last))
+	assert.Equal(t, "https://api.github.com/repositories/123/labels?page=2&per_page=30",
+		nextKnownPage("repos/owner/repo/labels", last))
+	assert.Equal(t, "", nextKnownPage("https://api.github.com/repositories/123/labels?page=4&per_page=30", last))
+	assert.Equal(t, "", nextKnownPage("https://api.github.com/repositories/123/labels?page=2", ""))
+}
+
+func TestPreviewAcceptHeader(t *testing.T) {
+	for path, accept := range map[string]string{
+		"repos/mislav/dotfiles/topics":                           "application/vnd.github.mercy-preview+json",
+		"/repos/mislav/dotfiles/topics?per_page=100":             "application/vnd.github.mercy-preview+json",
+		"https://git.my.org/api/v3/repos/mislav/dotfiles/topics": "application/vnd.github.mercy-preview+json",
+		"repos/mislav/dotfiles/issues/12/reactions":              "application/vnd.github.squirrel-girl-preview+json",
+		"repos/mislav/dotfiles/issues/comments/3/reactions/4":    "application/vnd.github.squirrel-girl-preview+json",
+		"orgs/github/projects":                                   "application/vnd.github.inertia-preview+json",
+		"projects/columns/12/cards":                              "application/vnd.github.inertia-preview+json",
+		"repos/mislav/dotfiles/commits/main/check-runs":          "application/vnd.github.antiope-preview+json",
+		"repos/mislav/dotfiles/check-suites/5":                   "application/vnd.github.antiope-preview+json",
+		"repos/mislav/dotfiles/issues/12/timeline":               "application/vnd.github.mockingbird-preview+json",
+		"repos/mislav/dotfiles":                                  "",
+		"repos/mislav/projects":                                  "",
+		"user":                                                   "",
+	} {
+		assert.Equal(t, accept, previewAcceptHeader(path))
+	}
+}
+
+func TestAPIMethod(t *testing.T) {
+	for _, test := range []struct {
+		flag       string
+		hasPayload bool
+		method     string
+	}{
+		{"", false, "GET"},
+		{"", true, "POST"},
+		{"get", true, "GET"},
+		{"Patch", false, "PATCH"},
+		{"DELETE", false, "DELETE"},
+	} {
+		method, err := apiMethod(test.flag, test.hasPayload)
+		assert.Equal(t, nil, err)
+		assert.Equal(t, test.method, method)
+	}
+
+	_, err := apiMethod("FETCH", false)
+	assert.Equal(t, "Error: invalid --method `FETCH'; expected one of GET, HEAD, POST, PUT, PATCH, DELETE, OPTIONS", err.Error())
+}
+
+func TestIsReadOnlyEndpoint(t *testing.T) {
+	for path, readOnly := range map[string]bool{
+		"search/issues":                         true,
+		"/search/issues?q=hub":                  true,
+		"user":                                  true,
+		"users/mislav":                          true,
+		"https://git.my.org/api/v3/repos/o/r":   true,
+		"repos/{owner}/{repo}/commits":          true,
+		"repos/mislav/dotfiles/compare/a...b":   true,
+		"repos/mislav/dotfiles/issues":          false,
+		"user/repos":                            false,
+		"repos/mislav/dotfiles/issues/1/labels": false,
+		"graphql":                               false,
+	} {
+		assert.Equal(t, readOnly, isReadOnlyEndpoint(path), path)
+	}
+}
+
+func TestSlurpPages(t *testing.T) {
+	combined, err := slurpPages([]json.RawMessage{[]byte(`[{"id": 1}, {"id": 2}]`), []byte(`[]`), []byte(`[{"id": 3}]`)})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `[{"id":1},{"id":2},{"id":3}]`, string(combined))
+
+	combined, err = slurpPages([]json.RawMessage{[]byte(`{"items": [1]}`), []byte(`{"items": [2]}`)})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `[{"items":[1]},{"items":[2]}]`, string(combined))
+
+	combined, err = slurpPages([]json.RawMessage{})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `[]`, string(combined))
+}
+
+func TestCheckAPIExpectations(t *testing.T) {
+	err := checkAPIExpectations(404, "404 Not Found", nil, []int{200, 404}, nil, "")
+	assert.Equal(t, nil, err)
+
+	err = checkAPIExpectations(404, "404 Not Found", nil, []int{200, 201}, nil, "")
+	assert.Equal(t, "Error: expected HTTP status 200 or 201, got 404 Not Found", err.Error())
+
+	filter, _ := parseJQ(".private")
+	err = checkAPIExpectations(200, "200 OK", []byte(`{"private": true}`), nil, filter, ".private")
+	assert.Equal(t, nil, err)
+
+	err = checkAPIExpectations(200, "200 OK", []byte(`{"private": false}`), nil, filter, ".private")
+	assert.Equal(t, "Error: expected `.private' to be true, got false", err.Error())
+
+	err = checkAPIExpectations(200, "200 OK", []byte(`{}`), nil, filter, ".private")
+	assert.Equal(t, "Error: expected `.private' to be true, got null", err.Error())
+
+	filter, _ = parseJQ(".[]")
+	err = checkAPIExpectations(200, "200 OK", []byte(`[]`), nil, filter, ".[]")
+	assert.Equal(t, "Error: expected `.[]' to be true, but it produced no results", err.Error())
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/github/hub/cmd"
+	"github.com/github/hub/ui"
 	"github.com/github/hub/utils"
 )
 
@@ -21,6 +22,14 @@ type Args struct {
 	noForward   bool
 	Callbacks   []func() error
 	Flag        *utils.ArgsParser
+
+	NoHTTPSUpgrade bool
+	NoCache        bool
+	UseTokenForGit bool
+	Verbosity      int
+	Repo           string
+	Scheme         string
+	Host           string
 }
 
 func (a *Args) Words() []string {
@@ -169,19 +178,46 @@ func (a *Args) AppendParams(params ...string) {
 
 func NewArgs(args []string) *Args {
 	var (
-		command string
-		params  []string
-		noop    bool
+		command        string
+		params         []string
+		noop           bool
+		noHTTPSUpgrade bool
+		noCache        bool
+		useTokenForGit bool
+		verbosity      = ui.VerbosityNormal
+		repo           string
+		scheme         string
+		host           string
 	)
 
 	cmdIdx := findCommandIndex(args)
 	globalFlags := args[:cmdIdx]
 	if cmdIdx > 0 {
 		args = args[cmdIdx:]
+		repo, globalFlags = extractValueFlag(globalFlags, repoFlag, repoShortFlag)
+		scheme, globalFlags = extractValueFlag(globalFlags, schemeFlag, "")
+		host, globalFlags = extractValueFlag(globalFlags, hostFlag, "")
 		for i := len(globalFlags) - 1; i >= 0; i-- {
 			if globalFlags[i] == noopFlag {
 				noop = true
 				globalFlags = append(globalFlags[:i], globalFlags[i+1:]...)
+			} else if globalFlags[i] == noHTTPSUpgradeFlag {
+				noHTTPSUpgrade = true
+				globalFlags = append(globalFlags[:i], globalFlags[i+1:]...)
+			} else if globalFlags[i] == noCacheFlag {
+				noCache = true
+				globalFlags = append(globalFlags[:i], globalFlags[i+1:]...)
+			} else if globalFlags[i] == useTokenForGitFlag {
+				useTokenForGit = true
+				globalFlags = append(globalFlags[:i], globalFlags[i+1:]...)
+			} else if globalFlags[i] == quietFlag || globalFlags[i] == quietShortFlag {
+				if verbosity < ui.VerbosityQuiet {
+					verbosity = ui.VerbosityQuiet
+				}
+				globalFlags = append(globalFlags[:i], globalFlags[i+1:]...)
+			} else if globalFlags[i] == silentFlag {
+				verbosity = ui.VerbositySilent
+				globalFlags = append(globalFlags[:i], globalFlags[i+1:]...)
 			}
 		}
 	}
@@ -192,30 +228,94 @@ func NewArgs(args []string) *Args {
 	}
 
 	return &Args{
-		Executable:  "git",
-		GlobalFlags: globalFlags,
-		Command:     command,
-		Params:      params,
-		Noop:        noop,
-		beforeChain: make([]*cmd.Cmd, 0),
-		afterChain:  make([]*cmd.Cmd, 0),
+		Executable:     "git",
+		GlobalFlags:    globalFlags,
+		Command:        command,
+		Params:         params,
+		Noop:           noop,
+		NoHTTPSUpgrade: noHTTPSUpgrade,
+		NoCache:        noCache,
+		UseTokenForGit: useTokenForGit,
+		Verbosity:      verbosity,
+		Repo:           repo,
+		Scheme:         scheme,
+		Host:           host,
+		beforeChain:    make([]*cmd.Cmd, 0),
+		afterChain:     make([]*cmd.Cmd, 0),
 	}
 }
 
 const (
-	noopFlag    = "--noop"
-	versionFlag = "--version"
-	listCmds    = "--list-cmds="
-	helpFlag    = "--help"
-	configFlag  = "-c"
-	chdirFlag   = "-C"
-	flagPrefix  = "-"
+	noopFlag           = "--noop"
+	noHTTPSUpgradeFlag = "--no-https-upgrade"
+	noCacheFlag        = "--no-cache"
+	useTokenForGitFlag = "--use-token-for-git"
+	quietFlag          = "--quiet"
+	quietShortFlag     = "-q"
+	silentFlag         = "--silent"
+	repoFlag           = "--repo"
+	repoShortFlag      = "-R"
+	schemeFlag         = "--scheme"
+	hostFlag           = "--host"
+	versionFlag        = "--version"
+	listCmds           = "--list-cmds="
+	helpFlag           = "--help"
+	configFlag         = "-c"
+	chdirFlag          = "-C"
+	flagPrefix         = "-"
 )
 
 func looksLikeFlag(value string) bool {
 	return strings.HasPrefix(value, flagPrefix)
 }
 
+// extractValueFlag removes "<SHORT> <VALUE>", "<LONG> <VALUE>", and
+// "<LONG>=<VALUE>" from the global flags, returning the last value given.
+func extractValueFlag(globalFlags []string, long, short string) (value string, rest []string) {
+	rest = []string{}
+	for i := 0; i < len(globalFlags); i++ {
+		flag := globalFlags[i]
+		switch {
+		case flag == configFlag || flag == chdirFlag:
+			rest = append(rest, flag)
+			if i+1 < len(globalFlags) {
+				i++
+				rest = append(rest, globalFlags[i])
+			}
+		case (flag == long || short != "" && flag == short) && i+1 < len(globalFlags):
+			i++
+			value = globalFlags[i]
+		case strings.HasPrefix(flag, long+"="):
+			value = strings.TrimPrefix(flag, long+"=")
+		default:
+			rest = append(rest, flag)
+		}
+	}
+	return
+}
+
+// extractRepoParam removes "-R <VALUE>", "--repo <VALUE>", and
+// "--repo=<VALUE>" given after the command name, up to a "--" separator,
+// returning the last value given.
+func extractRepoParam(params []string) (value string, rest []string) {
+	rest = []string{}
+	for i := 0; i < len(params); i++ {
+		param := params[i]
+		switch {
+		case param == "--":
+			return value, append(rest, params[i:]...)
+		case (param == repoFlag || param == repoShortFlag) && i+1 < len(params):
+			i++
+			value = params[i]
+		case strings.HasPrefix(param, repoFlag+"="):
+			value = strings.TrimPrefix(param, repoFlag+"=")
+		default:
+			rest = append(rest, param)
+		}
+	}
+	return
+}
+
 func findCommandIndex(args []string) int {
 	slurpNextValue := false
 	commandIndex := 0
@@ -228,7 +328,7 @@ func findCommandIndex(args []string) int {
 			break
 		} else {
 			commandIndex = i + 1
-			if arg == configFlag || arg == chdirFlag {
+			if arg == configFlag || arg == chdirFlag || arg == repoFlag || arg == repoShortFlag || arg == schemeFlag || arg == hostFlag {
 				slurpNextValue = true
 			}
 		}
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/bmizerany/assert"
+	"github.com/github/hub/ui"
 )
 
 func TestNewArgs(t *testing.T) {
@@ -106,6 +107,78 @@ func TestArgs_GlobalFlags_Repeated(t *testing.T) {
 	assert.Equal(t, false, args.Noop)
 }
 
+func TestArgs_GlobalFlags_Repo(t *testing.T) {
+	args := NewArgs([]string{"-c", "-R", "-R", "mislav/dotfiles", "--noop", "issue", "-R", "x"})
+	assert.Equal(t, "issue", args.Command)
+	assert.Equal(t, []string{"-c", "-R"}, args.GlobalFlags)
+	assert.Equal(t, []string{"-R", "x"}, args.Params)
+	assert.Equal(t, "mislav/dotfiles", args.Repo)
+	assert.Equal(t, true, args.Noop)
+
+	args = NewArgs([]string{"--repo=github.example.com/mislav/dotfiles", "pr", "list"})
+	assert.Equal(t, "pr", args.Command)
+	assert.Equal(t, 0, len(args.GlobalFlags))
+	assert.Equal(t, "github.example.com/mislav/dotfiles", args.Repo)
+}
+
+func TestArgs_ExtractRepoParam(t *testing.T) {
+	repo, rest := extractRepoParam([]string{"list", "-R", "mislav/dotfiles", "-s", "closed"})
+	assert.Equal(t, "mislav/dotfiles", repo)
+	assert.Equal(t, []string{"list", "-s", "closed"}, rest)
+
+	repo, rest = extractRepoParam([]string{"--repo=github/hub", "create", "--", "-R", "x"})
+	assert.Equal(t, "github/hub", repo)
+	assert.Equal(t, []string{"create", "--", "-R", "x"}, rest)
+
+	repo, rest = extractRepoParam([]string{"show", "12"})
+	assert.Equal(t, "", repo)
+	assert.Equal(t, []string{"show", "12"}, rest)
+}
+
+func TestArgs_GlobalFlags_Scheme(t *testing.T) {
+	args := NewArgs([]string{"--scheme", "http", "-R", "mislav/dotfiles", "fork"})
+	assert.Equal(t, "fork", args.Command)
+	assert.Equal(t, 0, len(args.GlobalFlags))
+	assert.Equal(t, "http", args.Scheme)
+	assert.Equal(t, "mislav/dotfiles", args.Repo)
+
+	args = NewArgs([]string{"--scheme=https", "fork"})
+	assert.Equal(t, "fork", args.Command)
+	assert.Equal(t, "https", args.Scheme)
+}
+
+func TestArgs_GlobalFlags_Host(t *testing.T) {
+	args := NewArgs([]string{"--host", "git.my.org", "-c", "key=value", "clone", "dotfiles"})
+	assert.Equal(t, "clone", args.Command)
+	assert.Equal(t, "git.my.org", args.Host)
+	assert.Equal(t, []string{"-c", "key=value"}, args.GlobalFlags)
+	assert.Equal(t, []string{"dotfiles"}, args.Params)
+
+	args = NewArgs([]string{"--host=git.my.org", "create"})
+	assert.Equal(t, "create", args.Command)
+	assert.Equal(t, "git.my.org", args.Host)
+}
+
+func TestArgs_GlobalFlags_NoCache(t *testing.T) {
+	args := NewArgs([]string{"--no-cache", "-c", "key=value", "pull-request"})
+	assert.Equal(t, "pull-request", args.Command)
+	assert.Equal(t, true, args.NoCache)
+	assert.Equal(t, []string{"-c", "key=value"}, args.GlobalFlags)
+}
+
+func TestArgs_GlobalFlags_Verbosity(t *testing.T) {
+	args := NewArgs([]string{"-q", "sync"})
+	assert.Equal(t, "sync", args.Command)
+	assert.Equal(t, 0, len(args.GlobalFlags))
+	assert.Equal(t, ui.VerbosityQuiet, args.Verbosity)
+
+	args = NewArgs([]string{"--silent", "--quiet", "sync"})
+	assert.Equal(t, ui.VerbositySilent, args.Verbosity)
+
+	args = NewArgs([]string{"sync", "--quiet"})
+	assert.Equal(t, ui.VerbosityNormal, args.Verbosity)
+}
+
 func TestArgs_GlobalFlags_Propagate(t *testing.T) {
 	args := NewArgs([]string{"-c", "key=value", "status"})
 	cmd := args.ToCmd()
@@ -3,29 +3,77 @@ package commands
 import (
 	"fmt"
 	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
 
+	"github.com/github/hub/git"
 	"github.com/github/hub/github"
 	"github.com/github/hub/utils"
 )
 
 var cmdBrowse = &Command{
-	Run:   browse,
-	Usage: "browse [-uc] [[<USER>/]<REPOSITORY>|--] [<SUBPAGE>]",
+	Run: browse,
+	Usage: `
+browse [-uc] [[<USER>/]<REPOSITORY>|--] [<SUBPAGE>]
+browse [-uc] [[<USER>/]<REPOSITORY>] (--issue <NUMBER>|--pr <NUMBER>)
+browse [-uc] --permalink [--] [<FILE>[:<LINE>[-<LINE>]]]
+browse [-uc] --last
+`,
 	Long: `Open a GitHub repository in a web browser.
 
 ## Options:
 	-u, --url
 		Print the URL instead of opening it.
 
+	--dump-url
+		Same as '--url'. This flag is accepted by all hub commands that open a
+		web browser, which is handy for scripts that run on headless machines.
+
 	-c, --copy
 		Put the URL in clipboard instead of opening it.
+
+	--latest
+		Open the page of the latest release. Same as the "latest" <SUBPAGE>.
+
+	--permalink
+		Link to the commit that is currently checked out instead of to the
+		branch, so that the link keeps showing the same content after the branch
+		moves on. This works with no <SUBPAGE>, with "tree" or "commits", and
+		with the path of a file in the working tree, optionally followed by
+		":<LINE>" or ":<START>-<END>" to highlight lines. The commit needs to be
+		pushed for the link to work.
+
+	--last
+		Open the pull request, issue, release, or repository that was last
+		created by hub from the current repository.
+
+	--issue <NUMBER>
+		Open the issue with the given <NUMBER>.
+
+	--pr <NUMBER>
+		Open the pull request with the given <NUMBER>.
+
+	--notifications
+		Open the notifications page of the authenticated user.
+
+	--profile
+		Open the profile page of the user given in place of <REPOSITORY>, or of
+		the authenticated user if none was given.
+
+		Like '--notifications', this doesn't need a git repository. The GitHub host
+		of the current repository is used if there is one, and the default host
+		otherwise.
 	
 	[<USER>/]<REPOSITORY>
 		Defaults to repository in the current working directory.
 
 	<SUBPAGE>
-		One of "wiki", "commits", "issues", or other (default: "tree").
+		One of "wiki", "commits", "issues", "settings", "actions", "releases",
+		"latest", "tags", "branches", "security", or other (default: "tree"). Any
+		other value is treated as a path within the repository.
 
 ## Examples:
 		$ hub browse
@@ -40,12 +88,54 @@ var cmdBrowse = &Command{
 		$ hub browse gh wiki
 		> open https://github.com/USER/gh/wiki
 
+		$ hub browse -u -- actions
+		> https://github.com/REPO/actions
+
+		$ hub browse --permalink -- commands/browse.go:12-20
+		> open https://github.com/REPO/blob/SHA/commands/browse.go#L12-L20
+
+		$ hub pull-request -m "Fix typo"
+		$ hub browse --last
+		> open https://github.com/REPO/pull/123
+
+		$ hub browse --latest
+		> open https://github.com/REPO/releases/latest
+
+		$ hub browse -R github/hub --pr 2600
+		> open https://github.com/github/hub/pull/2600
+
+		$ hub browse --profile
+		> open https://github.com/USER
+
+		$ hub browse --notifications
+		> open https://github.com/notifications
+
 ## See also:
 
 hub-compare(1), hub(1)
 `,
 }
 
+// browseSubpages maps recognized <SUBPAGE> keywords to repository paths.
+var browseSubpages = map[string]string{
+	"wiki":     "wiki",
+	"issues":   "issues",
+	"pulls":    "pulls",
+	"settings": "settings",
+	"actions":  "actions",
+	"releases": "releases",
+	"latest":   "releases/latest",
+	"tags":     "tags",
+	"branches": "branches",
+	"security": "security",
+}
+
+var browsePathRe = regexp.MustCompile(`^[\w.~%+@-]+(?:/\S*)?$`)
+
+// browseLinesRe matches the ":<LINE>" or ":<START>-<END>" suffix of a file
+// given to '--permalink'
+var browseLinesRe = regexp.MustCompile(`:(\d+)(?:-(\d+))?$`)
+
 func init() {
 	CmdRunner.Use(cmdBrowse)
 }
@@ -73,11 +163,72 @@ func browse(command *Command, args *Args) {
 		dest = ""
 	}
 
+	if args.Flag.Bool("--last") {
+		if dest != "" || subpage != "" {
+			utils.Check(command.UsageError("can't use --last together with <REPOSITORY> or <SUBPAGE>"))
+		}
+		lastURL, err := readLastURL()
+		utils.Check(err)
+
+		args.NoForward()
+		flagBrowseURLPrint := args.Flag.Bool("--url")
+		flagBrowseURLCopy := args.Flag.Bool("--copy")
+		printBrowseOrCopy(args, lastURL, !flagBrowseURLPrint && !flagBrowseURLCopy, flagBrowseURLCopy)
+		return
+	}
+
+	if args.Flag.Bool("--latest") {
+		if subpage != "" {
+			utils.Check(command.UsageError("can't use --latest together with <SUBPAGE>"))
+		}
+		subpage = "latest"
+	}
+
+	for _, numbered := range []struct{ flag, page, name string }{
+		{"--issue", "issues", "issue"},
+		{"--pr", "pull", "pull request"},
+	} {
+		flag := numbered.flag
+		if !args.Flag.HasReceived(flag) {
+			continue
+		}
+		if subpage != "" {
+			utils.Check(command.UsageError(fmt.Sprintf("can't use %s together with <SUBPAGE>", flag)))
+		}
+		number, err := strconv.Atoi(args.Flag.Value(flag))
+		if err != nil || number <= 0 {
+			utils.Check(fmt.Errorf("Error: invalid %s number: %s", numbered.name, args.Flag.Value(flag)))
+		}
+		subpage = fmt.Sprintf("%s/%d", numbered.page, number)
+	}
+
+	flagBrowsePermalink := args.Flag.Bool("--permalink")
+	if flagBrowsePermalink && dest != "" {
+		utils.Check(command.UsageError("can't use --permalink together with <REPOSITORY>"))
+	}
+
 	localRepo, _ := github.LocalRepo()
+
+	flagBrowseNotifications := args.Flag.Bool("--notifications")
+	flagBrowseProfile := args.Flag.Bool("--profile")
+	if flagBrowseNotifications || flagBrowseProfile {
+		if flagBrowseNotifications && (flagBrowseProfile || dest != "") || subpage != "" {
+			utils.Check(command.UsageError(""))
+		}
+		pageUrl, err := browseUserPage(localRepo, flagBrowseProfile, dest)
+		utils.Check(err)
+
+		args.NoForward()
+		flagBrowseURLPrint := args.Flag.Bool("--url")
+		flagBrowseURLCopy := args.Flag.Bool("--copy")
+		printBrowseOrCopy(args, pageUrl, !flagBrowseURLPrint && !flagBrowseURLCopy, flagBrowseURLCopy)
+		return
+	}
+
 	if dest != "" {
 		project = github.NewProject("", dest, "")
 		branch = localRepo.MasterBranch()
-	} else if subpage != "" && subpage != "commits" && subpage != "tree" && subpage != "blob" && subpage != "settings" {
+	} else if subpage != "" && !flagBrowsePermalink && subpage != "commits" && subpage != "tree" && subpage != "blob" && subpage != "settings" {
 		project, err = localRepo.MainProject()
 		branch = localRepo.MasterBranch()
 		utils.Check(err)
@@ -108,14 +259,21 @@ func browse(command *Command, args *Args) {
 		utils.Check(command.UsageError(""))
 	}
 
-	if subpage == "commits" {
+	if flagBrowsePermalink {
+		path, err = browsePermalinkPath(subpage)
+		utils.Check(err)
+	} else if subpage == "commits" {
 		path = fmt.Sprintf("commits/%s", branchInURL(branch))
 	} else if subpage == "tree" || subpage == "" {
 		if !branch.IsMaster() {
 			path = fmt.Sprintf("tree/%s", branchInURL(branch))
 		}
-	} else {
+	} else if subpagePath, ok := browseSubpages[subpage]; ok {
+		path = subpagePath
+	} else if browsePathRe.MatchString(subpage) {
 		path = subpage
+	} else {
+		utils.Check(fmt.Errorf("Error: unknown subpage '%s'", subpage))
 	}
 
 	pageUrl := project.WebURL("", "", path)
@@ -126,6 +284,104 @@ func browse(command *Command, args *Args) {
 	printBrowseOrCopy(args, pageUrl, !flagBrowseURLPrint && !flagBrowseURLCopy, flagBrowseURLCopy)
 }
 
+// browsePermalinkPath returns the path of the page for subpage at the commit
+// that is checked out. Other than "tree" and "commits", subpage can be a file
+// in the working tree with an optional line range.
+func browsePermalinkPath(subpage string) (string, error) {
+	sha, err := git.Ref("HEAD")
+	if err != nil {
+		return "", fmt.Errorf("Error: --permalink requires a commit to be checked out")
+	}
+
+	switch subpage {
+	case "", "tree":
+		return "tree/" + sha, nil
+	case "commits":
+		return "commits/" + sha, nil
+	}
+
+	file := subpage
+	fragment := ""
+	if _, err := os.Stat(file); err != nil {
+		if m := browseLinesRe.FindStringSubmatch(file); m != nil {
+			file = strings.TrimSuffix(file, m[0])
+			fragment = "#L" + m[1]
+			if m[2] != "" {
+				fragment += "-L" + m[2]
+			}
+		}
+	}
+	if _, err := os.Stat(file); err != nil {
+		return "", fmt.Errorf("Error: --permalink can only be used with \"tree\", \"commits\", or a file in the working tree, not `%s'", subpage)
+	}
+
+	relativePath, err := workdirRelativePath(file)
+	if err != nil {
+		return "", err
+	}
+	segments := strings.Split(relativePath, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return fmt.Sprintf("blob/%s/%s%s", sha, strings.Join(segments, "/"), fragment), nil
+}
+
+// workdirRelativePath turns file, relative to the current directory, into a
+// path relative to the root of the working tree, with forward slashes
+func workdirRelativePath(file string) (string, error) {
+	workdir, err := git.WorkdirName()
+	if err != nil {
+		return "", err
+	}
+	absFile, err := filepath.Abs(file)
+	if err != nil {
+		return "", err
+	}
+	// only the directory is resolved so that a symlinked file links to itself
+	// rather than to its target
+	if resolved, err := filepath.EvalSymlinks(filepath.Dir(absFile)); err == nil {
+		absFile = filepath.Join(resolved, filepath.Base(absFile))
+	}
+	if resolved, err := filepath.EvalSymlinks(workdir); err == nil {
+		workdir = resolved
+	}
+
+	relativePath, err := filepath.Rel(workdir, absFile)
+	if err != nil || relativePath == ".." || strings.HasPrefix(relativePath, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("Error: `%s' is outside of the repository", file)
+	}
+	return filepath.ToSlash(relativePath), nil
+}
+
+// browseUserPage returns the URL of the notifications page, or of the profile
+// page of user when profile is set. Without a user, the profile of the user
+// that hub is authenticated as is used.
+func browseUserPage(localRepo *github.GitHubRepo, profile bool, user string) (string, error) {
+	config := github.CurrentConfig()
+
+	var host *github.Host
+	var err error
+	if project, projectErr := localRepo.MainProject(); projectErr != nil {
+		host, err = config.DefaultHostNoPrompt()
+	} else if profile && user == "" {
+		host, err = config.PromptForHost(project.Host)
+	} else if host = config.Find(project.Host); host == nil {
+		host = &github.Host{Host: project.Host, Protocol: "https"}
+	}
+	if err != nil {
+		return "", github.FormatError("in browse", err)
+	}
+	if profile && user == "" {
+		user = host.User
+	}
+
+	page := "notifications"
+	if profile {
+		page = user
+	}
+	return fmt.Sprintf("%s/%s", hostURL(host), page), nil
+}
+
 func branchInURL(branch *github.Branch) string {
 	parts := strings.Split(branch.ShortName(), "/")
 	newPath := make([]string, len(parts))
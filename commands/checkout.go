@@ -1,11 +1,15 @@
 package commands
 
 import (
+	"bufio"
 	"fmt"
+	"os"
 	"regexp"
+	"strings"
 
 	"github.com/github/hub/git"
 	"github.com/github/hub/github"
+	"github.com/github/hub/ui"
 	"github.com/github/hub/utils"
 )
 
@@ -31,7 +35,7 @@ func init() {
 }
 
 func checkout(command *Command, args *Args) {
-	words := args.Words()
+	words := checkoutWords(args)
 
 	if len(words) == 0 {
 		return
@@ -64,7 +68,7 @@ func checkout(command *Command, args *Args) {
 	pullRequest, err := gh.PullRequest(url.Project, id)
 	utils.Check(err)
 
-	newArgs, err := transformCheckoutArgs(args, pullRequest, newBranchName)
+	newArgs, err := transformCheckoutArgs(args, pullRequest, newBranchName, false, false)
 	utils.Check(err)
 
 	if idx := args.IndexOfParam(newBranchName); idx >= 0 {
@@ -73,7 +77,7 @@ func checkout(command *Command, args *Args) {
 	replaceCheckoutParam(args, checkoutURL, newArgs...)
 }
 
-func transformCheckoutArgs(args *Args, pullRequest *github.PullRequest, newBranchName string) (newArgs []string, err error) {
+func transformCheckoutArgs(args *Args, pullRequest *github.PullRequest, newBranchName string, force, track bool) (newArgs []string, err error) {
 	repo, err := github.LocalRepo()
 	if err != nil {
 		return
@@ -91,19 +95,36 @@ func transformCheckoutArgs(args *Args, pullRequest *github.PullRequest, newBranc
 		headRemote, _ = repo.RemoteForRepo(pullRequest.Head.Repo)
 	}
 
+	if track && headRemote == nil {
+		if headRemote, err = addHeadRemote(args, repo, pullRequest); err != nil {
+			return
+		}
+	}
+
 	if headRemote != nil {
 		if newBranchName == "" {
 			newBranchName = pullRequest.Head.Ref
 		}
 		remoteBranch := fmt.Sprintf("%s/%s", headRemote.Name, pullRequest.Head.Ref)
 		refSpec := fmt.Sprintf("+refs/heads/%s:refs/remotes/%s", pullRequest.Head.Ref, remoteBranch)
-		if git.HasFile("refs", "heads", newBranchName) {
+		mergeRef := "refs/heads/" + pullRequest.Head.Ref
+		exists := git.HasFile("refs", "heads", newBranchName)
+		if exists {
+			if err = checkExistingBranch(args, newBranchName, headRemote.Name, mergeRef, pullRequest, force); err != nil {
+				return
+			}
+		}
+		if exists && !force {
 			newArgs = append(newArgs, newBranchName)
 			args.After("git", "merge", "--ff-only", fmt.Sprintf("refs/remotes/%s", remoteBranch))
 		} else {
-			newArgs = append(newArgs, "-b", newBranchName, "--no-track", remoteBranch)
+			createFlag := "-b"
+			if exists {
+				createFlag = "-B"
+			}
+			newArgs = append(newArgs, createFlag, newBranchName, "--no-track", remoteBranch)
 			args.After("git", "config", fmt.Sprintf("branch.%s.remote", newBranchName), headRemote.Name)
-			args.After("git", "config", fmt.Sprintf("branch.%s.merge", newBranchName), "refs/heads/"+pullRequest.Head.Ref)
+			args.After("git", "config", fmt.Sprintf("branch.%s.merge", newBranchName), mergeRef)
 		}
 		args.Before("git", "fetch", headRemote.Name, refSpec)
 	} else {
@@ -113,11 +134,8 @@ func transformCheckoutArgs(args *Args, pullRequest *github.PullRequest, newBranc
 				newBranchName = fmt.Sprintf("%s-%s", pullRequest.Head.Repo.Owner.Login, newBranchName)
 			}
 		}
-		newArgs = append(newArgs, newBranchName)
 
 		ref := fmt.Sprintf("refs/pull/%d/head", pullRequest.Number)
-		args.Before("git", "fetch", baseRemote.Name, fmt.Sprintf("%s:%s", ref, newBranchName))
-
 		remote := baseRemote.Name
 		mergeRef := ref
 		if pullRequest.MaintainerCanModify && pullRequest.Head.Repo != nil {
@@ -130,12 +148,101 @@ func transformCheckoutArgs(args *Args, pullRequest *github.PullRequest, newBranc
 			remote = project.GitURL("", "", true)
 			mergeRef = fmt.Sprintf("refs/heads/%s", pullRequest.Head.Ref)
 		}
+
+		if git.HasFile("refs", "heads", newBranchName) {
+			if err = checkExistingBranch(args, newBranchName, remote, mergeRef, pullRequest, force); err != nil {
+				return
+			}
+			args.Before("git", "fetch", baseRemote.Name, ref)
+			if force {
+				newArgs = append(newArgs, "-B", newBranchName, "FETCH_HEAD")
+			} else {
+				newArgs = append(newArgs, newBranchName)
+				args.After("git", "merge", "--ff-only", "FETCH_HEAD")
+				return
+			}
+		} else {
+			newArgs = append(newArgs, newBranchName)
+			args.Before("git", "fetch", baseRemote.Name, fmt.Sprintf("%s:%s", ref, newBranchName))
+		}
+
 		args.After("git", "config", fmt.Sprintf("branch.%s.remote", newBranchName), remote)
 		args.After("git", "config", fmt.Sprintf("branch.%s.merge", newBranchName), mergeRef)
 	}
 	return
 }
 
+// addHeadRemote adds a git remote named after the owner of the head
+// repository of a pull request from a fork, so that the checked out branch can
+// track the contributor's branch
+func addHeadRemote(args *Args, repo *github.GitHubRepo, pullRequest *github.PullRequest) (*github.Remote, error) {
+	if pullRequest.Head.Repo == nil {
+		return nil, fmt.Errorf("Error: can't track pull request #%d because its head repository was deleted", pullRequest.Number)
+	}
+
+	project, err := github.NewProjectFromRepo(pullRequest.Head.Repo)
+	if err != nil {
+		return nil, err
+	}
+
+	remoteName := project.Owner
+	if existing, err := repo.RemoteByName(remoteName); err == nil {
+		return nil, fmt.Errorf("Error: can't track pull request #%d: a git remote named `%s' already exists and points to '%s'", pullRequest.Number, remoteName, existing.URL)
+	}
+
+	args.Before("git", "remote", "add", remoteName, project.GitURL("", "", pullRequest.Head.Repo.Private))
+	return &github.Remote{Name: remoteName}, nil
+}
+
+// checkExistingBranch verifies that a local branch about to be reused for a
+// pull request can be switched to. A branch qualifies if its configured
+// upstream is the head of the pull request; otherwise it is only reset to the
+// pull request head when force is set and the user confirms.
+func checkExistingBranch(args *Args, branch, remote, mergeRef string, pullRequest *github.PullRequest, force bool) error {
+	upstreamRemote, _ := git.Config(fmt.Sprintf("branch.%s.remote", branch))
+	upstreamMerge, _ := git.Config(fmt.Sprintf("branch.%s.merge", branch))
+	if upstreamRemote == remote && upstreamMerge == mergeRef {
+		return nil
+	}
+
+	if !force {
+		return fmt.Errorf("Error: branch `%s' already exists and doesn't track pull request #%d\n(pass a different <BRANCH> name, or use `hub pr checkout --force %d` to reset it)", branch, pullRequest.Number, pullRequest.Number)
+	}
+
+	if args.Noop || !ui.IsTerminal(os.Stdin) || !ui.IsTerminal(os.Stdout) {
+		return nil
+	}
+
+	ui.Printf("Reset branch `%s' to the head of pull request #%d? [y/N] ", branch, pullRequest.Number)
+	var confirm string
+	prompt := bufio.NewScanner(os.Stdin)
+	if prompt.Scan() {
+		confirm = prompt.Text()
+	}
+	if strings.EqualFold(confirm, "y") || strings.EqualFold(confirm, "yes") {
+		return nil
+	}
+	return fmt.Errorf("Aborted: branch `%s' was left unchanged", branch)
+}
+
+// checkoutWords returns the positional arguments to git checkout, leaving out
+// the names of branches given to flags such as "-b" so that those are never
+// mistaken for a pull request URL.
+func checkoutWords(args *Args) []string {
+	words := []string{}
+	for i := 0; i < len(args.Params); i++ {
+		p := args.Params[i]
+		if p == "--" {
+			break
+		} else if p == "-b" || p == "-B" || p == "--orphan" {
+			i++
+		} else if !looksLikeFlag(p) {
+			words = append(words, p)
+		}
+	}
+	return words
+}
+
 func sanitizeCheckoutFlags(args *Args) error {
 	if i := args.IndexOfParam("-b"); i != -1 {
 		return fmt.Errorf("Unsupported flag -b when checking out pull request")
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"sort"
+	"time"
 
 	"github.com/github/hub/git"
 	"github.com/github/hub/github"
@@ -13,7 +14,7 @@ import (
 
 var cmdCiStatus = &Command{
 	Run:   ciStatus,
-	Usage: "ci-status [-v] [<COMMIT>]",
+	Usage: "ci-status [-v] [--watch [--interval <SECONDS>] [--timeout <SECONDS>]] [<COMMIT>]",
 	Long: `Display status of GitHub checks for a commit.
 
 ## Options:
@@ -37,6 +38,21 @@ var cmdCiStatus = &Command{
 		Enable colored output even if stdout is not a terminal. <WHEN> can be one
 		of "always" (default for '--color'), "never", or "auto" (default).
 
+	--watch
+		Keep polling the status checks until all of them have finished, then
+		display the result as usual. While waiting, a progress line is printed to
+		standard error: it is updated in place when standard error is a terminal,
+		and a new line is printed whenever the progress changes otherwise. If no
+		checks have started after 3 polls, stop waiting and report "no status".
+
+	--interval <SECONDS>
+		With '--watch', wait <SECONDS> between polls (default: 10). The interval
+		can't be shorter than 1 second.
+
+	--timeout <SECONDS>
+		With '--watch', stop waiting after <SECONDS> and display the result at that
+		point, which is usually "pending".
+
 	<COMMIT>
 		A commit SHA or branch name (default: "HEAD").
 
@@ -45,6 +61,7 @@ Possible outputs and exit statuses:
 - success, neutral: 0
 - failure, error, action_required, cancelled, timed_out: 1
 - pending: 2
+- no status: 3
 
 ## See also:
 
@@ -90,17 +107,34 @@ func ciStatus(cmd *Command, args *Args) {
 	project, err := localRepo.MainProject()
 	utils.Check(err)
 
-	sha, err := git.Ref(ref)
-	if err != nil {
-		err = fmt.Errorf("Aborted: no revision could be determined from '%s'", ref)
+	// the local checkout is unrelated to the repository given with `--repo`,
+	// so let the API resolve the ref instead
+	sha := ref
+	if github.RepoOverride == nil {
+		sha, err = git.Ref(ref)
+		if err != nil {
+			err = fmt.Errorf("Aborted: no revision could be determined from '%s'", ref)
+		}
+		utils.Check(err)
 	}
-	utils.Check(err)
 
 	if args.Noop {
 		ui.Printf("Would request CI status for %s\n", sha)
 	} else {
 		gh := github.NewClient(project.Host)
-		response, err := gh.FetchCIStatus(project, sha)
+		var response *github.CIStatusResponse
+		if args.Flag.Bool("--watch") {
+			interval := 10
+			if args.Flag.HasReceived("--interval") {
+				interval = args.Flag.Int("--interval")
+				if interval < 1 {
+					utils.Check(fmt.Errorf("Error: invalid --interval `%s'; expected a number of seconds of at least 1", args.Flag.Value("--interval")))
+				}
+			}
+			response, err = watchCIStatus(gh, project, sha, time.Duration(interval)*time.Second, time.Duration(args.Flag.Int("--timeout"))*time.Second)
+		} else {
+			response, err = gh.FetchCIStatus(project, sha)
+		}
 		utils.Check(err)
 
 		state := ""
@@ -140,6 +174,69 @@ func ciStatus(cmd *Command, args *Args) {
 	}
 }
 
+// ciWatchStartPolls is how many times watchCIStatus polls a commit without
+// any status checks before concluding that none are going to start
+const ciWatchStartPolls = 3
+
+// watchCIStatus polls the status checks of sha until none of them are pending,
+// reporting progress on stderr. A zero timeout means waiting indefinitely,
+// unless no checks start at all.
+func watchCIStatus(gh *github.Client, project *github.Project, sha string, interval, timeout time.Duration) (*github.CIStatusResponse, error) {
+	interactive := ui.IsTerminal(os.Stderr)
+	var deadline time.Time
+	if timeout > 0 {
+		deadline = time.Now().Add(timeout)
+	}
+
+	lastProgress := ""
+	for poll := 1; ; poll++ {
+		response, err := gh.FetchCIStatus(project, sha)
+		if err != nil {
+			return nil, err
+		}
+
+		pending := 0
+		for _, status := range response.Statuses {
+			if status.State == "pending" {
+				pending++
+			}
+		}
+		total := len(response.Statuses)
+		done := total > 0 && pending == 0
+		noChecks := total == 0 && poll >= ciWatchStartPolls
+		timedOut := !deadline.IsZero() && !time.Now().Before(deadline)
+
+		progress := "waiting for checks to start"
+		if total > 0 {
+			progress = fmt.Sprintf("%d of %d checks completed", total-pending, total)
+		}
+		if interactive {
+			ui.Noticef("\r\033[K%s", progress)
+		} else if progress != lastProgress {
+			ui.Noticeln(progress)
+		}
+		lastProgress = progress
+
+		if done || noChecks || timedOut {
+			if interactive {
+				ui.Noticef("\r\033[K")
+			}
+			if noChecks {
+				ui.Errorf("No checks started after %d polls\n", poll)
+			} else if !done {
+				ui.Errorf("Timed out after %s waiting for checks to complete\n", timeout)
+			}
+			return response, nil
+		}
+
+		wait := interval
+		if remaining := time.Until(deadline); !deadline.IsZero() && remaining < wait {
+			wait = remaining
+		}
+		time.Sleep(wait)
+	}
+}
+
 func ciVerboseFormat(statuses []github.CIStatus, formatString string, colorize bool) {
 	contextWidth := 0
 	for _, status := range statuses {
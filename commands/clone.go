@@ -6,22 +6,31 @@ import (
 	"strings"
 
 	"github.com/github/hub/github"
+	"github.com/github/hub/ui"
 	"github.com/github/hub/utils"
 )
 
 var cmdClone = &Command{
 	Run:          clone,
 	GitExtension: true,
-	Usage:        "clone [-p] [<OPTIONS>] [<USER>/]<REPOSITORY> [<DESTINATION>]",
+	Usage:        "clone [-p] [--set-upstream] [<OPTIONS>] [<USER>/]<REPOSITORY> [<DESTINATION>]",
 	Long: `Clone a repository from GitHub.
 
 ## Options:
 	-p
 		(Deprecated) Clone private repositories over SSH.
 
+	--set-upstream
+		If the cloned repository is a fork, add a remote named "upstream" for the
+		repository that it was forked from. Nothing is added for repositories that
+		aren't forks.
+
 	[<USER>/]<REPOSITORY>
 		<USER> defaults to your own GitHub username.
 
+		Prefix with "<ALIAS>:" to clone from the GitHub Enterprise host defined in
+		the "hub.hostAlias.<ALIAS>" git config. See "GitHub Enterprise" in hub(1).
+
 	<DESTINATION>
 		Directory name to clone into (default: <REPOSITORY>).
 
@@ -36,6 +45,17 @@ everything. See "HTTPS instead of git protocol" and "HUB_PROTOCOL" of hub(1).
 		$ hub clone rtomayko/ronn
 		> git clone git://github.com/rtomayko/ronn.git
 
+		$ hub clone --set-upstream dotfiles
+		> git clone git@github.com:YOUR_USER/dotfiles.git
+		> git -C dotfiles remote add upstream git://github.com/ORIGINAL_OWNER/dotfiles.git
+
+		$ hub clone --filter=blob:none github/hub
+		> git clone --filter=blob:none git://github.com/github/hub.git
+
+		$ git config hub.hostAlias.work git.my.org
+		$ hub clone work:myteam/myproject
+		> git clone git@git.my.org:myteam/myproject.git
+
 ## See also:
 
 hub-fork(1), hub(1), git-clone(1)
@@ -54,6 +74,13 @@ func clone(command *Command, args *Args) {
 
 func transformCloneArgs(args *Args) {
 	isSSH := parseClonePrivateFlag(args)
+	setUpstream := false
+	if args.Command != "submodule" {
+		if i := args.IndexOfParam("--set-upstream"); i != -1 {
+			args.RemoveParam(i)
+			setUpstream = true
+		}
+	}
 
 	// git help clone | grep -e '^ \+-.\+<'
 	p := utils.NewArgsParser()
@@ -64,6 +91,7 @@ func transformCloneArgs(args *Args) {
 		p.RegisterValue("--name")
 	} else {
 		p.RegisterValue("--config", "-c")
+		p.RegisterValue("--filter")
 		p.RegisterValue("--jobs", "-j")
 		p.RegisterValue("--origin", "-o")
 		p.RegisterValue("--reference-if-able")
@@ -76,16 +104,45 @@ func transformCloneArgs(args *Args) {
 	p.Parse(args.Params)
 
 	nameWithOwnerRegexp := regexp.MustCompile(NameWithOwnerRe)
-	for _, i := range p.PositionalIndices {
+	for n, i := range p.PositionalIndices {
 		a := args.Params[i]
-		if nameWithOwnerRegexp.MatchString(a) && !isCloneable(a) {
-			url := getCloneUrl(a, isSSH, args.Command != "submodule")
+		var url string
+		var project *github.Project
+		var repo *github.Repository
+		if hostname, nameWithOwner, ok := github.ExpandHostAlias(a); ok && nameWithOwnerRegexp.MatchString(nameWithOwner) && !isCloneable(a) {
+			url, project, repo = getCloneUrl(nameWithOwner, hostname, isSSH, args.Command != "submodule")
+			args.ReplaceParam(i, url)
+		} else if nameWithOwnerRegexp.MatchString(a) && !isCloneable(a) {
+			url, project, repo = getCloneUrl(a, "", isSSH, args.Command != "submodule")
 			args.ReplaceParam(i, url)
 		}
+
+		if setUpstream && repo != nil && repo.Parent != nil && !strings.HasSuffix(url, ".wiki.git") {
+			dest := repo.Name
+			if len(p.PositionalIndices) > n+1 {
+				dest = args.Params[p.PositionalIndices[n+1]]
+			}
+			addUpstreamRemote(args, dest, project.Host, repo.Parent)
+		}
 		break
 	}
 }
 
+// addUpstreamRemote arranges for a remote named "upstream" pointing to parent
+// to be added to the clone in dest
+func addUpstreamRemote(args *Args, dest, host string, parent *github.Repository) {
+	parentProject := github.NewProject(parent.Owner.Login, parent.Name, host)
+	parentURL := parentProject.GitURL("", "", parent.Private)
+
+	args.After("git", "-C", dest, "remote", "add", "upstream", parentURL)
+	if !args.Noop {
+		args.AfterFn(func() error {
+			ui.Printf("Added remote `upstream' for %s\n", parentProject)
+			return nil
+		})
+	}
+}
+
 func parseClonePrivateFlag(args *Args) bool {
 	if i := args.IndexOfParam("-p"); i != -1 {
 		args.RemoveParam(i)
@@ -95,7 +152,7 @@ func parseClonePrivateFlag(args *Args) bool {
 	return false
 }
 
-func getCloneUrl(nameWithOwner string, isSSH, allowSSH bool) string {
+func getCloneUrl(nameWithOwner, hostStr string, isSSH, allowSSH bool) (string, *github.Project, *github.Repository) {
 	name := nameWithOwner
 	owner := ""
 	if strings.Contains(name, "/") {
@@ -104,21 +161,21 @@ func getCloneUrl(nameWithOwner string, isSSH, allowSSH bool) string {
 		name = split[1]
 	}
 
-	var host *github.Host
 	if owner == "" {
 		config := github.CurrentConfig()
-		h, err := config.DefaultHost()
+		var host *github.Host
+		var err error
+		if hostStr != "" {
+			host, err = config.PromptForHost(hostStr)
+		} else {
+			host, err = config.DefaultHost()
+		}
 		if err != nil {
 			utils.Check(github.FormatError("cloning repository", err))
 		}
 
-		host = h
-		owner = host.User
-	}
-
-	var hostStr string
-	if host != nil {
 		hostStr = host.Host
+		owner = host.User
 	}
 
 	expectWiki := strings.HasSuffix(name, ".wiki")
@@ -152,5 +209,5 @@ func getCloneUrl(nameWithOwner string, isSSH, allowSSH bool) string {
 		isSSH = repo.Private || repo.Permissions.Push
 	}
 
-	return project.GitURL(name, owner, isSSH)
+	return project.GitURL(name, owner, isSSH), project, repo
 }
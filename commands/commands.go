@@ -5,7 +5,9 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/github/hub/git"
 	"github.com/github/hub/utils"
+	"github.com/kballard/go-shellquote"
 )
 
 var (
@@ -35,6 +37,14 @@ func (c *Command) Call(args *Args) (err error) {
 		return
 	}
 
+	defaultFlags, err := runCommand.defaultFlags()
+	if err != nil {
+		return
+	}
+	if len(defaultFlags) > 0 {
+		args.Params = append(defaultFlags, args.Params...)
+	}
+
 	if !c.GitExtension {
 		err = runCommand.parseArguments(args)
 		if err != nil {
@@ -70,7 +80,7 @@ func (c *Command) parseArguments(args *Args) error {
 		args.Terminator = args.Flag.HasTerminated
 		return nil
 	} else {
-		return fmt.Errorf("%s\n%s", err, c.Synopsis())
+		return &usageError{fmt.Sprintf("%s\n%s", err, c.Synopsis())}
 	}
 }
 
@@ -87,7 +97,19 @@ func (c *Command) UsageError(msg string) error {
 	if msg != "" {
 		nl = "\n"
 	}
-	return fmt.Errorf("%s%s%s", msg, nl, c.Synopsis())
+	return &usageError{fmt.Sprintf("%s%s%s", msg, nl, c.Synopsis())}
+}
+
+type usageError struct {
+	message string
+}
+
+func (e *usageError) Error() string {
+	return e.message
+}
+
+func (e *usageError) ExitCode() int {
+	return utils.ExitUsage
 }
 
 func (c *Command) Synopsis() string {
@@ -141,6 +163,48 @@ func (c *Command) Name() string {
 	return strings.Split(usageLine, " ")[0]
 }
 
+// configName is how the command is referred to in git config keys, such as
+// "pullRequest" for "pull-request" and "issueCreate" for "issue create"
+func (c *Command) configName() string {
+	name := c.Name()
+	if c.parentCommand != nil {
+		name = c.parentCommand.Name() + "-" + name
+	}
+	words := strings.Split(name, "-")
+	for i := 1; i < len(words); i++ {
+		if words[i] != "" {
+			words[i] = strings.ToUpper(words[i][:1]) + words[i][1:]
+		}
+	}
+	return strings.Join(words, "")
+}
+
+// defaultFlags reads the flags configured for the command in
+// "hub.<NAME>.flags". They are meant to be put in front of the flags given on
+// the command line, which thus take precedence. The value is split like a
+// shell would, and isn't expanded any further.
+func (c *Command) defaultFlags() ([]string, error) {
+	key := fmt.Sprintf("hub.%s.flags", c.configName())
+	value, _ := git.Config(key)
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+
+	flags, err := shellquote.Split(value)
+	if err != nil {
+		return nil, fmt.Errorf("Error: invalid %s `%s': %s", key, value, err)
+	}
+	if !strings.HasPrefix(flags[0], "-") {
+		return nil, fmt.Errorf("Error: invalid %s `%s'; expected flags such as \"--browse\"", key, value)
+	}
+	for _, flag := range flags {
+		if flag == "--" {
+			return nil, fmt.Errorf("Error: invalid %s `%s'; it can't contain `--'", key, value)
+		}
+	}
+	return flags, nil
+}
+
 func (c *Command) Runnable() bool {
 	return c.Run != nil
 }
@@ -6,6 +6,8 @@ import (
 	"testing"
 
 	"github.com/bmizerany/assert"
+	"github.com/github/hub/fixtures"
+	"github.com/github/hub/git"
 	"github.com/github/hub/ui"
 )
 
@@ -123,3 +125,42 @@ func TestSubCommandCall(t *testing.T) {
 	c.Call(args)
 	assert.Equal(t, "baz", result)
 }
+
+func TestCommandConfigName(t *testing.T) {
+	c := &Command{Usage: "pull-request -f"}
+	assert.Equal(t, "pullRequest", c.configName())
+
+	p := &Command{Usage: "issue"}
+	s := &Command{Key: "create"}
+	p.Use(s)
+	assert.Equal(t, "issue", p.configName())
+	assert.Equal(t, "issueCreate", s.configName())
+}
+
+func TestCommandCallWithDefaultFlags(t *testing.T) {
+	repo := fixtures.SetupTestRepo()
+	defer repo.TearDown()
+
+	var result []string
+	f := func(c *Command, args *Args) {
+		result = []string{args.Flag.Value("--state"), args.FirstParam()}
+	}
+	c := &Command{Usage: "foo", Long: "-s, --state <STATE>\n", Run: f}
+
+	git.SetConfig("hub.foo.flags", "--state 'all open'")
+	err := c.Call(NewArgs([]string{"foo", "bar"}))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []string{"all open", "bar"}, result)
+
+	err = c.Call(NewArgs([]string{"foo", "-s", "closed", "bar"}))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []string{"closed", "bar"}, result)
+
+	git.SetConfig("hub.foo.flags", "bar --state all")
+	err = c.Call(NewArgs([]string{"foo"}))
+	assert.Equal(t, "Error: invalid hub.foo.flags `bar --state all'; expected flags such as \"--browse\"", err.Error())
+
+	git.SetConfig("hub.foo.flags", "--state 'all")
+	err = c.Call(NewArgs([]string{"foo"}))
+	assert.NotEqual(t, nil, err)
+}
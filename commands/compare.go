@@ -22,6 +22,10 @@ compare [-uc] [-b <BASE>]
 	-u, --url
 		Print the URL instead of opening it.
 
+	--dump-url
+		Same as '--url'. This flag is accepted by all hub commands that open a
+		web browser, which is handy for scripts that run on headless machines.
+
 	-c, --copy
 		Put the URL to clipboard instead of opening it.
 
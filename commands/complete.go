@@ -0,0 +1,124 @@
+package commands
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/github/hub/git"
+	"github.com/github/hub/github"
+	"github.com/github/hub/ui"
+	"github.com/github/hub/utils"
+)
+
+// cmdComplete is invoked by the shell completion scripts in "etc/" at tab-time
+// to list candidate values that can't be known in advance. It's left out of
+// the list of hub commands.
+var cmdComplete = &Command{
+	Key: "__complete",
+	Run: runComplete,
+}
+
+// completionLimit is the number of pull requests offered for completion
+const completionLimit = 20
+
+var completionTypes = map[string]func(*Args) ([]string, error){
+	"remotes":  completeRemotes,
+	"branches": completeBranches,
+	"prs":      completePullRequests,
+}
+
+func init() {
+	CmdRunner.Use(cmdComplete)
+}
+
+func runComplete(cmd *Command, args *Args) {
+	args.NoForward()
+
+	kind := args.FirstParam()
+	complete, ok := completionTypes[kind]
+	if !ok {
+		utils.Check(fmt.Errorf("Error: unknown completion type `%s'", kind))
+	}
+
+	candidates, err := complete(args)
+	utils.Check(err)
+	for _, candidate := range candidates {
+		ui.Println(candidate)
+	}
+}
+
+// completeRemotes lists the names of git remotes
+func completeRemotes(args *Args) ([]string, error) {
+	remotes, err := github.Remotes()
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for _, remote := range remotes {
+		names = append(names, remote.Name)
+	}
+	return names, nil
+}
+
+// completeBranches lists local branches along with the remote-tracking
+// branches of every remote, the latter without the remote name prefix
+func completeBranches(args *Args) ([]string, error) {
+	branches, err := git.LocalBranches()
+	if err != nil {
+		return nil, err
+	}
+	remotes, err := github.Remotes()
+	if err != nil {
+		return nil, err
+	}
+	for _, remote := range remotes {
+		remoteBranches, err := git.RemoteBranches(remote.Name)
+		if err != nil {
+			return nil, err
+		}
+		branches = append(branches, remoteBranches...)
+	}
+
+	seen := map[string]bool{}
+	names := []string{}
+	for _, branch := range branches {
+		if branch != "" && !seen[branch] {
+			seen[branch] = true
+			names = append(names, branch)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// completePullRequests lists the most recently created open pull requests as
+// "NUMBER<Tab>TITLE". Nothing is listed unless hub is already authenticated,
+// since completion must never stop to ask for credentials.
+func completePullRequests(args *Args) ([]string, error) {
+	localRepo, err := github.LocalRepo()
+	if err != nil {
+		return nil, err
+	}
+	project, err := localRepo.MainProject()
+	if err != nil {
+		return nil, err
+	}
+
+	config := github.CurrentConfig()
+	if host := config.Find(project.Host); (host == nil || host.AccessToken == "") && config.DetectToken() == "" {
+		return nil, nil
+	}
+
+	gh := github.NewClient(project.Host)
+	pulls, err := gh.FetchPullRequests(project, map[string]interface{}{"state": "open"}, completionLimit, nil)
+	if err != nil {
+		return nil, err
+	}
+	candidates := []string{}
+	for _, pr := range pulls {
+		title := strings.Replace(pr.Title, "\t", " ", -1)
+		candidates = append(candidates, fmt.Sprintf("%d\t%s", pr.Number, title))
+	}
+	return candidates, nil
+}
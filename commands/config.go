@@ -0,0 +1,266 @@
+package commands
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/github/hub/git"
+	"github.com/github/hub/github"
+	"github.com/github/hub/ui"
+	"github.com/github/hub/utils"
+)
+
+var cmdConfig = &Command{
+	Run:          config,
+	GitExtension: true,
+	Usage: `
+config [--global] <KEY> [<VALUE>]
+config --hub
+`,
+	Long: `Read or write hub settings stored in git config.
+
+With <KEY>, print the value of a single setting. With <KEY> and <VALUE>, update
+the setting after validating the value.
+
+Arguments that don't name a hub setting are passed to git-config(1) as-is, so
+that 'git config' keeps working when hub is aliased as git.
+
+## Options:
+	--hub
+		List every setting that hub understands together with its effective
+		value, falling back to the default for settings that aren't set.
+
+	--global
+		Write the setting to the global git config instead of the configuration
+		of the current repository.
+
+	<KEY>
+		The name of a hub setting, with or without the "hub." prefix.
+
+## Settings:
+
+	* _hub.host_:
+		A GitHub Enterprise hostname that hub should treat as GitHub. May be
+		given multiple times in git config.
+
+	* _hub.hostAlias.<NAME>_:
+		A hostname that <NAME> stands for in "<NAME>:<OWNER>/<REPO>" arguments.
+		<NAME> can't contain a dot. Every configured alias is listed.
+
+	* _hub.keyring_:
+		Either "true" or "false" (default). When true, store OAuth tokens in the
+		macOS Keychain or, on Linux, via libsecret instead of the hub config file.
+
+	* _hub.netrc_:
+		Either "true" or "false" (default). When true, read API tokens from
+		"~/.netrc" for hosts that hub doesn't have a token for yet.
+
+	* _hub.noHttpsUpgrade_:
+		Either "true" or "false" (default). When true, keep using the protocol
+		of the existing git remotes when constructing URLs for new ones.
+
+	* _hub.postCheckout_:
+		A shell command to run after 'hub pr checkout' has checked out a pull
+		request.
+
+	* _hub.prepareMessage_:
+		A command that every pull request, issue, and release message composed
+		by hub is piped through before it's sent to GitHub.
+
+	* _hub.protectedBranches_:
+		Glob patterns of remote branches that 'hub prune-remote' never deletes
+		(default: "release/*"). Every configured pattern is listed.
+
+	* _hub.protocol_:
+		One of "https", "ssh", or "git" (default); the protocol used for git
+		remote URLs that hub constructs.
+
+	* _hub.reportCrash_:
+		Set to "never" to stop hub from offering to report crashes.
+
+	* _hub.useTokenForGit_:
+		Either "true" or "false" (default). When true, let the git commands that
+		hub runs authenticate to GitHub with hub's OAuth token.
+
+	* _hub.<COMMAND>.flags_:
+		Flags that are put in front of the ones given to <COMMAND>, where
+		<COMMAND> is the command name in camel case, such as "pullRequest" or
+		"prList". Every configured command is listed.
+
+Other keys starting with "hub." are passed to git-config(1) with a warning.
+
+## Examples:
+		$ hub config --hub
+		hub.host=
+		hub.keyring=false
+		hub.netrc=false
+		hub.noHttpsUpgrade=false
+		hub.postCheckout=
+		hub.prepareMessage=
+		hub.protectedBranches=release/*
+		hub.protocol=git
+		hub.reportCrash=
+		hub.useTokenForGit=false
+
+		$ hub config --global protocol https
+		> git config --global hub.protocol https
+
+## See also:
+
+hub(1), git-config(1)
+`,
+}
+
+type hubSetting struct {
+	Name    string
+	Default string
+	Values  []string
+	Multi   bool
+}
+
+var hubSettings = []hubSetting{
+	{Name: "hub.host", Multi: true},
+	{Name: "hub.keyring", Default: "false", Values: []string{"true", "false"}},
+	{Name: "hub.netrc", Default: "false", Values: []string{"true", "false"}},
+	{Name: "hub.noHttpsUpgrade", Default: "false", Values: []string{"true", "false"}},
+	{Name: "hub.postCheckout"},
+	{Name: "hub.prepareMessage"},
+	{Name: "hub.protectedBranches", Default: defaultProtectedBranches, Multi: true},
+	{Name: "hub.protocol", Default: "git", Values: []string{"https", "ssh", "git"}},
+	{Name: "hub.reportCrash", Values: []string{"never"}},
+	{Name: "hub.useTokenForGit", Default: "false", Values: []string{"true", "false"}},
+}
+
+func init() {
+	CmdRunner.Use(cmdConfig)
+}
+
+func config(command *Command, args *Args) {
+	params := args.Params
+	global := false
+	if len(params) > 0 && params[0] == "--global" {
+		global = true
+		params = params[1:]
+	}
+
+	if len(params) == 1 && params[0] == "--hub" && !global {
+		listHubSettings()
+		args.NoForward()
+		return
+	} else if len(params) == 0 {
+		return
+	}
+
+	setting := findHubSetting(params[0])
+	if setting == nil {
+		if strings.HasPrefix(strings.ToLower(params[0]), "hub.") {
+			ui.Errorf("Warning: `%s' isn't a known hub setting\n", params[0])
+		}
+		return
+	}
+
+	switch len(params) {
+	case 1:
+		for _, value := range hubSettingValues(setting) {
+			ui.Println(value)
+		}
+	case 2:
+		value := params[1]
+		if len(setting.Values) > 0 && !hasString(setting.Values, value) {
+			utils.Check(fmt.Errorf("Error: invalid value `%s' for %s; expected one of: %s", value, setting.Name, strings.Join(setting.Values, ", ")))
+		}
+		if args.Noop {
+			ui.Printf("Would set %s to %s\n", setting.Name, value)
+		} else if global {
+			utils.Check(git.SetGlobalConfig(setting.Name, value))
+		} else {
+			utils.Check(git.SetConfig(setting.Name, value))
+		}
+	default:
+		utils.Check(command.UsageError(""))
+	}
+
+	args.NoForward()
+}
+
+func listHubSettings() {
+	for i := range hubSettings {
+		setting := &hubSettings[i]
+		for _, value := range hubSettingValues(setting) {
+			ui.Printf("%s=%s\n", setting.Name, value)
+		}
+	}
+
+	aliases := github.HostAliases()
+	names := []string{}
+	for name := range aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		ui.Printf("%s%s=%s\n", hostAliasPrefix, name, aliases[name])
+	}
+
+	lines, _ := git.ConfigAll(`^hub\..*\.flags$`)
+	for _, line := range lines {
+		if matches := commandFlagsConfigRe.FindStringSubmatch(line); matches != nil {
+			ui.Printf("hub.%s.flags=%s\n", matches[1], matches[2])
+		}
+	}
+}
+
+var commandFlagsConfigRe = regexp.MustCompile(`^hub\.([^.\s]+)\.flags\s(.*)$`)
+
+// hubSettingValues returns the configured values of a setting, or its default
+// if it isn't set
+func hubSettingValues(setting *hubSetting) []string {
+	var values []string
+	if setting.Multi {
+		values, _ = git.ConfigAll(setting.Name)
+	} else if value, err := git.Config(setting.Name); err == nil {
+		values = []string{value}
+	}
+
+	if len(values) == 0 {
+		values = []string{setting.Default}
+	}
+	return values
+}
+
+const hostAliasPrefix = "hub.hostAlias."
+
+var commandFlagsRe = regexp.MustCompile(`(?i)^hub\.([^.]+)\.flags$`)
+
+func findHubSetting(name string) *hubSetting {
+	if !strings.Contains(name, ".") || strings.HasPrefix(strings.ToLower(name), "hostalias.") {
+		name = "hub." + name
+	}
+	if strings.HasPrefix(strings.ToLower(name), strings.ToLower(hostAliasPrefix)) {
+		alias := name[len(hostAliasPrefix):]
+		if alias == "" || strings.Contains(alias, ".") {
+			return nil
+		}
+		return &hubSetting{Name: hostAliasPrefix + strings.ToLower(alias)}
+	}
+	if matches := commandFlagsRe.FindStringSubmatch(name); matches != nil {
+		return &hubSetting{Name: "hub." + matches[1] + ".flags"}
+	}
+
+	for i := range hubSettings {
+		if strings.EqualFold(hubSettings[i].Name, name) {
+			return &hubSettings[i]
+		}
+	}
+	return nil
+}
+
+func hasString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -12,7 +12,7 @@ import (
 
 var cmdCreate = &Command{
 	Run:   create,
-	Usage: "create [-poc] [-d <DESCRIPTION>] [-h <HOMEPAGE>] [[<ORGANIZATION>/]<NAME>]",
+	Usage: "create [-poc] [--dump-url] [-d <DESCRIPTION>] [-h <HOMEPAGE>] [--add-readme] [--gitignore <TEMPLATE>] [--license <KEY>] [--set-default-branch] [[<ORGANIZATION>/]<NAME>]",
 	Long: `Create a new repository on GitHub and add a git remote for it.
 
 ## Options:
@@ -32,15 +32,43 @@ var cmdCreate = &Command{
 	-o, --browse
 		Open the new repository in a web browser.
 
+	--dump-url
+		With '--browse', print the URL of the new repository instead of opening
+		it in a web browser.
+
 	-c, --copy
 		Put the URL of the new repository to clipboard instead of printing it.
 
+	--add-readme
+		Have GitHub create an initial commit with a README file, so that the new
+		repository has a default branch that can be pulled right away.
+
+	--gitignore <TEMPLATE>
+		Have GitHub create an initial commit with a ".gitignore" file from
+		<TEMPLATE>, for example "Go" or "Node". See
+		<https://github.com/github/gitignore> for the available templates.
+
+	--license <KEY>
+		Have GitHub create an initial commit with a "LICENSE" file for the
+		license identified by <KEY>, for example "mit" or "apache-2.0".
+
+		With any of '--add-readme', '--gitignore' or '--license', the new remote
+		is fetched so that the files created on GitHub can be pulled into the
+		local repository.
+
+	--set-default-branch
+		If the local repository has no commits yet, rename the current branch to
+		match the default branch of the GitHub repository.
+
 	[<ORGANIZATION>/]<NAME>
 		The name for the repository on GitHub (default: name of the current working
 		directory).
 
 		Optionally, create the repository within <ORGANIZATION>.
 
+		Prefix with "<ALIAS>:" to create the repository on the GitHub Enterprise
+		host defined in the "hub.hostAlias.<ALIAS>" git config.
+
 ## Examples:
 		$ hub create
 		[ repo created on GitHub ]
@@ -50,6 +78,11 @@ var cmdCreate = &Command{
 		[ repo created in GitHub organization ]
 		> git remote add -f origin git@github.com:sinatra/recipes.git
 
+		$ hub create --add-readme --license mit
+		[ repo created with an initial commit on GitHub ]
+		> git remote add -f origin git@github.com:USER/REPO.git
+		$ git pull origin main
+
 ## See also:
 
 hub-init(1), hub(1)
@@ -80,7 +113,13 @@ func create(command *Command, args *Args) {
 	}
 
 	config := github.CurrentConfig()
-	host, err := config.DefaultHost()
+	var host *github.Host
+	if hostname, nameWithOwner, ok := github.ExpandHostAlias(newRepoName); ok {
+		newRepoName = nameWithOwner
+		host, err = config.PromptForHost(hostname)
+	} else {
+		host, err = config.DefaultHost()
+	}
 	if err != nil {
 		utils.Check(github.FormatError("creating repository", err))
 	}
@@ -105,7 +144,7 @@ func create(command *Command, args *Args) {
 				err = fmt.Errorf("Repository '%s' already exists and is public", repo.FullName)
 				utils.Check(err)
 			} else {
-				ui.Errorln("Existing repository detected")
+				ui.Noticeln("Existing repository detected")
 				project = foundProject
 			}
 		} else {
@@ -115,11 +154,28 @@ func create(command *Command, args *Args) {
 		repo = nil
 	}
 
+	initialFiles := []string{}
 	if repo == nil {
+		params := map[string]interface{}{
+			"description": args.Flag.Value("--description"),
+			"homepage":    args.Flag.Value("--homepage"),
+			"private":     flagCreatePrivate,
+		}
+		if args.Flag.Bool("--add-readme") {
+			params["auto_init"] = true
+			initialFiles = append(initialFiles, "README.md")
+		}
+		if template := args.Flag.Value("--gitignore"); template != "" {
+			params["gitignore_template"] = template
+			initialFiles = append(initialFiles, ".gitignore")
+		}
+		if license := args.Flag.Value("--license"); license != "" {
+			params["license_template"] = license
+			initialFiles = append(initialFiles, "LICENSE")
+		}
+
 		if !args.Noop {
-			flagCreateDescription := args.Flag.Value("--description")
-			flagCreateHomepage := args.Flag.Value("--homepage")
-			repo, err := gh.CreateRepository(project, flagCreateDescription, flagCreateHomepage, flagCreatePrivate)
+			repo, err = gh.CreateRepository(project, params)
 			utils.Check(err)
 			project = github.NewProject(repo.FullName, "", project.Host)
 		}
@@ -133,19 +189,62 @@ func create(command *Command, args *Args) {
 		originName = "origin"
 	}
 
+	// the default branch only tracks a remote that points to the new repository
+	trackRemote := true
 	if originRemote, err := localRepo.RemoteByName(originName); err == nil {
 		originProject, err := originRemote.Project()
 		if err != nil || !originProject.SameAs(project) {
 			ui.Errorf(`A git remote named "%s" already exists and is set to push to '%s'.\n`, originRemote.Name, originRemote.PushURL)
+			trackRemote = false
+		} else if len(initialFiles) > 0 {
+			args.Before("git", "fetch", originName)
 		}
 	} else {
 		url := project.GitURL("", "", true)
 		args.Before("git", "remote", "add", "-f", originName, url)
 	}
 
+	if repo != nil && repo.DefaultBranch != "" {
+		setupDefaultBranch(args, localRepo, originName, repo.DefaultBranch, trackRemote)
+	}
+
+	if len(initialFiles) > 0 && repo != nil {
+		ui.Noticef("Created %s in the initial commit of %s\n", strings.Join(initialFiles, ", "), repo.FullName)
+		if repo.DefaultBranch != "" {
+			ui.Noticef("(use `git pull %s %s` to get them)\n", originName, repo.DefaultBranch)
+		}
+	}
+
 	webUrl := project.WebURL("", "", "")
+	recordLastURL(args, webUrl)
 	args.NoForward()
 	flagCreateBrowse := args.Flag.Bool("--browse")
 	flagCreateCopy := args.Flag.Bool("--copy")
 	printBrowseOrCopy(args, webUrl, flagCreateBrowse, flagCreateCopy)
 }
+
+// setupDefaultBranch makes the local branch named after the default branch of
+// the GitHub repository track its remote counterpart, unless track is false.
+// With '--set-default-branch', the current branch of a repository without
+// commits is first renamed to match.
+func setupDefaultBranch(args *Args, localRepo *github.GitHubRepo, remoteName, defaultBranch string, track bool) {
+	currentBranch, err := localRepo.CurrentBranch()
+	if err != nil {
+		return
+	}
+	branchName := currentBranch.ShortName()
+
+	if branchName != defaultBranch && args.Flag.Bool("--set-default-branch") {
+		if _, err := git.Ref("HEAD"); err == nil {
+			ui.Errorf("Not renaming branch `%s' to `%s' because the repository already has commits\n", branchName, defaultBranch)
+		} else {
+			args.Before("git", "symbolic-ref", "HEAD", "refs/heads/"+defaultBranch)
+			branchName = defaultBranch
+		}
+	}
+
+	if track && branchName == defaultBranch {
+		args.After("git", "config", fmt.Sprintf("branch.%s.remote", branchName), remoteName)
+		args.After("git", "config", fmt.Sprintf("branch.%s.merge", branchName), "refs/heads/"+defaultBranch)
+	}
+}
@@ -110,12 +110,12 @@ func fork(cmd *Command, args *Args) {
 			currentProject, err := currentRemote.Project()
 			if err == nil {
 				if currentProject.SameAs(forkProject) {
-					ui.Printf("existing remote: %s\n", newRemoteName)
+					ui.Infof("existing remote: %s\n", newRemoteName)
 					return
 				}
 				if newRemoteName == "origin" {
 					// Assume user wants to follow github guides for collaboration
-					ui.Printf("renaming existing \"origin\" remote to \"upstream\"\n")
+					ui.Infof("renaming existing \"origin\" remote to \"upstream\"\n")
 					args.Before("git", "remote", "rename", "origin", "upstream")
 				}
 			}
@@ -125,7 +125,7 @@ func fork(cmd *Command, args *Args) {
 		args.Before("git", "remote", "set-url", newRemoteName, url)
 
 		args.AfterFn(func() error {
-			ui.Printf("new remote: %s\n", newRemoteName)
+			ui.Infof("new remote: %s\n", newRemoteName)
 			return nil
 		})
 	}
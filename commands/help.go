@@ -182,7 +182,7 @@ func lookupCmd(name string) *Command {
 func customCommands() []string {
 	cmds := []string{}
 	for n, c := range CmdRunner.All() {
-		if !c.GitExtension && !strings.HasPrefix(n, "--") {
+		if !c.GitExtension && !strings.HasPrefix(n, "--") && !strings.HasPrefix(n, "__") {
 			cmds = append(cmds, n)
 		}
 	}
@@ -203,7 +203,10 @@ These GitHub commands are provided by hub:
    fork           Make a fork of a remote repository on GitHub and add as remote
    issue          List or create GitHub issues
    pr             List or checkout GitHub pull requests
+   prune-remote   Delete remote branches merged into the default branch
    pull-request   Open a pull request on GitHub
    release        List or create GitHub releases
+   repo           Manage settings of a GitHub repository
    sync           Fetch git objects from upstream and update branches
+   whoami         Show the GitHub account that hub is authenticated as
 `
@@ -1,12 +1,17 @@
 package commands
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"os"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/github/hub/cmd"
 	"github.com/github/hub/git"
 	"github.com/github/hub/github"
 	"github.com/github/hub/ui"
@@ -17,10 +22,15 @@ var (
 	cmdIssue = &Command{
 		Run: listIssues,
 		Usage: `
-issue [-a <ASSIGNEE>] [-c <CREATOR>] [-@ <USER>] [-s <STATE>] [-f <FORMAT>] [-M <MILESTONE>] [-l <LABELS>] [-d <DATE>] [-o <SORT_KEY> [-^]] [-L <LIMIT>]
-issue show [-f <FORMAT>] <NUMBER>
-issue create [-oc] [-m <MESSAGE>|-F <FILE>] [--edit] [-a <USERS>] [-M <MILESTONE>] [-l <LABELS>]
+issue [-a <ASSIGNEE>] [-c <CREATOR>] [-@ <USER>] [-s <STATE>] [-f <FORMAT>|--jsonl] [--group-by <KEY>] [-M <MILESTONE>] [-l <LABELS>] [-d <DATE>] [--closed-since <DATE>] [-o <SORT_KEY> [-^]] [-L <LIMIT>]
+issue --search <QUERY> [-f <FORMAT>|--jsonl] [--group-by <KEY>] [-L <LIMIT>]
+issue show [-c] [-f <FORMAT>] <NUMBER>
+issue create [-oc] [--dump-url] [--idempotent] [-m <MESSAGE>|-F <FILE>|--edit-last] [--edit] [--body-from-commits[=<N>]] [-a <USERS>] [-M <MILESTONE>] [-l <LABELS> [--check-labels|--strict-labels]] [--project <OWNER>/<NUMBER>] [--parent <ISSUE>] [--strict]
 issue labels [--color]
+issue react [--remove] --reaction <REACTION> <NUMBER>
+issue edit [--title <TITLE>] [--body <BODY>|--body-file <FILE>] [--editor] <NUMBER>
+issue lock [--reason <REASON>] <NUMBER>
+issue unlock <NUMBER>
 `,
 		Long: `Manage GitHub Issues for the current repository.
 
@@ -31,18 +41,41 @@ With no arguments, show a list of open issues.
 	* _show_:
 		Show an existing issue specified by <NUMBER>.
 
+		When standard output is a terminal and "glow" or "mdcat" is available,
+		the issue is rendered through it as Markdown.
+
 	* _create_:
 		Open an issue in the current repository.
 
 	* _labels_:
 		List the labels available in this repository.
 
+	* _react_:
+		React to the issue or pull request specified by <NUMBER>, then show the
+		number of reactions it received so far.
+
+	* _edit_:
+		Change the title or the description of the issue specified by <NUMBER>,
+		then print its URL. This aborts if neither would change.
+
+	* _lock_:
+		Lock the conversation of the issue or pull request specified by <NUMBER>
+		so that only collaborators of the repository can comment on it.
+
+	* _unlock_:
+		Unlock the conversation of the issue or pull request specified by
+		<NUMBER>.
+
 ## Options:
 	-a, --assignee <ASSIGNEE>
 		In list mode, display only issues assigned to <ASSIGNEE>.
 
 	-a, --assign <USERS>
 		A comma-separated list of GitHub handles to assign to the created issue.
+		The flag can be repeated, and is also accepted as '--assignee'. Use "@me"
+		for the authenticated user, and <ORG>/<TEAM> to assign every member of a
+		team. A warning is printed for anyone who couldn't be assigned, such as
+		users without access to the repository.
 
 	-c, --creator <CREATOR>
 		Display only issues created by <CREATOR>.
@@ -89,6 +122,8 @@ With no arguments, show a list of open issues.
 
 		%Nc: number of comments wrapped in parentheses, or blank string if zero.
 
+		%cc: number of comments (same as %NC)
+
 		%cD: created date-only (no time of day)
 
 		%cr: created date, relative
@@ -109,6 +144,17 @@ With no arguments, show a list of open issues.
 
 		%%: a literal %
 
+	--jsonl
+		Output each issue as a raw JSON object on its own line as soon as it is
+		fetched, instead of formatting it with <FORMAT>.
+
+	--group-by <KEY>
+		Group the listed issues by "assignee", "label", or "milestone". Each
+		group starts with a header that names it and counts its issues, followed
+		by the issues formatted with <FORMAT>. An issue with several assignees or
+		labels is listed under each of them; issues without any are grouped last.
+		This can't be combined with '--jsonl'.
+
 	--color[=<WHEN>]
 		Enable colored output even if stdout is not a terminal. <WHEN> can be one
 		of "always" (default for '--color'), "never", or "auto" (default).
@@ -126,12 +172,28 @@ With no arguments, show a list of open issues.
 	-e, --edit
 		Further edit the contents of <FILE> in a text editor before submitting.
 
+	--edit-last
+		Reopen the message of the last issue that failed to be created or whose
+		creation was aborted in this repository in a text editor.
+
+	--body-from-commits[=<N>]
+		Append the messages of the last <N> commits on the current branch
+		(default: 1) to the issue description, after the text of <MESSAGE>,
+		<FILE>, or the issue template. Without '--message' or '--file', the
+		subject of the oldest of those commits becomes the title in the editor.
+
 	-o, --browse
 		Open the new issue in a web browser.
 
+	--dump-url
+		With '--browse', print the URL of the new issue instead of opening it in
+		a web browser.
+
 	-c, --copy
 		Put the URL of the new issue to clipboard instead of printing it.
 
+		When showing an issue, put its URL to clipboard.
+
 	-M, --milestone <ID>
 		Display only issues for a GitHub milestone with id <ID>.
 
@@ -142,8 +204,54 @@ With no arguments, show a list of open issues.
 
 		When opening an issue, add a comma-separated list of labels to this issue.
 
+	--check-labels
+		When opening an issue, compare <LABELS> against the labels defined in the
+		repository before doing anything else. Unknown labels are reported as
+		warnings, with a suggestion if a similarly named label exists, and are not
+		applied. Without this flag, GitHub creates any missing labels.
+
+	--strict-labels
+		Same as '--check-labels', but abort with an error if any label is unknown.
+
+	--project <OWNER>/<NUMBER>
+		When opening an issue, add it to the project board numbered <NUMBER> that
+		belongs to the user or organization <OWNER>. A failure to add the issue to
+		the project is reported as a warning.
+
+	--parent <ISSUE>
+		When opening an issue, make it a sub-issue of an existing issue. <ISSUE> is
+		either a number, such as "#123", or a reference to an issue in another
+		repository in the form of "<OWNER>/<REPO>#<NUMBER>". A failure to link the
+		issue to its parent, such as on GitHub Enterprise versions that don't
+		support sub-issues, is reported as a warning.
+
+	--strict
+		Abort with an error if the new issue could not be added to the project or
+		linked to its parent issue.
+
+	--idempotent
+		When the request to open the issue fails without a definitive answer from
+		GitHub, such as when the connection drops or the server responds with an
+		error status of 500 or above, check whether an issue with the same title was
+		opened by you in the last 10 minutes before retrying the request once. If
+		such an issue exists, it is used as the result instead of opening a
+		duplicate.
+
+		This is a best-effort check that never runs when the first request gets a
+		definitive response.
+
 	-d, --since <DATE>
 		Display only issues updated on or after <DATE> in ISO 8601 format.
+		Relative dates such as "3d" or "2 weeks ago" are accepted as well.
+
+	--closed-since <DATE>
+		Display only issues closed on or after <DATE>, which can be given in the
+		same formats as for '--since'. This implies '--state=closed' and can be
+		combined with other filters such as '--labels' or '--milestone'.
+
+		The GitHub API can't filter issues by closing date, so hub fetches the
+		closed issues updated since <DATE> and filters them locally. This may take
+		a while in busy repositories.
 
 	-o, --sort <KEY>
 		Sort displayed issues by "created" (default), "updated" or "comments".
@@ -154,12 +262,57 @@ With no arguments, show a list of open issues.
 	-L, --limit <LIMIT>
 		Display only the first <LIMIT> issues.
 
+	--min-comments <N>
+		Display only issues that have at least <N> comments. Combine with
+		'--sort comments' to list the most discussed issues first.
+
+		The GitHub API can't filter issues by comment count, so hub filters the
+		fetched issues locally. Issues that are filtered out don't count against
+		'--limit', which means that hub may need to fetch many pages to find
+		<LIMIT> matching issues.
+
 	--include-pulls
 		Include pull requests as well as issues.
 
+	--search <QUERY>
+		Display only issues matching <QUERY> using the GitHub search syntax, for
+		example "is:open label:bug sort:comments". The search is always scoped to
+		the current repository. Filtering and sorting flags such as '--state' or
+		'--labels' are ignored in favor of the query.
+
+		The search API returns at most 1000 results for any query.
+
 	--color
 		Enable colored output for labels list.
 
+	--reaction <REACTION>
+		The reaction to add to an issue or pull request: one of "+1" (also
+		"thumbsup"), "-1" (also "thumbsdown"), "laugh", "confused", "heart",
+		"hooray", "rocket", or "eyes".
+
+	--remove
+		Remove your <REACTION> from the issue or pull request instead of adding
+		it.
+
+	--reason <REASON>
+		In lock mode, the reason for locking the conversation: one of
+		"off-topic", "too-heated", "resolved", or "spam".
+
+	--title <TITLE>
+		In edit mode, the new title of the issue.
+
+	--body <BODY>
+		In edit mode, the new description of the issue in Markdown format.
+
+	--body-file <FILE>
+		In edit mode, read the new description from <FILE>. Pass "-" to read
+		from standard input.
+
+	--editor
+		In edit mode, open a text editor prefilled with the current title and
+		description, or with their new values if given. As with 'create', the
+		first block of text is the title and the rest is the description.
+
 ## See also:
 
 hub-pr(1), hub(1)
@@ -173,10 +326,15 @@ hub-pr(1), hub(1)
 		-@, --mentioned USER
 		-l, --labels LIST
 		-d, --since DATE
+		--closed-since DATE
 		-o, --sort KEY
 		-^, --sort-ascending
 		--include-pulls
 		-L, --limit N
+		--min-comments N
+		--search QUERY
+		--jsonl
+		--group-by KEY
 		--color
 `,
 	}
@@ -190,9 +348,19 @@ hub-pr(1), hub(1)
 		-M, --milestone M
 		-l, --labels LIST
 		-a, --assign USER
+		--assignee USER
 		-o, --browse
+		--dump-url
 		-c, --copy
 		-e, --edit
+		--edit-last
+		--body-from-commits
+		--project PROJECT
+		--parent ISSUE
+		--strict
+		--idempotent
+		--check-labels
+		--strict-labels
 `,
 	}
 
@@ -200,6 +368,7 @@ hub-pr(1), hub(1)
 		Key: "show",
 		Run: showIssue,
 		KnownFlags: `
+		-c, --copy
 		-f, --format FMT
 		--color
 `,
@@ -212,12 +381,52 @@ hub-pr(1), hub(1)
 		--color
 `,
 	}
+
+	cmdReactIssue = &Command{
+		Key: "react",
+		Run: reactToIssue,
+		KnownFlags: `
+		--reaction REACTION
+		--remove
+`,
+	}
+
+	cmdEditIssue = &Command{
+		Key:        "edit",
+		Run:        editIssue,
+		KnownFlags: issueEditFlags,
+	}
+
+	cmdLockIssue = &Command{
+		Key: "lock",
+		Run: lockIssue,
+		KnownFlags: `
+		--reason REASON
+`,
+	}
+
+	cmdUnlockIssue = &Command{
+		Key: "unlock",
+		Run: unlockIssue,
+	}
 )
 
+// issueEditFlags are shared by "issue edit" and "pr edit"
+const issueEditFlags = `
+		--title TITLE
+		--body BODY
+		--body-file FILE
+		--editor
+`
+
 func init() {
 	cmdIssue.Use(cmdShowIssue)
 	cmdIssue.Use(cmdCreateIssue)
 	cmdIssue.Use(cmdLabel)
+	cmdIssue.Use(cmdReactIssue)
+	cmdIssue.Use(cmdEditIssue)
+	cmdIssue.Use(cmdLockIssue)
+	cmdIssue.Use(cmdUnlockIssue)
 	CmdRunner.Use(cmdIssue)
 }
 
@@ -230,8 +439,19 @@ func listIssues(cmd *Command, args *Args) {
 
 	gh := github.NewClient(project.Host)
 
+	if groupBy := args.Flag.Value("--group-by"); groupBy != "" {
+		if !issueGroupByKeys[groupBy] {
+			utils.Check(fmt.Errorf("Error: invalid --group-by `%s'; expected \"assignee\", \"label\", or \"milestone\"", groupBy))
+		}
+		if args.Flag.Bool("--jsonl") {
+			utils.Check(fmt.Errorf("Error: --group-by can't be combined with --jsonl"))
+		}
+	}
+
 	if args.Noop {
 		ui.Printf("Would request list of issues for %s\n", project)
+	} else if args.Flag.HasReceived("--search") {
+		searchIssues(gh, project, args)
 	} else {
 		filters := map[string]interface{}{}
 		if args.Flag.HasReceived("--state") {
@@ -265,23 +485,57 @@ func listIssues(cmd *Command, args *Args) {
 
 		if args.Flag.HasReceived("--since") {
 			flagIssueSince := args.Flag.Value("--since")
-			if sinceTime, err := time.ParseInLocation("2006-01-02", flagIssueSince, time.Local); err == nil {
+			if sinceTime, err := parseDate(flagIssueSince, time.Now()); err == nil {
 				filters["since"] = sinceTime.Format(time.RFC3339)
 			} else {
 				filters["since"] = flagIssueSince
 			}
 		}
 
+		var closedSince time.Time
+		if args.Flag.HasReceived("--closed-since") {
+			if state, ok := filters["state"]; ok && state != "closed" {
+				utils.Check(fmt.Errorf("Error: --closed-since can't be combined with --state=%s", state))
+			}
+			closedSince, err = parseDate(args.Flag.Value("--closed-since"), time.Now())
+			if err != nil {
+				utils.Check(fmt.Errorf("Error: %s", err))
+			}
+			filters["state"] = "closed"
+			if _, ok := filters["since"]; !ok {
+				// an issue can't have been closed after it was last updated
+				filters["since"] = closedSince.Format(time.RFC3339)
+			}
+		}
+
 		flagIssueLimit := args.Flag.Int("--limit")
+		flagIssueMinComments := args.Flag.Int("--min-comments")
 		flagIssueIncludePulls := args.Flag.Bool("--include-pulls")
 		flagIssueFormat := "%sC%>(8)%i%Creset  %t%  l%n"
 		if args.Flag.HasReceived("--format") {
 			flagIssueFormat = args.Flag.Value("--format")
 		}
 
-		issues, err := gh.FetchIssues(project, filters, flagIssueLimit, func(issue *github.Issue) bool {
+		issueFilter := func(issue *github.Issue) bool {
+			if !closedSince.IsZero() && issue.ClosedAt.Before(closedSince) {
+				return false
+			}
+			if issue.Comments < flagIssueMinComments {
+				return false
+			}
 			return issue.PullRequest == nil || flagIssueIncludePulls
-		})
+		}
+
+		if args.Flag.Bool("--jsonl") {
+			err := gh.EachIssue(project, filters, flagIssueLimit, issueFilter, func(_ github.Issue, raw json.RawMessage) {
+				printJSONLine(raw)
+			})
+			utils.Check(err)
+			args.NoForward()
+			return
+		}
+
+		issues, err := gh.FetchIssues(project, filters, flagIssueLimit, issueFilter)
 		utils.Check(err)
 
 		maxNumWidth := 0
@@ -292,12 +546,146 @@ func listIssues(cmd *Command, args *Args) {
 		}
 
 		colorize := colorizeOutput(args.Flag.HasReceived("--color"), args.Flag.Value("--color"))
+		printIssues(issues, flagIssueFormat, args.Flag.Value("--group-by"), colorize)
+	}
+
+	args.NoForward()
+}
+
+var issueGroupByKeys = map[string]bool{
+	"assignee":  true,
+	"label":     true,
+	"milestone": true,
+}
+
+// printIssues prints each issue formatted with format, grouped under headers
+// by the assignees, labels, or milestone of the issues if groupBy is set
+func printIssues(issues []github.Issue, format, groupBy string, colorize bool) {
+	if groupBy == "" {
 		for _, issue := range issues {
-			ui.Print(formatIssue(issue, flagIssueFormat, colorize))
+			ui.Print(formatIssue(issue, format, colorize))
 		}
+		return
 	}
 
-	args.NoForward()
+	names := []string{}
+	groups := map[string][]github.Issue{}
+	for _, issue := range issues {
+		issueGroups := issueGroupNames(issue, groupBy)
+		if len(issueGroups) == 0 {
+			issueGroups = []string{""}
+		}
+		for _, name := range issueGroups {
+			if _, ok := groups[name]; !ok {
+				names = append(names, name)
+			}
+			groups[name] = append(groups[name], issue)
+		}
+	}
+	sort.SliceStable(names, func(i, j int) bool {
+		if names[i] == "" || names[j] == "" {
+			return names[j] == "" && names[i] != ""
+		}
+		return strings.ToLower(names[i]) < strings.ToLower(names[j])
+	})
+
+	for i, name := range names {
+		if i > 0 {
+			ui.Println()
+		}
+		header := name
+		if header == "" {
+			header = "No " + groupBy
+		}
+		ui.Printf("%s (%d)\n", header, len(groups[name]))
+		for _, issue := range groups[name] {
+			ui.Print(formatIssue(issue, format, colorize))
+		}
+	}
+}
+
+// issueGroupNames lists the groups that issue belongs to for '--group-by'
+func issueGroupNames(issue github.Issue, groupBy string) (names []string) {
+	switch groupBy {
+	case "assignee":
+		for _, assignee := range issue.Assignees {
+			names = append(names, assignee.Login)
+		}
+	case "label":
+		for _, label := range issue.Labels {
+			names = append(names, label.Name)
+		}
+	case "milestone":
+		if issue.Milestone != nil {
+			names = append(names, issue.Milestone.Title)
+		}
+	}
+	return
+}
+
+var issueSearchIgnoredFlags = []string{
+	"--state",
+	"--assignee",
+	"--milestone",
+	"--creator",
+	"--mentioned",
+	"--labels",
+	"--since",
+	"--closed-since",
+	"--sort",
+	"--sort-ascending",
+}
+
+func searchIssues(gh *github.Client, project *github.Project, args *Args) {
+	ignored := []string{}
+	for _, flag := range issueSearchIgnoredFlags {
+		if args.Flag.HasReceived(flag) {
+			ignored = append(ignored, flag)
+		}
+	}
+	if len(ignored) > 0 {
+		ui.Errorf("Warning: ignoring %s in favor of --search\n", strings.Join(ignored, ", "))
+	}
+
+	query := args.Flag.Value("--search")
+	flagIssueIncludePulls := args.Flag.Bool("--include-pulls")
+	if !flagIssueIncludePulls {
+		query = "is:issue " + query
+	}
+
+	flagIssueLimit := args.Flag.Int("--limit")
+	flagIssueMinComments := args.Flag.Int("--min-comments")
+	flagIssueFormat := "%sC%>(8)%i%Creset  %t%  l%n"
+	if args.Flag.HasReceived("--format") {
+		flagIssueFormat = args.Flag.Value("--format")
+	}
+
+	issueFilter := func(issue *github.Issue) bool {
+		if issue.Comments < flagIssueMinComments {
+			return false
+		}
+		return issue.PullRequest == nil || flagIssueIncludePulls
+	}
+
+	var total int
+	var err error
+	if args.Flag.Bool("--jsonl") {
+		total, err = gh.EachSearchIssue(project, query, flagIssueLimit, issueFilter, func(_ github.Issue, raw json.RawMessage) {
+			printJSONLine(raw)
+		})
+		utils.Check(err)
+	} else {
+		var issues []github.Issue
+		issues, total, err = gh.SearchIssues(project, query, flagIssueLimit, issueFilter)
+		utils.Check(err)
+
+		colorize := colorizeOutput(args.Flag.HasReceived("--color"), args.Flag.Value("--color"))
+		printIssues(issues, flagIssueFormat, args.Flag.Value("--group-by"), colorize)
+	}
+
+	if total > github.SearchResultsLimit && (flagIssueLimit <= 0 || flagIssueLimit > github.SearchResultsLimit) {
+		ui.Errorf("Warning: the search matched %d issues, but only the first %d can be displayed\n", total, github.SearchResultsLimit)
+	}
 }
 
 func formatIssuePlaceholders(issue github.Issue, colorize bool) map[string]string {
@@ -374,6 +762,7 @@ func formatIssuePlaceholders(issue github.Issue, colorize bool) map[string]strin
 		"Mt": milestoneTitle,
 		"NC": numComments,
 		"Nc": numCommentsWrapped,
+		"cc": numComments,
 		"cD": createdDate,
 		"cI": createdAtISO8601,
 		"ct": createdAtUnix,
@@ -476,6 +865,10 @@ func showIssue(cmd *Command, args *Args) {
 
 	args.NoForward()
 
+	if args.Flag.Bool("--copy") {
+		printBrowseOrCopy(args, issue.HtmlUrl, false, true)
+	}
+
 	colorize := colorizeOutput(args.Flag.HasReceived("--color"), args.Flag.Value("--color"))
 	if args.Flag.HasReceived("--format") {
 		flagShowIssueFormat := args.Flag.Value("--format")
@@ -490,27 +883,53 @@ func showIssue(cmd *Command, args *Args) {
 	commentsList, err := gh.FetchComments(project, issueNumber)
 	utils.Check(err)
 
-	ui.Printf("# %s%s\n\n", closed, issue.Title)
-	ui.Printf("* created by @%s on %s\n", issue.User.Login, issue.CreatedAt.String())
+	out := &bytes.Buffer{}
+	fmt.Fprintf(out, "# %s%s\n\n", closed, issue.Title)
+	fmt.Fprintf(out, "* created by @%s on %s\n", issue.User.Login, issue.CreatedAt.String())
 
 	if len(issue.Assignees) > 0 {
 		var assignees []string
 		for _, user := range issue.Assignees {
 			assignees = append(assignees, user.Login)
 		}
-		ui.Printf("* assignees: %s\n", strings.Join(assignees, ", "))
+		fmt.Fprintf(out, "* assignees: %s\n", strings.Join(assignees, ", "))
 	}
 
-	ui.Printf("\n%s\n", issue.Body)
+	if len(issue.Labels) > 0 {
+		var labels []string
+		for _, label := range issue.Labels {
+			labels = append(labels, label.Name)
+		}
+		fmt.Fprintf(out, "* labels: %s\n", strings.Join(labels, ", "))
+	}
+
+	fmt.Fprintf(out, "\n%s\n", issue.Body)
 
 	if issue.Comments > 0 {
-		ui.Printf("\n## Comments:\n")
+		fmt.Fprintf(out, "\n## Comments:\n")
 		for _, comment := range commentsList {
-			ui.Printf("\n### comment by @%s on %s\n\n%s\n", comment.User.Login, comment.CreatedAt.String(), comment.Body)
+			fmt.Fprintf(out, "\n### comment by @%s on %s\n\n%s\n", comment.User.Login, comment.CreatedAt.String(), comment.Body)
 		}
 	}
 
-	return
+	if !renderMarkdown(out.String()) {
+		ui.Print(out.String())
+	}
+}
+
+// renderMarkdown pipes content through a terminal Markdown renderer, if one is
+// installed and standard output is a terminal. Reports whether it succeeded.
+func renderMarkdown(content string) bool {
+	if !ui.IsTerminal(os.Stdout) {
+		return false
+	}
+	for _, name := range []string{"glow", "mdcat"} {
+		if path, err := utils.CommandPath(name); err == nil {
+			renderCmd := cmd.NewWithArray([]string{path, "-"})
+			return renderCmd.SpawnWithInput(content) == nil
+		}
+	}
+	return false
 }
 
 func createIssue(cmd *Command, args *Args) {
@@ -522,9 +941,24 @@ func createIssue(cmd *Command, args *Args) {
 
 	gh := github.NewClient(project.Host)
 
+	flagIssueLabels := commaSeparated(args.Flag.AllValues("--labels"))
+	strictLabels := args.Flag.Bool("--strict-labels")
+	if len(flagIssueLabels) > 0 && (strictLabels || args.Flag.Bool("--check-labels")) {
+		flagIssueLabels, err = checkLabels(gh, project, flagIssueLabels, strictLabels)
+		utils.Check(err)
+	}
+
+	flagIssueAssignees := commaSeparated(append(args.Flag.AllValues("--assign"), args.Flag.AllValues("--assignee")...))
+	if len(flagIssueAssignees) > 0 {
+		flagIssueAssignees, err = expandAssignees(gh, flagIssueAssignees)
+		utils.Check(err)
+	}
+
 	messageBuilder := &github.MessageBuilder{
-		Filename: "ISSUE_EDITMSG",
-		Title:    "issue",
+		Filename:  "ISSUE_EDITMSG",
+		Title:     "issue",
+		KeepDraft: true,
+		EditLast:  args.Flag.Bool("--edit-last"),
 	}
 
 	messageBuilder.AddCommentedSection(fmt.Sprintf(`Creating an issue for %s
@@ -555,6 +989,19 @@ text is the title and the rest is the description.`, project))
 
 	}
 
+	if args.Flag.Bool("--body-from-commits") {
+		limit, err := bodyFromCommitsLimit(args)
+		utils.Check(err)
+		if limit == 0 {
+			limit = 1
+		}
+		commits, err := git.RecentCommits(limit)
+		utils.Check(err)
+		body, err := commitsBody(commits, limit)
+		utils.Check(err)
+		messageBuilder.Message = appendParagraph(messageBuilder.Message, body)
+	}
+
 	title, body, err := messageBuilder.Extract()
 	utils.Check(err)
 
@@ -567,12 +1014,10 @@ text is the title and the rest is the description.`, project))
 		"body":  body,
 	}
 
-	flagIssueLabels := commaSeparated(args.Flag.AllValues("--labels"))
 	if len(flagIssueLabels) > 0 {
 		params["labels"] = flagIssueLabels
 	}
 
-	flagIssueAssignees := commaSeparated(args.Flag.AllValues("--assign"))
 	if len(flagIssueAssignees) > 0 {
 		params["assignees"] = flagIssueAssignees
 	}
@@ -581,13 +1026,56 @@ text is the title and the rest is the description.`, project))
 		params["milestone"] = flagIssueMilestone
 	}
 
+	projectOwner, projectNumber := "", 0
+	if flagIssueProject := args.Flag.Value("--project"); flagIssueProject != "" {
+		projectOwner, projectNumber, err = parseProjectV2Ref(flagIssueProject)
+		utils.Check(err)
+	}
+
+	flagIssueParent := args.Flag.Value("--parent")
+	if flagIssueParent != "" && !issueReferenceRe.MatchString(flagIssueParent) {
+		utils.Check(fmt.Errorf("Error: invalid parent issue `%s'; expected a number or <OWNER>/<REPO>#<NUMBER>", flagIssueParent))
+	}
+
 	args.NoForward()
 	if args.Noop {
 		ui.Printf("Would create issue `%s' for %s\n", params["title"], project)
 	} else {
+		gh.Idempotent = args.Flag.Bool("--idempotent")
 		issue, err := gh.CreateIssue(project, params)
 		utils.Check(err)
 
+		for _, login := range missingAssignees(flagIssueAssignees, issue.Assignees) {
+			ui.Errorf("Warning: %s could not be assigned; they might not be a collaborator of %s\n", login, project)
+		}
+
+		if projectNumber > 0 {
+			err = addIssueToProject(gh, issue, projectOwner, projectNumber)
+			if err != nil && args.Flag.Bool("--strict") {
+				utils.Check(err)
+			} else if err != nil {
+				ui.Errorf("Warning: could not add issue to project %s/%d\n%s\n", projectOwner, projectNumber, err)
+			}
+		}
+
+		if flagIssueParent != "" {
+			parentProject, parentNumber := parseIssueReference(project, flagIssueParent)
+			parent := "#" + parentNumber
+			if !parentProject.SameAs(project) {
+				parent = parentProject.String() + parent
+			}
+
+			err = gh.AddSubIssue(parentProject, parentNumber, issue.Id)
+			if err != nil && args.Flag.Bool("--strict") {
+				utils.Check(err)
+			} else if err != nil {
+				ui.Errorf("Warning: could not make #%d a sub-issue of %s; sub-issues might not be supported on %s\n%s\n", issue.Number, parent, parentProject.Host, err)
+			} else {
+				ui.Noticef("Added #%d as a sub-issue of %s\n", issue.Number, parent)
+			}
+		}
+
+		recordLastURL(args, issue.HtmlUrl)
 		flagIssueBrowse := args.Flag.Bool("--browse")
 		flagIssueCopy := args.Flag.Bool("--copy")
 		printBrowseOrCopy(args, issue.HtmlUrl, flagIssueBrowse, flagIssueCopy)
@@ -596,6 +1084,81 @@ text is the title and the rest is the description.`, project))
 	messageBuilder.Cleanup()
 }
 
+// expandAssignees resolves "@me" to the authenticated user and "<ORG>/<TEAM>"
+// to the members of that team, since teams can't be assigned directly. The
+// result has duplicates removed.
+func expandAssignees(gh *github.Client, assignees []string) ([]string, error) {
+	result := []string{}
+	seen := map[string]bool{}
+	add := func(login string) {
+		if !seen[strings.ToLower(login)] {
+			seen[strings.ToLower(login)] = true
+			result = append(result, login)
+		}
+	}
+
+	for _, assignee := range assignees {
+		if assignee == "@me" {
+			user, err := gh.CurrentUser()
+			if err != nil {
+				return nil, err
+			}
+			add(user.Login)
+		} else if split := strings.SplitN(assignee, "/", 2); len(split) == 2 {
+			members, err := gh.FetchTeamMembers(split[0], split[1])
+			if err != nil {
+				return nil, err
+			}
+			if len(members) == 0 {
+				ui.Errorf("Warning: team %s has no members to assign\n", assignee)
+			}
+			for _, member := range members {
+				add(member.Login)
+			}
+		} else {
+			add(strings.TrimPrefix(assignee, "@"))
+		}
+	}
+
+	return result, nil
+}
+
+// missingAssignees lists the requested logins that the API silently dropped
+func missingAssignees(requested []string, assigned []github.User) []string {
+	found := map[string]bool{}
+	for _, user := range assigned {
+		found[strings.ToLower(user.Login)] = true
+	}
+	missing := []string{}
+	for _, login := range requested {
+		if !found[strings.ToLower(login)] {
+			missing = append(missing, login)
+		}
+	}
+	return missing
+}
+
+var projectV2RefRe = regexp.MustCompile(`^([^/\s]+)/(\d+)$`)
+
+func parseProjectV2Ref(ref string) (owner string, number int, err error) {
+	matches := projectV2RefRe.FindStringSubmatch(ref)
+	if matches == nil {
+		err = fmt.Errorf("Error: invalid project `%s'; expected <OWNER>/<NUMBER>", ref)
+		return
+	}
+	owner = matches[1]
+	number, err = strconv.Atoi(matches[2])
+	return
+}
+
+func addIssueToProject(gh *github.Client, issue *github.Issue, owner string, number int) error {
+	projectID, err := gh.ProjectV2ID(owner, number)
+	if err != nil {
+		return err
+	}
+	return gh.AddProjectV2Item(projectID, issue.NodeId)
+}
+
 func listLabels(cmd *Command, args *Args) {
 	localRepo, err := github.LocalRepo()
 	utils.Check(err)
@@ -620,6 +1183,160 @@ func listLabels(cmd *Command, args *Args) {
 	}
 }
 
+// issueLockReasons maps the values accepted by '--reason' to the lock reasons
+// of the API
+var issueLockReasons = map[string]string{
+	"off-topic":  "off-topic",
+	"too-heated": "too heated",
+	"resolved":   "resolved",
+	"spam":       "spam",
+}
+
+func lockIssue(cmd *Command, args *Args) {
+	number := issueNumberParam(cmd, args, "issue")
+
+	reason := ""
+	if args.Flag.HasReceived("--reason") {
+		var ok bool
+		if reason, ok = issueLockReasons[strings.ToLower(args.Flag.Value("--reason"))]; !ok {
+			utils.Check(fmt.Errorf("Error: invalid lock reason `%s'; expected one of: off-topic, too-heated, resolved, spam", args.Flag.Value("--reason")))
+		}
+	}
+
+	localRepo, err := github.LocalRepo()
+	utils.Check(err)
+	project, err := localRepo.MainProject()
+	utils.Check(err)
+	gh := github.NewClient(project.Host)
+
+	args.NoForward()
+	if args.Noop {
+		ui.Printf("Would lock the conversation of #%d\n", number)
+		return
+	}
+
+	utils.Check(gh.LockIssue(project, number, reason))
+	if reason != "" {
+		ui.Printf("Locked the conversation of #%d as %s\n", number, reason)
+	} else {
+		ui.Printf("Locked the conversation of #%d\n", number)
+	}
+}
+
+func unlockIssue(cmd *Command, args *Args) {
+	number := issueNumberParam(cmd, args, "issue")
+
+	localRepo, err := github.LocalRepo()
+	utils.Check(err)
+	project, err := localRepo.MainProject()
+	utils.Check(err)
+	gh := github.NewClient(project.Host)
+
+	args.NoForward()
+	if args.Noop {
+		ui.Printf("Would unlock the conversation of #%d\n", number)
+		return
+	}
+
+	utils.Check(gh.UnlockIssue(project, number))
+	ui.Printf("Unlocked the conversation of #%d\n", number)
+}
+
+// issueNumberParam parses the single <NUMBER> argument of a subcommand, where
+// noun names what the number refers to in the error message
+func issueNumberParam(cmd *Command, args *Args, noun string) int {
+	if args.ParamsSize() != 1 {
+		utils.Check(cmd.UsageError(""))
+	}
+	number, err := strconv.Atoi(strings.TrimPrefix(args.GetParam(0), "#"))
+	if err != nil {
+		utils.Check(fmt.Errorf("Error: invalid %s number: %s", noun, args.GetParam(0)))
+	}
+	return number
+}
+
+// issueReactions lists the reactions that GitHub supports, in the order that
+// they are displayed in
+var issueReactions = []string{"+1", "-1", "laugh", "confused", "heart", "hooray", "rocket", "eyes"}
+
+var issueReactionAliases = map[string]string{
+	"thumbsup":   "+1",
+	"thumbsdown": "-1",
+}
+
+func reactToIssue(cmd *Command, args *Args) {
+	if !args.Flag.HasReceived("--reaction") {
+		utils.Check(cmd.UsageError(""))
+	}
+	number := issueNumberParam(cmd, args, "issue")
+
+	content := strings.ToLower(args.Flag.Value("--reaction"))
+	if alias, ok := issueReactionAliases[content]; ok {
+		content = alias
+	}
+	known := false
+	for _, reaction := range issueReactions {
+		known = known || reaction == content
+	}
+	if !known {
+		utils.Check(fmt.Errorf("Error: invalid reaction `%s'; expected one of: %s", args.Flag.Value("--reaction"), strings.Join(issueReactions, ", ")))
+	}
+
+	localRepo, err := github.LocalRepo()
+	utils.Check(err)
+	project, err := localRepo.MainProject()
+	utils.Check(err)
+	gh := github.NewClient(project.Host)
+	remove := args.Flag.Bool("--remove")
+
+	args.NoForward()
+	if args.Noop {
+		if remove {
+			ui.Printf("Would remove %s reaction from #%d\n", content, number)
+		} else {
+			ui.Printf("Would react with %s to #%d\n", content, number)
+		}
+		return
+	}
+
+	if remove {
+		user, err := gh.CurrentUser()
+		utils.Check(err)
+		reactions, err := gh.IssueReactions(project, number, content)
+		utils.Check(err)
+
+		removed := false
+		for _, reaction := range reactions {
+			if reaction.User != nil && strings.EqualFold(reaction.User.Login, user.Login) {
+				utils.Check(gh.DeleteIssueReaction(project, number, reaction.Id))
+				removed = true
+			}
+		}
+		if removed {
+			ui.Printf("Removed your %s reaction from #%d\n", content, number)
+		} else {
+			ui.Errorf("You haven't reacted with %s to #%d\n", content, number)
+		}
+	} else {
+		_, err := gh.ReactToIssue(project, number, content)
+		utils.Check(err)
+		ui.Printf("Reacted with %s to #%d\n", content, number)
+	}
+
+	counts, err := gh.IssueReactionCounts(project, number)
+	utils.Check(err)
+	summary := []string{}
+	for _, reaction := range issueReactions {
+		if count := counts.Count(reaction); count > 0 {
+			summary = append(summary, fmt.Sprintf("%s (%d)", reaction, count))
+		}
+	}
+	if len(summary) == 0 {
+		summary = append(summary, "none")
+	}
+	ui.Printf("Reactions: %s\n", strings.Join(summary, ", "))
+}
+
 func colorizeOutput(colorSet bool, when string) bool {
 	if !colorSet || when == "auto" {
 		return ui.IsTerminal(os.Stdout)
@@ -676,3 +1393,103 @@ func pickHighContrastTextColor(color *utils.Color) *utils.Color {
 	}
 	return utils.Black
 }
+
+func editIssue(cmd *Command, args *Args) {
+	editIssueOrPullRequest(cmd, args, false)
+}
+
+// editIssueOrPullRequest updates the title and body of an issue, or of a pull
+// request, which the issues API treats the same
+func editIssueOrPullRequest(cmd *Command, args *Args, isPullRequest bool) {
+	noun := "issue"
+	filename := "ISSUE_EDITMSG"
+	if isPullRequest {
+		noun = "pull request"
+		filename = "PULLREQ_EDITMSG"
+	}
+
+	flagEditTitle := args.Flag.HasReceived("--title")
+	flagEditBody := args.Flag.HasReceived("--body")
+	flagEditBodyFile := args.Flag.HasReceived("--body-file")
+	flagEditEditor := args.Flag.Bool("--editor")
+	if !(flagEditTitle || flagEditBody || flagEditBodyFile || flagEditEditor) {
+		utils.Check(cmd.UsageError(""))
+	}
+	number := issueNumberParam(cmd, args, noun)
+	if flagEditBody && flagEditBodyFile {
+		utils.Check(fmt.Errorf("Error: --body and --body-file can't be used together"))
+	}
+
+	localRepo, err := github.LocalRepo()
+	utils.Check(err)
+	project, err := localRepo.MainProject()
+	utils.Check(err)
+	gh := github.NewClient(project.Host)
+
+	issue, err := gh.FetchIssue(project, strconv.Itoa(number))
+	utils.Check(err)
+	if isPullRequest && issue.PullRequest == nil {
+		utils.Check(fmt.Errorf("Error: #%d is an issue, not a pull request", number))
+	}
+
+	title := issue.Title
+	body := issue.Body
+	if flagEditTitle {
+		title = args.Flag.Value("--title")
+	}
+	if flagEditBody {
+		body = args.Flag.Value("--body")
+	} else if flagEditBodyFile {
+		body, err = msgFromFile(args.Flag.Value("--body-file"))
+		utils.Check(err)
+	}
+
+	var messageBuilder *github.MessageBuilder
+	if flagEditEditor {
+		messageBuilder = &github.MessageBuilder{
+			Filename: filename,
+			Title:    noun,
+			Message:  fmt.Sprintf("%s\n\n%s", title, strings.Replace(body, "\r\n", "\n", -1)),
+			Edit:     true,
+		}
+		messageBuilder.AddCommentedSection(fmt.Sprintf(`Editing %s #%d for %s
+
+Write a message for this %s. The first block of
+text is the title and the rest is the description.`, noun, number, project, noun))
+
+		title, body, err = messageBuilder.Extract()
+		utils.Check(err)
+	}
+	if strings.TrimSpace(title) == "" {
+		utils.Check(fmt.Errorf("Aborting editing due to empty %s title", noun))
+	}
+
+	currentBody := issue.Body
+	if flagEditEditor {
+		// the editor normalizes line endings and surrounding whitespace
+		currentBody = strings.TrimSpace(strings.Replace(issue.Body, "\r\n", "\n", -1))
+	}
+
+	params := map[string]interface{}{}
+	if title != issue.Title {
+		params["title"] = title
+	}
+	if body != issue.Body && body != currentBody {
+		params["body"] = body
+	}
+	if len(params) == 0 {
+		utils.Check(fmt.Errorf("Aborted: %s #%d was left unchanged", noun, number))
+	}
+
+	args.NoForward()
+	if args.Noop {
+		ui.Printf("Would edit %s #%d\n", noun, number)
+		return
+	}
+
+	utils.Check(gh.UpdateIssue(project, number, params))
+	if messageBuilder != nil {
+		messageBuilder.Cleanup()
+	}
+	ui.Println(issue.HtmlUrl)
+}
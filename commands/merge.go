@@ -3,6 +3,7 @@ package commands
 import (
 	"fmt"
 	"regexp"
+	"strings"
 
 	"github.com/github/hub/github"
 	"github.com/github/hub/utils"
@@ -11,7 +12,7 @@ import (
 var cmdMerge = &Command{
 	Run:          merge,
 	GitExtension: true,
-	Usage:        "merge <PULLREQ-URL>",
+	Usage:        "merge [--no-ff] [-m <MESSAGE>|-F <FILE>] <PULLREQ-URL>",
 	Long: `Merge a pull request locally with a message like the GitHub Merge Button.
 
 This creates a local merge commit in the current branch, but does not actually
@@ -19,11 +20,34 @@ change the state of the pull request. However, the pull request will get
 auto-closed and marked as "merged" as soon as the newly created merge commit is
 pushed to the default branch of the remote repository.
 
+## Options:
+	--no-ff
+		Create a merge commit even when the merge could be a fast-forward. This
+		is the default unless '--ff', '--ff-only', or '--squash' is given.
+
+	-m, --message <MESSAGE>
+		Use <MESSAGE> instead of the pull request title as the body of the merge
+		commit message. The first line always references the pull request, as in
+		"Merge pull request #73 from jingweno/feature". Multiple '-m' values are
+		joined as separate paragraphs.
+
+	-F, --file <FILE>
+		Read the merge commit message body from <FILE>. Pass "-" to read from
+		standard input.
+
+	Other flags are passed to git-merge(1).
+
 ## Examples:
 		$ hub merge https://github.com/jingweno/gh/pull/73
 		> git fetch origin refs/pull/73/head
 		> git merge FETCH_HEAD --no-ff -m "Merge pull request #73 from jingweno/feature..."
 
+		$ hub merge https://github.com/jingweno/gh/pull/73 -m "Add the feature"
+		> git fetch origin refs/pull/73/head
+		> git merge FETCH_HEAD --no-ff -m "Merge pull request #73 from jingweno/feature
+
+		Add the feature"
+
 ## See also:
 
 hub-checkout(1), hub(1), git-merge(1)
@@ -42,7 +66,13 @@ func merge(command *Command, args *Args) {
 }
 
 func transformMergeArgs(args *Args) error {
-	words := args.Words()
+	params, messages, files := splitMergeMessageFlags(args.Params)
+	words := []string{}
+	for _, p := range params {
+		if !looksLikeFlag(p) {
+			words = append(words, p)
+		}
+	}
 	if len(words) == 0 {
 		return nil
 	}
@@ -82,19 +112,60 @@ func transformMergeArgs(args *Args) error {
 		return fmt.Errorf("Error: that fork is not available anymore")
 	}
 
+	body := pullRequest.Title
+	for _, file := range files {
+		content, err := msgFromFile(file)
+		if err != nil {
+			return err
+		}
+		messages = append(messages, strings.TrimSpace(content))
+	}
+	if len(messages) > 0 {
+		body = strings.Join(messages, "\n\n")
+	}
+
 	args.Before("git", "fetch", remote.Name, fmt.Sprintf("refs/pull/%s/head", id))
 
-	// Remove pull request URL
+	// Remove pull request URL and message flags
+	args.Params = params
 	idx := args.IndexOfParam(mergeURL)
 	args.RemoveParam(idx)
 
-	mergeMsg := fmt.Sprintf("Merge pull request #%s from %s/%s\n\n%s", id, headRepo.Owner.Login, branch, pullRequest.Title)
+	mergeMsg := fmt.Sprintf("Merge pull request #%s from %s/%s\n\n%s", id, headRepo.Owner.Login, branch, body)
 	args.AppendParams("FETCH_HEAD", "-m", mergeMsg)
 
-	if args.IndexOfParam("--ff-only") == -1 && args.IndexOfParam("--squash") == -1 && args.IndexOfParam("--ff") == -1 {
+	if args.IndexOfParam("--ff-only") == -1 && args.IndexOfParam("--squash") == -1 && args.IndexOfParam("--ff") == -1 && args.IndexOfParam("--no-ff") == -1 {
 		i := args.IndexOfParam("-m")
 		args.InsertParam(i, "--no-ff")
 	}
 
 	return nil
 }
+
+// splitMergeMessageFlags separates the "-m" and "-F" flags of git-merge from
+// the rest of params, returning their messages and file names
+func splitMergeMessageFlags(params []string) (rest, messages, files []string) {
+	for i := 0; i < len(params); i++ {
+		p := params[i]
+		switch {
+		case (p == "-m" || p == "--message" || p == "-F" || p == "--file") && i+1 < len(params):
+			i++
+			if p == "-m" || p == "--message" {
+				messages = append(messages, params[i])
+			} else {
+				files = append(files, params[i])
+			}
+		case strings.HasPrefix(p, "--message="):
+			messages = append(messages, strings.TrimPrefix(p, "--message="))
+		case strings.HasPrefix(p, "--file="):
+			files = append(files, strings.TrimPrefix(p, "--file="))
+		case strings.HasPrefix(p, "-m") && len(p) > 2:
+			messages = append(messages, p[2:])
+		case strings.HasPrefix(p, "-F") && len(p) > 2:
+			files = append(files, p[2:])
+		default:
+			rest = append(rest, p)
+		}
+	}
+	return
+}
@@ -1,10 +1,17 @@
 package commands
 
 import (
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 
+	"github.com/github/hub/cmd"
+	"github.com/github/hub/git"
 	"github.com/github/hub/github"
 	"github.com/github/hub/ui"
 	"github.com/github/hub/utils"
@@ -14,8 +21,9 @@ var (
 	cmdPr = &Command{
 		Run: printHelp,
 		Usage: `
-pr list [-s <STATE>] [-h <HEAD>] [-b <BASE>] [-o <SORT_KEY> [-^]] [-f <FORMAT>] [-L <LIMIT>]
-pr checkout <PR-NUMBER> [<BRANCH>]
+pr list [-s <STATE>] [-h <HEAD>] [-b <BASE>] [-l <LABELS>|--label-any <LABELS>] [-o <SORT_KEY> [-^]] [-f <FORMAT>|--jsonl|--json-fields <FIELDS>] [-L <LIMIT>]
+pr checkout [--detach|--force] [--track] [--recurse-submodules] [--commit-template] <PR-NUMBER>|<OWNER>:<HEAD> [<BRANCH>]
+pr edit [--title <TITLE>] [--body <BODY>|--body-file <FILE>] [--editor] <PR-NUMBER>
 `,
 		Long: `Manage GitHub Pull Requests for the current repository.
 
@@ -25,7 +33,17 @@ pr checkout <PR-NUMBER> [<BRANCH>]
 		List pull requests in the current repository.
 
 	* _checkout_:
-		Check out the head of a pull request in a new branch.
+		Check out the head of a pull request in a new branch. If the branch
+		already exists and tracks the same pull request, switch to it and
+		fast-forward it to the latest pull request head instead.
+
+		Instead of <PR-NUMBER>, the pull request may be given by its head
+		branch in the "<OWNER>:<HEAD>" format. The open pull request started
+		from that branch is checked out.
+
+	* _edit_:
+		Change the title or the description of a pull request, then print its
+		URL. This aborts if neither would change.
 
 ## Options:
 
@@ -40,6 +58,19 @@ pr checkout <PR-NUMBER> [<BRANCH>]
 	-b, --base <BRANCH>
 		Show pull requests based off the specified <BRANCH>.
 
+	-l, --labels <LABELS>
+		Show only pull requests that have all of the labels in the
+		comma-separated list <LABELS>.
+
+	--label-any <LABELS>
+		Show pull requests that have at least one of the labels in the
+		comma-separated list <LABELS>. Unlike '--labels', which requires every
+		label to be present, this matches any of them.
+
+		This uses the GitHub search API, which returns at most 1000 results.
+		Pull requests found this way lack the base and head branch information,
+		so the "%B", "%H", "%sB", and "%sH" format placeholders are empty.
+
 	-f, --format <FORMAT>
 		Pretty print the list of pull requests using format <FORMAT> (default:
 		"%pC%>(8)%i%Creset  %t%  l%n"). See the "PRETTY FORMATS" section of
@@ -120,6 +151,24 @@ pr checkout <PR-NUMBER> [<BRANCH>]
 
 		%%: a literal %
 
+	--jsonl
+		Output each pull request as a raw JSON object on its own line as soon as
+		it is fetched, instead of formatting it with <FORMAT>.
+
+	--json-fields <FIELDS>
+		Output the pull requests as a JSON array of objects that have only the
+		fields in the comma-separated list <FIELDS>, in that order. Just those
+		fields are requested from the GitHub GraphQL API. The available fields
+		are:
+
+		additions, assignees, author, baseRefName, baseRefOid, body,
+		changedFiles, closedAt, createdAt, deletions, headRefName, headRefOid,
+		isDraft, labels, mergedAt, number, state, title, updatedAt, url
+
+		The "author" field is a login name, while "assignees" and "labels" are
+		lists of login names and label names. This can't be combined with
+		'--labels' or with the "long-running" sort key.
+
 	--color[=<WHEN>]
 		Enable colored output even if stdout is not a terminal. <WHEN> can be one
 		of "always" (default for '--color'), "never", or "auto" (default).
@@ -133,6 +182,61 @@ pr checkout <PR-NUMBER> [<BRANCH>]
 	-L, --limit <LIMIT>
 		Display only the first <LIMIT> issues.
 
+	--detach
+		Check out the head of a pull request in detached HEAD state instead of
+		creating a local branch for it.
+
+	--force
+		Reset an existing local branch of the same name to the head of the pull
+		request, even if that branch doesn't track the pull request. When run
+		interactively, ask for confirmation first.
+
+	--track
+		When checking out a pull request from a fork, add a git remote named after
+		the owner of the fork unless a remote for it exists already, and have the
+		new branch track the contributor's branch on that remote. This lets
+		'git status' show how far the local branch is ahead of or behind the pull
+		request. Branches of same-repository pull requests always track their
+		branch on the base remote.
+
+	--recurse-submodules
+		After checking out the pull request, initialize and update submodules
+		recursively to match it, and report the ones that were updated. This does
+		nothing in repositories without submodules. Submodules configured with
+		"update = none" are left alone.
+
+	--commit-template
+		After checking out the pull request, point the "commit.template" setting
+		of the repository to a file that references the pull request, so that
+		follow-up commit messages mention it. The setting stays in place until
+		removed with 'git config --unset commit.template'.
+
+	--title <TITLE>
+		In edit mode, the new title of the pull request.
+
+	--body <BODY>
+		In edit mode, the new description of the pull request in Markdown
+		format.
+
+	--body-file <FILE>
+		In edit mode, read the new description from <FILE>. Pass "-" to read
+		from standard input.
+
+	--editor
+		In edit mode, open a text editor prefilled with the current title and
+		description, or with their new values if given. The first block of text
+		is the title and the rest is the description.
+
+## Configuration:
+
+	* 'hub.postCheckout':
+		A shell command to run after 'pr checkout' has checked out a pull
+		request, such as "npm install". It runs with 'sh', or 'cmd' on Windows,
+		in the root of the working tree with 'HUB_PR_NUMBER', 'HUB_PR_HEAD',
+		'HUB_PR_BASE', and 'HUB_PR_URL' set to the number, head branch, base
+		branch, and URL of the pull request. If the command fails, hub prints a
+		warning and keeps the checkout.
+
 ## See also:
 
 hub-issue(1), hub-pull-request(1), hub(1)
@@ -140,9 +244,15 @@ hub-issue(1), hub-pull-request(1), hub(1)
 	}
 
 	cmdCheckoutPr = &Command{
-		Key:        "checkout",
-		Run:        checkoutPr,
-		KnownFlags: "\n",
+		Key: "checkout",
+		Run: checkoutPr,
+		KnownFlags: `
+		--detach
+		--force
+		--track
+		--recurse-submodules
+		--commit-template
+`,
 	}
 
 	cmdListPulls = &Command{
@@ -150,11 +260,18 @@ hub-issue(1), hub-pull-request(1), hub(1)
 		Run:  listPulls,
 		Long: cmdPr.Long,
 	}
+
+	cmdEditPr = &Command{
+		Key:        "edit",
+		Run:        editPr,
+		KnownFlags: issueEditFlags,
+	}
 )
 
 func init() {
 	cmdPr.Use(cmdListPulls)
 	cmdPr.Use(cmdCheckoutPr)
+	cmdPr.Use(cmdEditPr)
 	CmdRunner.Use(cmdPr)
 }
 
@@ -162,6 +279,10 @@ func printHelp(command *Command, args *Args) {
 	utils.Check(command.UsageError(""))
 }
 
+func editPr(cmd *Command, args *Args) {
+	editIssueOrPullRequest(cmd, args, true)
+}
+
 func listPulls(cmd *Command, args *Args) {
 	localRepo, err := github.LocalRepo()
 	utils.Check(err)
@@ -171,6 +292,10 @@ func listPulls(cmd *Command, args *Args) {
 
 	gh := github.NewClient(project.Host)
 
+	if args.Flag.HasReceived("--head") {
+		utils.Check(checkPullRequestHead(args.Flag.Value("--head")))
+	}
+
 	args.NoForward()
 	if args.Noop {
 		ui.Printf("Would request list of pull requests for %s\n", project)
@@ -213,9 +338,33 @@ func listPulls(cmd *Command, args *Args) {
 		flagPullRequestFormat = "%pC%>(8)%i%Creset  %t%  l%n"
 	}
 
-	pulls, err := gh.FetchPullRequests(project, filters, flagPullRequestLimit, func(pr *github.PullRequest) bool {
-		return !(onlyMerged && pr.MergedAt.IsZero())
-	})
+	if args.Flag.HasReceived("--json-fields") {
+		if args.Flag.HasReceived("--format") || args.Flag.Bool("--jsonl") {
+			utils.Check(fmt.Errorf("Error: --json-fields can't be combined with --format or --jsonl"))
+		}
+		listPullsFields(gh, project, args, flagPullRequestLimit)
+		return
+	}
+
+	if args.Flag.HasReceived("--label-any") {
+		searchPulls(gh, project, args, flagPullRequestFormat)
+		return
+	}
+
+	flagPullRequestLabels := commaSeparated(args.Flag.AllValues("--labels"))
+	pullFilter := func(pr *github.PullRequest) bool {
+		return !(onlyMerged && pr.MergedAt.IsZero()) && hasAllLabels(pr, flagPullRequestLabels)
+	}
+
+	if args.Flag.Bool("--jsonl") {
+		err := gh.EachPullRequest(project, filters, flagPullRequestLimit, pullFilter, func(_ github.PullRequest, raw json.RawMessage) {
+			printJSONLine(raw)
+		})
+		utils.Check(err)
+		return
+	}
+
+	pulls, err := gh.FetchPullRequests(project, filters, flagPullRequestLimit, pullFilter)
 	utils.Check(err)
 
 	colorize := colorizeOutput(args.Flag.HasReceived("--color"), args.Flag.Value("--color"))
@@ -224,7 +373,343 @@ func listPulls(cmd *Command, args *Args) {
 	}
 }
 
+// pullRequestFields maps the fields allowed in '--json-fields' to their
+// GraphQL selection
+var pullRequestFields = map[string]string{
+	"additions":    "additions",
+	"assignees":    "assignees(first: 100) { nodes { login } }",
+	"author":       "author { login }",
+	"baseRefName":  "baseRefName",
+	"baseRefOid":   "baseRefOid",
+	"body":         "body",
+	"changedFiles": "changedFiles",
+	"closedAt":     "closedAt",
+	"createdAt":    "createdAt",
+	"deletions":    "deletions",
+	"headRefName":  "headRefName",
+	"headRefOid":   "headRefOid",
+	"isDraft":      "isDraft",
+	"labels":       "labels(first: 100) { nodes { name } }",
+	"mergedAt":     "mergedAt",
+	"number":       "number",
+	"state":        "state",
+	"title":        "title",
+	"updatedAt":    "updatedAt",
+	"url":          "url",
+}
+
+var pullRequestOrderFields = map[string]string{
+	"created":    "CREATED_AT",
+	"updated":    "UPDATED_AT",
+	"popularity": "COMMENTS",
+}
+
+var pullRequestGraphQLStates = map[string][]string{
+	"open":   {"OPEN"},
+	"closed": {"CLOSED", "MERGED"},
+	"merged": {"MERGED"},
+}
+
+// pullRequestFieldsQuery builds a query for the pull requests of a repository
+// that selects just the given fields
+func pullRequestFieldsQuery(fields []string, withHeadOwner bool) string {
+	selections := []string{}
+	for _, field := range fields {
+		selections = append(selections, pullRequestFields[field])
+	}
+	if withHeadOwner {
+		selections = append(selections, "headRepositoryOwner { login }")
+	}
+	return fmt.Sprintf(`query($owner: String!, $name: String!, $states: [PullRequestState!], $baseRefName: String, $headRefName: String, $labels: [String!], $orderBy: IssueOrder, $first: Int!, $after: String) {
+  repository(owner: $owner, name: $name) {
+    pullRequests(states: $states, baseRefName: $baseRefName, headRefName: $headRefName, labels: $labels, orderBy: $orderBy, first: $first, after: $after) {
+      nodes { %s }
+      pageInfo { hasNextPage endCursor }
+    }
+  }
+}`, strings.Join(selections, " "))
+}
+
+func parsePullRequestFields(value string) ([]string, error) {
+	fields := commaSeparated([]string{value})
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("Error: --json-fields needs at least one field")
+	}
+	for _, field := range fields {
+		if _, ok := pullRequestFields[field]; !ok {
+			known := []string{}
+			for name := range pullRequestFields {
+				known = append(known, name)
+			}
+			sort.Strings(known)
+			return nil, fmt.Errorf("Error: unknown field `%s' for --json-fields; expected one of: %s", field, strings.Join(known, ", "))
+		}
+	}
+	return fields, nil
+}
+
+func listPullsFields(gh *github.Client, project *github.Project, args *Args, limit int) {
+	fields, err := parsePullRequestFields(args.Flag.Value("--json-fields"))
+	utils.Check(err)
+	if args.Flag.HasReceived("--labels") {
+		utils.Check(fmt.Errorf("Error: --json-fields can't be combined with --labels; use --label-any instead"))
+	}
+
+	variables := map[string]interface{}{
+		"owner": project.Owner,
+		"name":  project.Name,
+	}
+
+	state := "open"
+	if args.Flag.HasReceived("--state") {
+		state = args.Flag.Value("--state")
+	}
+	if state != "all" {
+		states, ok := pullRequestGraphQLStates[state]
+		if !ok {
+			utils.Check(fmt.Errorf("Error: invalid state `%s'", state))
+		}
+		variables["states"] = states
+	}
+
+	if args.Flag.HasReceived("--base") {
+		variables["baseRefName"] = args.Flag.Value("--base")
+	}
+
+	// The GraphQL API filters by head branch name only, so look up the owner of
+	// the head repository to leave out pull requests from other forks
+	headOwner := ""
+	if args.Flag.HasReceived("--head") {
+		head := args.Flag.Value("--head")
+		headOwner = project.Owner
+		if split := strings.SplitN(head, ":", 2); len(split) == 2 {
+			headOwner, head = split[0], split[1]
+		}
+		variables["headRefName"] = head
+	}
+
+	if labels := commaSeparated(args.Flag.AllValues("--label-any")); len(labels) > 0 {
+		variables["labels"] = labels
+	}
+
+	sortKey := "created"
+	if args.Flag.HasReceived("--sort") {
+		sortKey = args.Flag.Value("--sort")
+	}
+	orderField, ok := pullRequestOrderFields[sortKey]
+	if !ok {
+		utils.Check(fmt.Errorf("Error: --json-fields can't be combined with --sort=%s", sortKey))
+	}
+	direction := "DESC"
+	if args.Flag.Bool("--sort-ascending") {
+		direction = "ASC"
+	}
+	variables["orderBy"] = map[string]string{"field": orderField, "direction": direction}
+
+	query := pullRequestFieldsQuery(fields, headOwner != "")
+	results := []map[string]interface{}{}
+	for {
+		perPage := 100
+		if limit > 0 && limit-len(results) < perPage {
+			perPage = limit - len(results)
+		}
+		variables["first"] = perPage
+
+		data := struct {
+			Repository *struct {
+				PullRequests struct {
+					Nodes    []map[string]interface{}
+					PageInfo struct {
+						HasNextPage bool
+						EndCursor   string
+					}
+				}
+			}
+		}{}
+		utils.Check(gh.GraphQL(query, variables, &data))
+		if data.Repository == nil {
+			utils.Check(fmt.Errorf("Error: repository %s doesn't exist", project))
+		}
+
+		for _, node := range data.Repository.PullRequests.Nodes {
+			if headOwner != "" && !strings.EqualFold(graphQLLogin(node["headRepositoryOwner"]), headOwner) {
+				continue
+			}
+			results = append(results, node)
+		}
+
+		page := data.Repository.PullRequests.PageInfo
+		if !page.HasNextPage || (limit > 0 && len(results) >= limit) {
+			break
+		}
+		variables["after"] = page.EndCursor
+	}
+
+	output := []json.RawMessage{}
+	for _, node := range results {
+		output = append(output, pullRequestFieldsJSON(node, fields))
+	}
+	encoded, err := json.Marshal(output)
+	utils.Check(err)
+	ui.Println(string(encoded))
+}
+
+// pullRequestFieldsJSON encodes the fields of a GraphQL pull request node in
+// the requested order, flattening users and labels into their names
+func pullRequestFieldsJSON(node map[string]interface{}, fields []string) json.RawMessage {
+	parts := []string{}
+	for _, field := range fields {
+		value := node[field]
+		switch field {
+		case "author":
+			if value != nil {
+				value = graphQLLogin(value)
+			}
+		case "assignees", "labels":
+			key := "login"
+			if field == "labels" {
+				key = "name"
+			}
+			names := []string{}
+			if connection, ok := value.(map[string]interface{}); ok {
+				nodes, _ := connection["nodes"].([]interface{})
+				for _, item := range nodes {
+					if object, ok := item.(map[string]interface{}); ok {
+						names = append(names, fmt.Sprint(object[key]))
+					}
+				}
+			}
+			value = names
+		}
+		name, _ := json.Marshal(field)
+		encoded, _ := json.Marshal(value)
+		parts = append(parts, string(name)+":"+string(encoded))
+	}
+	return json.RawMessage("{" + strings.Join(parts, ",") + "}")
+}
+
+func graphQLLogin(value interface{}) string {
+	if user, ok := value.(map[string]interface{}); ok {
+		if login, ok := user["login"].(string); ok {
+			return login
+		}
+	}
+	return ""
+}
+
+func hasAllLabels(pr *github.PullRequest, labels []string) bool {
+	for _, label := range labels {
+		found := false
+		for _, prLabel := range pr.Labels {
+			if strings.EqualFold(prLabel.Name, label) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+var searchSortKeys = map[string]string{
+	"created":    "created",
+	"updated":    "updated",
+	"popularity": "comments",
+	"comments":   "comments",
+}
+
+// searchPulls lists pull requests that have any of the labels given with
+// "--label-any" by translating the other list filters to search qualifiers
+func searchPulls(gh *github.Client, project *github.Project, args *Args, format string) {
+	qualifiers := []string{"is:pr"}
+
+	switch state := args.Flag.Value("--state"); state {
+	case "", "open":
+		qualifiers = append(qualifiers, "is:open")
+	case "closed", "merged":
+		qualifiers = append(qualifiers, "is:"+state)
+	case "all":
+	default:
+		qualifiers = append(qualifiers, "is:"+state)
+	}
+
+	if args.Flag.HasReceived("--base") {
+		qualifiers = append(qualifiers, "base:"+args.Flag.Value("--base"))
+	}
+	if args.Flag.HasReceived("--head") {
+		head := args.Flag.Value("--head")
+		if idx := strings.Index(head, ":"); idx >= 0 {
+			head = head[idx+1:]
+		}
+		qualifiers = append(qualifiers, "head:"+head)
+	}
+
+	quoted := []string{}
+	for _, label := range commaSeparated(args.Flag.AllValues("--label-any")) {
+		quoted = append(quoted, fmt.Sprintf("%q", label))
+	}
+	qualifiers = append(qualifiers, "label:"+strings.Join(quoted, ","))
+	for _, label := range commaSeparated(args.Flag.AllValues("--labels")) {
+		qualifiers = append(qualifiers, fmt.Sprintf("label:%q", label))
+	}
+
+	direction := "desc"
+	if args.Flag.Bool("--sort-ascending") {
+		direction = "asc"
+	}
+	sortKey := searchSortKeys[args.Flag.Value("--sort")]
+	if !args.Flag.HasReceived("--sort") {
+		sortKey = "created"
+	}
+	if sortKey != "" {
+		qualifiers = append(qualifiers, fmt.Sprintf("sort:%s-%s", sortKey, direction))
+	}
+
+	query := strings.Join(qualifiers, " ")
+	flagPullRequestLimit := args.Flag.Int("--limit")
+
+	var total int
+	var err error
+	if args.Flag.Bool("--jsonl") {
+		total, err = gh.EachSearchIssue(project, query, flagPullRequestLimit, nil, func(_ github.Issue, raw json.RawMessage) {
+			printJSONLine(raw)
+		})
+	} else {
+		colorize := colorizeOutput(args.Flag.HasReceived("--color"), args.Flag.Value("--color"))
+		total, err = gh.EachSearchIssue(project, query, flagPullRequestLimit, nil, func(issue github.Issue, _ json.RawMessage) {
+			ui.Print(formatPullRequest(pullRequestFromSearchResult(issue), format, colorize))
+		})
+	}
+	utils.Check(err)
+
+	if total > github.SearchResultsLimit && (flagPullRequestLimit <= 0 || flagPullRequestLimit > github.SearchResultsLimit) {
+		ui.Errorf("Warning: the search matched %d pull requests, but only the first %d can be displayed\n", total, github.SearchResultsLimit)
+	}
+}
+
+// pullRequestFromSearchResult converts an item returned by the issue search API
+// to a pull request. Search results don't include the base and head branches.
+func pullRequestFromSearchResult(issue github.Issue) github.PullRequest {
+	pr := github.PullRequest(issue)
+	if pr.Base == nil {
+		pr.Base = &github.PullRequestSpec{}
+	}
+	if pr.Head == nil {
+		pr.Head = &github.PullRequestSpec{}
+	}
+	if pr.MergedAt.IsZero() && issue.PullRequest != nil {
+		pr.MergedAt = issue.PullRequest.MergedAt
+	}
+	return pr
+}
+
 func checkoutPr(command *Command, args *Args) {
+	if github.RepoOverride != nil {
+		utils.Check(fmt.Errorf("Error: the `--repo' flag cannot be used with `pr checkout'"))
+	}
+
 	words := args.Words()
 	var newBranchName string
 
@@ -235,8 +720,13 @@ func checkoutPr(command *Command, args *Args) {
 	}
 
 	prNumberString := words[0]
-	_, err := strconv.Atoi(prNumberString)
-	utils.Check(err)
+	headSpec := ""
+	if _, err := strconv.Atoi(prNumberString); err != nil {
+		if !strings.Contains(prNumberString, ":") {
+			utils.Check(fmt.Errorf("Error: invalid pull request `%s'; expected <PR-NUMBER> or <OWNER>:<HEAD>", prNumberString))
+		}
+		headSpec = prNumberString
+	}
 
 	// Figure out the PR URL
 	localRepo, err := github.LocalRepo()
@@ -246,13 +736,190 @@ func checkoutPr(command *Command, args *Args) {
 	host, err := github.CurrentConfig().PromptForHost(baseProject.Host)
 	utils.Check(err)
 	client := github.NewClientWithHost(host)
-	pr, err := client.PullRequest(baseProject, prNumberString)
+
+	var pr *github.PullRequest
+	if headSpec != "" {
+		pr, err = findPullRequestByHead(client, baseProject, headSpec)
+	} else {
+		pr, err = client.PullRequest(baseProject, prNumberString)
+	}
 	utils.Check(err)
 
-	newArgs, err := transformCheckoutArgs(args, pr, newBranchName)
+	if args.Flag.Bool("--detach") {
+		if newBranchName != "" {
+			utils.Check(fmt.Errorf("Error: can't specify a branch name with --detach"))
+		}
+		if args.Flag.Bool("--track") {
+			utils.Check(fmt.Errorf("Error: the `--detach' and `--track' flags can't be used together"))
+		}
+		detachCheckoutPr(args, localRepo, pr)
+	} else {
+		newArgs, err := transformCheckoutArgs(args, pr, newBranchName, args.Flag.Bool("--force"), args.Flag.Bool("--track"))
+		utils.Check(err)
+
+		args.Replace(args.Executable, "checkout", newArgs...)
+	}
+
+	if args.Flag.Bool("--recurse-submodules") {
+		if args.Noop {
+			args.After("git", "submodule", "update", "--init", "--recursive")
+		} else {
+			args.AfterFn(updateSubmodules)
+		}
+	}
+
+	if args.Flag.Bool("--commit-template") {
+		setCommitTemplate(args, pr)
+	}
+
+	if hook, _ := git.Config("hub.postCheckout"); hook != "" {
+		runPostCheckoutHook(args, hook, pr)
+	}
+}
+
+// runPostCheckoutHook runs the `hub.postCheckout` command once the pull
+// request has been checked out. A failing command doesn't undo the checkout.
+func runPostCheckoutHook(args *Args, hook string, pr *github.PullRequest) {
+	hookCmd := cmd.NewShell(hook)
+	if args.Noop {
+		args.After(append([]string{hookCmd.Name}, hookCmd.Args...)...)
+		return
+	}
+
+	args.AfterFn(func() error {
+		hookCmd.WithEnv("HUB_PR_NUMBER", strconv.Itoa(pr.Number))
+		hookCmd.WithEnv("HUB_PR_HEAD", pr.Head.Ref)
+		hookCmd.WithEnv("HUB_PR_BASE", pr.Base.Ref)
+		hookCmd.WithEnv("HUB_PR_URL", pr.HtmlUrl)
+		if workdir, err := git.WorkdirName(); err == nil {
+			hookCmd.Dir = workdir
+		}
+
+		if err := hookCmd.Spawn(); err != nil {
+			ui.Errorf("Warning: the hub.postCheckout command failed: %s\n", err)
+		}
+		return nil
+	})
+}
+
+// setCommitTemplate configures "commit.template" to a file that references
+// the pull request once it has been checked out
+func setCommitTemplate(args *Args, pr *github.PullRequest) {
+	gitDir, err := git.Dir()
 	utils.Check(err)
+	templateFile := filepath.Join(gitDir, "hub-commit-template")
 
-	args.Replace(args.Executable, "checkout", newArgs...)
+	if args.Noop {
+		args.After("git", "config", "commit.template", templateFile)
+		return
+	}
+
+	args.AfterFn(func() error {
+		reference := fmt.Sprintf("%s/%s#%d", pr.Base.Repo.Owner.Login, pr.Base.Repo.Name, pr.Number)
+		content := fmt.Sprintf("\n\nRefs: %s\n", reference)
+		if err := ioutil.WriteFile(templateFile, []byte(content), 0644); err != nil {
+			return err
+		}
+		if err := git.SetConfig("commit.template", templateFile); err != nil {
+			return err
+		}
+		ui.Infof("Set commit.template to reference %s\n", reference)
+		return nil
+	})
+}
+
+var submoduleStatusRe = regexp.MustCompile(`^([ +U-])([0-9a-f]+) (\S+)`)
+
+// updateSubmodules checks out the commits of submodules recorded in HEAD and
+// reports the submodules that weren't at those commits before
+func updateSubmodules() error {
+	statuses, err := git.SubmoduleStatus()
+	if err != nil || len(statuses) == 0 {
+		return nil
+	}
+
+	outdated := []string{}
+	for _, status := range statuses {
+		if matches := submoduleStatusRe.FindStringSubmatch(status); matches != nil && matches[1] != " " {
+			outdated = append(outdated, matches[3])
+		}
+	}
+	if len(outdated) == 0 {
+		return nil
+	}
+
+	if err := git.Spawn("submodule", "--quiet", "update", "--init", "--recursive"); err != nil {
+		ui.Errorln("Warning: not all submodules could be updated")
+		return nil
+	}
+
+	statuses, _ = git.SubmoduleStatus()
+	upToDate := map[string]bool{}
+	for _, status := range statuses {
+		if matches := submoduleStatusRe.FindStringSubmatch(status); matches != nil && matches[1] == " " {
+			upToDate[matches[3]] = true
+		}
+	}
+	for _, path := range outdated {
+		if upToDate[path] {
+			ui.Infof("Updated submodule %s\n", path)
+		}
+	}
+	return nil
+}
+
+// checkPullRequestHead loosely validates a "BRANCH" or "OWNER:BRANCH" filter
+func checkPullRequestHead(head string) error {
+	if split := strings.SplitN(head, ":", 2); head == "" || len(split) == 2 && (split[0] == "" || split[1] == "") {
+		return fmt.Errorf("Error: invalid head `%s'; expected <BRANCH> or <OWNER>:<BRANCH>", head)
+	}
+	return nil
+}
+
+// findPullRequestByHead returns the only open pull request in project whose
+// head matches the "OWNER:BRANCH" spec
+func findPullRequestByHead(client *github.Client, project *github.Project, head string) (*github.PullRequest, error) {
+	filters := map[string]interface{}{
+		"head":  head,
+		"state": "open",
+	}
+	pulls, err := client.FetchPullRequests(project, filters, 0, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(pulls) {
+	case 0:
+		return nil, fmt.Errorf("Error: no open pull request found for `%s'\n(check the spelling of <OWNER>:<HEAD>, or use `hub pr list --state all --head %s` to find closed ones)", head, head)
+	case 1:
+		return &pulls[0], nil
+	default:
+		numbers := []string{}
+		for _, pr := range pulls {
+			numbers = append(numbers, fmt.Sprintf("#%d", pr.Number))
+		}
+		return nil, fmt.Errorf("Error: multiple open pull requests found for `%s': %s\n(pass the pull request number instead)", head, strings.Join(numbers, ", "))
+	}
+}
+
+func detachCheckoutPr(args *Args, localRepo *github.GitHubRepo, pr *github.PullRequest) {
+	baseRemote, err := localRepo.RemoteForRepo(pr.Base.Repo)
+	utils.Check(err)
+
+	args.Before("git", "fetch", baseRemote.Name, fmt.Sprintf("refs/pull/%d/head", pr.Number))
+	args.Replace(args.Executable, "checkout", "--detach", "FETCH_HEAD")
+
+	if !args.Noop {
+		args.AfterFn(func() error {
+			sha, err := git.Ref("HEAD")
+			if err != nil {
+				return err
+			}
+			ui.Infof("Checked out pull request #%d at %s in detached HEAD state.\n", pr.Number, sha[0:7])
+			ui.Errorln("(use `git checkout -b <BRANCH>` to create a branch from it)")
+			return nil
+		})
+	}
 }
 
 func formatPullRequest(pr github.PullRequest, format string, colorize bool) string {
@@ -0,0 +1,189 @@
+package commands
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/github/hub/git"
+	"github.com/github/hub/github"
+	"github.com/github/hub/ui"
+	"github.com/github/hub/utils"
+)
+
+var cmdPruneRemote = &Command{
+	Run:   pruneRemote,
+	Usage: "prune-remote [--remote <REMOTE>] [--dry-run] [-y]",
+	Long: `Delete branches on a remote that are merged into its default branch.
+
+After fetching from <REMOTE>, list its branches whose commits are all contained
+in the default branch, then delete them from <REMOTE> with 'git push --delete'.
+The default branch is looked up through the GitHub API rather than guessed from
+the local clone.
+The default branch itself and branches matching a pattern in the
+"hub.protectedBranches" git config are never deleted.
+
+Only branches that were merged with a merge commit are considered merged. A
+branch that points to a commit of the default branch itself, such as one that
+was just created from it or one that was fast-forwarded into it, has no commits
+of its own and is kept.
+
+## Options:
+	--remote <REMOTE>
+		The git remote to clean up. Defaults to "upstream", "github", or "origin",
+		in that order of preference.
+
+	--dry-run
+		List the branches that would be deleted without deleting them.
+
+	-y, --yes
+		Skip the confirmation prompt and immediately delete the branches.
+
+## Configuration:
+
+	* 'hub.protectedBranches':
+		A glob pattern of branch names to keep, such as "release/*". This setting
+		can be given multiple times. Defaults to "release/*" if not set.
+
+## Examples:
+		$ hub prune-remote --dry-run
+		Would delete 2 merged branches from origin:
+		  fix-typo
+		  feature/login
+
+		$ git config --add hub.protectedBranches "stable-*"
+		$ hub prune-remote --yes
+
+## See also:
+
+hub-sync(1), git-push(1), hub(1)
+`,
+}
+
+func init() {
+	CmdRunner.Use(cmdPruneRemote)
+}
+
+func pruneRemote(cmd *Command, args *Args) {
+	localRepo, err := github.LocalRepo()
+	utils.Check(err)
+
+	var remote *github.Remote
+	if remoteName := args.Flag.Value("--remote"); remoteName != "" {
+		if _, err := git.Config(fmt.Sprintf("remote.%s.url", remoteName)); err != nil {
+			utils.Check(fmt.Errorf("Error: no git remote named `%s'", remoteName))
+		}
+		remote, err = localRepo.RemoteByName(remoteName)
+	} else {
+		remote, err = localRepo.MainRemote()
+	}
+	utils.Check(err)
+
+	err = git.Spawn("fetch", "--prune", "--quiet", remote.Name)
+	utils.Check(err)
+
+	// deciding what's merged against a guessed default branch could delete the
+	// wrong branches, so ask the API
+	project, err := remote.Project()
+	utils.Check(err)
+	repo, err := github.NewClient(project.Host).Repository(project)
+	utils.Check(err)
+	defaultBranch := repo.DefaultBranch
+	fullDefaultBranch := fmt.Sprintf("refs/remotes/%s/%s", remote.Name, defaultBranch)
+	if _, err := git.Ref(fullDefaultBranch); err != nil {
+		utils.Check(fmt.Errorf("Error: the default branch `%s' of %s wasn't fetched", defaultBranch, remote.Name))
+	}
+
+	branches, err := git.RemoteBranches(remote.Name)
+	utils.Check(err)
+
+	// a branch that points to a commit made on the default branch itself has no
+	// commits of its own, such as one that was just created, so it's kept
+	defaultCommits, err := git.FirstParentList(fullDefaultBranch)
+	utils.Check(err)
+	ownCommit := map[string]bool{}
+	for _, sha := range defaultCommits {
+		ownCommit[sha] = true
+	}
+
+	protected := protectedBranchPatterns()
+	merged := []string{}
+	for _, branch := range branches {
+		if branch == defaultBranch || isProtectedBranch(branch, protected) {
+			continue
+		}
+		fullBranch := fmt.Sprintf("refs/remotes/%s/%s", remote.Name, branch)
+		if sha, err := git.Ref(fullBranch); err != nil || ownCommit[sha] {
+			continue
+		}
+		diff := &git.Range{A: fullBranch, B: fullDefaultBranch}
+		if diff.IsAncestor() {
+			merged = append(merged, branch)
+		}
+	}
+
+	args.NoForward()
+	if len(merged) == 0 {
+		ui.Printf("No merged branches to delete from %s.\n", remote.Name)
+		return
+	}
+
+	noun := "branches"
+	if len(merged) == 1 {
+		noun = "branch"
+	}
+	dryRun := args.Flag.Bool("--dry-run") || args.Noop
+	if dryRun {
+		ui.Printf("Would delete %d merged %s from %s:\n", len(merged), noun, remote.Name)
+	} else {
+		ui.Printf("Merged %s on %s:\n", noun, remote.Name)
+	}
+	for _, branch := range merged {
+		ui.Printf("  %s\n", branch)
+	}
+	if dryRun {
+		return
+	}
+
+	if !args.Flag.Bool("--yes") {
+		ui.Printf("Delete %d %s from %s (yes/N)? ", len(merged), noun, remote.Name)
+		answer := ""
+		scanner := bufio.NewScanner(os.Stdin)
+		if scanner.Scan() {
+			answer = strings.TrimSpace(scanner.Text())
+		}
+		utils.Check(scanner.Err())
+		if answer != "yes" {
+			utils.Check(fmt.Errorf("Please type 'yes' for confirmation."))
+		}
+	}
+
+	pushArgs := append([]string{"push", "--delete", remote.Name}, merged...)
+	err = git.Spawn(pushArgs...)
+	utils.Check(err)
+
+	ui.Printf("Deleted %d %s from %s.\n", len(merged), noun, remote.Name)
+}
+
+// defaultProtectedBranches is the pattern of branches that are protected when
+// "hub.protectedBranches" isn't set
+const defaultProtectedBranches = "release/*"
+
+func protectedBranchPatterns() []string {
+	patterns, _ := git.ConfigAll("hub.protectedBranches")
+	if len(patterns) == 0 {
+		patterns = []string{defaultProtectedBranches}
+	}
+	return patterns
+}
+
+func isProtectedBranch(branch string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, branch); matched {
+			return true
+		}
+	}
+	return false
+}
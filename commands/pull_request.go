@@ -2,6 +2,7 @@ package commands
 
 import (
 	"fmt"
+	"net/url"
 	"os"
 	"regexp"
 	"strconv"
@@ -10,15 +11,17 @@ import (
 
 	"github.com/github/hub/git"
 	"github.com/github/hub/github"
+	"github.com/github/hub/ui"
 	"github.com/github/hub/utils"
 )
 
 var cmdPullRequest = &Command{
 	Run: pullRequest,
 	Usage: `
-pull-request [-focp] [-b <BASE>] [-h <HEAD>] [-r <REVIEWERS> ] [-a <ASSIGNEES>] [-M <MILESTONE>] [-l <LABELS>]
-pull-request -m <MESSAGE> [--edit]
-pull-request -F <FILE> [--edit]
+pull-request [-focp] [--fill] [--web] [--dump-url] [--no-default-message] [--strict] [--idempotent] [--allow-empty] [--no-maintainer-edits] [-b <BASE>] [-h <HEAD>] [--base-remote <REMOTE>] [--head-remote <REMOTE>|--head-repo <OWNER>/<REPO>] [-r <REVIEWERS> ] [--reviewers-from-codeowners] [-a <ASSIGNEES>] [-M <MILESTONE>] [-l <LABELS> [--check-labels|--strict-labels]] [--copy-labels-from <ISSUE>] [--copy-milestone-from <ISSUE>] [--references <PR-OR-SHA>]
+pull-request -m <MESSAGE> [--edit] [--body-from-commits[=<N>]]
+pull-request -F <FILE> [--edit] [--body-from-commits[=<N>]]
+pull-request --edit-last
 pull-request -i <ISSUE>
 `,
 	Long: `Create a GitHub Pull Request.
@@ -39,12 +42,34 @@ pull-request -i <ISSUE>
 		Use the message from the first commit on the branch as pull request title
 		and description without opening a text editor.
 
+	--fill
+		Set the pull request title and description from the commits between the
+		base and the head branch without opening a text editor. A single commit
+		provides both; with multiple commits, the title is made from the name of
+		the head branch and the description lists the commit subjects, oldest
+		first. Use '--edit' to review the result in a text editor. This can't be
+		combined with '--message' or '--file'.
+
+	--no-default-message
+		Open the text editor without filling in the message of the commit on the
+		branch or the pull request template, so that the message can be written
+		from scratch. The commented-out summary of changes is still shown.
+
 	-F, --file <FILE>
 		Read the pull request title and description from <FILE>.
 
 	-e, --edit
 		Further edit the contents of <FILE> in a text editor before submitting.
 
+	--edit-last
+		Reopen the message of the last pull request that failed to be created or
+		whose creation was aborted in this repository in a text editor.
+
+	--body-from-commits[=<N>]
+		Append the messages of the commits between the base and the head branch,
+		or only of the last <N> of them, to the pull request description, after
+		the text of <MESSAGE>, <FILE>, or the pull request template.
+
 	-i, --issue <ISSUE>
 		Convert <ISSUE> (referenced by its number) to a pull request.
 
@@ -56,12 +81,30 @@ pull-request -i <ISSUE>
 	-o, --browse
 		Open the new pull request in a web browser.
 
+	--web
+		Instead of creating the pull request, open the form for creating it on
+		GitHub in a web browser, prefilled with the title and description given
+		via '--message', '--file', or '--fill', and with the reviewers,
+		assignees, milestone, and labels given on the command line. The text
+		editor is only opened when '--edit' is given. This can't be combined with
+		'--issue'.
+
+	--dump-url
+		With '--browse' or '--web', print the URL of the new pull request or of
+		the form instead of opening it in a web browser.
+
 	-c, --copy
 		Put the URL of the new pull request to clipboard instead of printing it.
 
 	-p, --push
 		Push the current branch to <HEAD> before creating the pull request.
 
+	--allow-empty
+		If GitHub rejects the pull request because there are no commits between
+		<BASE> and <HEAD>, create an empty commit on the current branch using the
+		pull request title as message, push it to <HEAD>, and try again. This is
+		useful for opening a pull request to discuss changes before writing them.
+
 	-b, --base <BASE>
 		The base branch in the "[<OWNER>:]<BRANCH>" format. Defaults to the default
 		branch of the upstream repository (usually "master").
@@ -73,9 +116,33 @@ pull-request -i <ISSUE>
 		The head branch in "[<OWNER>:]<BRANCH>" format. Defaults to the currently
 		checked out branch.
 
+	--head-repo <OWNER>/<REPO>
+		The repository that the head branch lives in. Use this when a fork has a
+		different name than the base repository. This takes precedence over the
+		<OWNER> part of '--head', which is then only used for the branch name. The
+		repository must be in the same fork network as the base repository.
+
+	--base-remote <REMOTE>
+		Take the base repository from the URL of the git remote named <REMOTE>
+		instead of an <OWNER> in '--base'. The branch still comes from '--base',
+		or defaults to the default branch of that remote.
+
+	--head-remote <REMOTE>
+		Take the head repository from the URL of the git remote named <REMOTE>
+		instead of an <OWNER> in '--head'. The branch still comes from '--head',
+		or defaults to the current branch. This can't be combined with
+		'--head-repo'.
+
 	-r, --reviewer <USERS>
 		A comma-separated list of GitHub handles to request a review from.
 
+	--reviewers-from-codeowners
+		Also request reviews from the code owners of the files changed between
+		the base and head branches, as listed in the CODEOWNERS file of the
+		working tree. As on GitHub, the last matching pattern in the file takes
+		precedence. Owners given as email addresses are skipped, and so are you.
+		Changed files that have no code owners are reported as a warning.
+
 	-a, --assign <USERS>
 		A comma-separated list of GitHub handles to assign to this pull request.
 
@@ -87,6 +154,61 @@ pull-request -i <ISSUE>
 		Add a comma-separated list of labels to this pull request. Labels will be
 		created if they do not already exist.
 
+	--check-labels
+		Compare <LABELS> against the labels defined in the repository before
+		creating the pull request. Unknown labels are reported as warnings, with a
+		suggestion if a similarly named label exists, and are not applied.
+
+	--strict-labels
+		Same as '--check-labels', but abort with an error if any label is unknown.
+
+	--copy-labels-from <ISSUE>
+		Also add the labels of an existing issue or pull request, such as when
+		opening a follow-up or backport pull request. <ISSUE> is either a number in
+		the base repository, such as "#123", or a reference to another repository in
+		the form of "<OWNER>/<REPO>#<NUMBER>". Labels copied from another repository
+		that don't exist in the base repository are reported as warnings and are not
+		applied.
+
+	--copy-milestone-from <ISSUE>
+		Add the pull request to the milestone of an existing issue or pull request,
+		given in the same form as for '--copy-labels-from'. When copying from
+		another repository, the milestone is looked up by its title. This is ignored
+		if '--milestone' is given.
+
+	--references <PR-OR-SHA>
+		Append a section to the pull request description listing where the changes
+		were cherry-picked from. <PR-OR-SHA> is either a pull request number, such as
+		"#123", or a commit SHA. A number without "#" is taken as a commit if one
+		matches it. This option can be given multiple times.
+
+		The section is delimited by HTML comments so that it gets replaced rather
+		than duplicated when the same description is submitted again.
+
+	--no-maintainer-edits
+		Don't allow maintainers of the base repository to push commits to the head
+		branch of a pull request opened from a fork. GitHub allows such edits by
+		default, which grants anyone with push access to the base repository write
+		access to the head branch; opt out if that branch is also used for other
+		purposes, or if it carries changes that only you should sign off on.
+
+	--idempotent
+		When the request to create the pull request fails without a definitive
+		answer from GitHub, such as when the connection drops or the server
+		responds with an error status of 500 or above, look for an open pull
+		request with the same head, base, and title that was created in the last 10
+		minutes before retrying the request once. If one exists, it is used as the
+		result instead of creating a duplicate.
+
+		This is a best-effort check that never runs when the first request gets a
+		definitive response.
+
+	--strict
+		Abort with an error if labels, assignees, milestone, or reviewers could not
+		be applied to the newly created pull request. Without this flag, such
+		failures are reported as warnings, the pull request URL is still printed,
+		and hub exits with status 7.
+
 ## Examples:
 		$ hub pull-request
 		[ opens a text editor for writing title and message ]
@@ -98,6 +220,10 @@ pull-request -i <ISSUE>
 		$ hub pull-request --browse -m "My title"
 		[ creates a pull request with the given title and opens it in a browser ]
 
+		$ hub pull-request --web --fill -r octocat -l bug
+		[ opens the form for creating a pull request in a browser, prefilled with
+		  the message of the commits, a reviewer, and a label ]
+
 		$ hub pull-request -F - --edit < path/to/message-template.md
 		[ further edit the title and message received on standard input ]
 
@@ -131,6 +257,7 @@ func pullRequest(cmd *Command, args *Args) {
 		utils.Check(github.FormatError("creating pull request", err))
 	}
 	client := github.NewClientWithHost(host)
+	client.Idempotent = args.Flag.Bool("--idempotent")
 
 	trackedBranch, headProject, err := localRepo.RemoteBranchAndProject(host.User, false)
 	utils.Check(err)
@@ -139,14 +266,42 @@ func pullRequest(cmd *Command, args *Args) {
 		base, head string
 	)
 
-	if flagPullRequestBase := args.Flag.Value("--base"); flagPullRequestBase != "" {
+	flagPullRequestBase := args.Flag.Value("--base")
+	flagPullRequestHead := args.Flag.Value("--head")
+	flagPullRequestHeadRepo := args.Flag.Value("--head-repo")
+
+	if flagPullRequestBaseRemote := args.Flag.Value("--base-remote"); flagPullRequestBaseRemote != "" {
+		if strings.Contains(flagPullRequestBase, ":") {
+			utils.Check(fmt.Errorf("Error: --base-remote can't be combined with an owner in --base"))
+		}
+		baseProject, err = pullRequestRemoteProject(localRepo, flagPullRequestBaseRemote)
+		utils.Check(err)
+	}
+
+	if flagPullRequestHeadRemote := args.Flag.Value("--head-remote"); flagPullRequestHeadRemote != "" {
+		if strings.Contains(flagPullRequestHead, ":") {
+			utils.Check(fmt.Errorf("Error: --head-remote can't be combined with an owner in --head"))
+		}
+		if flagPullRequestHeadRepo != "" {
+			utils.Check(fmt.Errorf("Error: --head-remote can't be combined with --head-repo"))
+		}
+		headProject, err = pullRequestRemoteProject(localRepo, flagPullRequestHeadRemote)
+		utils.Check(err)
+	}
+
+	if flagPullRequestBase != "" {
 		baseProject, base = parsePullRequestProject(baseProject, flagPullRequestBase)
 	}
 
-	if flagPullRequestHead := args.Flag.Value("--head"); flagPullRequestHead != "" {
+	if flagPullRequestHead != "" {
 		headProject, head = parsePullRequestProject(headProject, flagPullRequestHead)
 	}
 
+	if flagPullRequestHeadRepo != "" {
+		headProject, err = parsePullRequestHeadRepo(baseProject, flagPullRequestHeadRepo)
+		utils.Check(err)
+	}
+
 	baseRemote, _ := localRepo.RemoteForProject(baseProject)
 	if base == "" && baseRemote != nil {
 		base = localRepo.DefaultBranch(baseRemote).ShortName()
@@ -174,7 +329,17 @@ func pullRequest(cmd *Command, args *Args) {
 		}
 	}
 
-	if headRepo, err := client.Repository(headProject); err == nil {
+	if flagPullRequestHeadRepo != "" {
+		headRepo, err := client.CachedRepository(headProject)
+		utils.Check(err)
+		baseRepo, err := client.CachedRepository(baseProject)
+		utils.Check(err)
+		if repositoryNetwork(headRepo) != repositoryNetwork(baseRepo) {
+			utils.Check(fmt.Errorf("Error: %s is not in the same network as %s", headRepo.FullName, baseRepo.FullName))
+		}
+		headProject.Owner = headRepo.Owner.Login
+		headProject.Name = headRepo.Name
+	} else if headRepo, err := client.CachedRepository(headProject); err == nil {
 		headProject.Owner = headRepo.Owner.Login
 		headProject.Name = headRepo.Name
 	}
@@ -182,6 +347,27 @@ func pullRequest(cmd *Command, args *Args) {
 	fullBase := fmt.Sprintf("%s:%s", baseProject.Owner, base)
 	fullHead := fmt.Sprintf("%s:%s", headProject.Owner, head)
 
+	flagPullRequestLabels := commaSeparated(args.Flag.AllValues("--labels"))
+	strictLabels := args.Flag.Bool("--strict-labels")
+	if len(flagPullRequestLabels) > 0 && (strictLabels || args.Flag.Bool("--check-labels")) {
+		flagPullRequestLabels, err = checkLabels(client, baseProject, flagPullRequestLabels, strictLabels)
+		utils.Check(err)
+	}
+
+	flagPullRequestCopyLabelsFrom := args.Flag.Value("--copy-labels-from")
+	flagPullRequestCopyMilestoneFrom := args.Flag.Value("--copy-milestone-from")
+	for _, ref := range []string{flagPullRequestCopyLabelsFrom, flagPullRequestCopyMilestoneFrom} {
+		if ref != "" && !issueReferenceRe.MatchString(ref) {
+			utils.Check(fmt.Errorf("Error: invalid issue reference `%s'; expected a number or <OWNER>/<REPO>#<NUMBER>", ref))
+		}
+	}
+
+	flagPullRequestNoMaintainerEdits := args.Flag.Bool("--no-maintainer-edits")
+	if flagPullRequestNoMaintainerEdits && baseProject.SameAs(headProject) {
+		ui.Errorln("Warning: `--no-maintainer-edits' has no effect when the head and base branches are in the same repository")
+		flagPullRequestNoMaintainerEdits = false
+	}
+
 	force := args.Flag.Bool("--force")
 	if !force && trackedBranch != nil {
 		remoteCommits, _ := git.RefList(trackedBranch.LongName(), "")
@@ -193,8 +379,10 @@ func pullRequest(cmd *Command, args *Args) {
 	}
 
 	messageBuilder := &github.MessageBuilder{
-		Filename: "PULLREQ_EDITMSG",
-		Title:    "pull request",
+		Filename:  "PULLREQ_EDITMSG",
+		Title:     "pull request",
+		KeepDraft: true,
+		EditLast:  args.Flag.Bool("--edit-last"),
 	}
 
 	baseTracking := base
@@ -228,6 +416,16 @@ of text is the title and the rest is the description.`, fullBase, fullHead))
 		flagPullRequestIssue = parsePullRequestIssueNumber(args.GetParam(0))
 	}
 
+	flagPullRequestWeb := args.Flag.Bool("--web")
+	if flagPullRequestWeb && flagPullRequestIssue != "" {
+		utils.Check(fmt.Errorf("Error: --web can't be combined with --issue"))
+	}
+
+	flagPullRequestFill := args.Flag.Bool("--fill")
+	if flagPullRequestFill && (len(flagPullRequestMessage) > 0 || args.Flag.HasReceived("--file")) {
+		utils.Check(fmt.Errorf("Error: --fill can't be combined with --message or --file"))
+	}
+
 	if len(flagPullRequestMessage) > 0 {
 		messageBuilder.Message = strings.Join(flagPullRequestMessage, "\n\n")
 		messageBuilder.Edit = flagPullRequestEdit
@@ -243,6 +441,21 @@ of text is the title and the rest is the description.`, fullBase, fullHead))
 		message, err := git.Show(commits[len(commits)-1])
 		utils.Check(err)
 		messageBuilder.Message = message
+	} else if flagPullRequestFill {
+		headForMessage := headTracking
+		if flagPullRequestPush {
+			headForMessage = head
+		}
+		commits, _ := git.RefList(baseTracking, headForMessage)
+		if len(commits) == 0 {
+			utils.Check(fmt.Errorf("Aborted: no commits detected between %s and %s", baseTracking, headForMessage))
+		}
+		messageBuilder.Message, err = fillMessage(commits, head)
+		utils.Check(err)
+		messageBuilder.Edit = flagPullRequestEdit
+	} else if flagPullRequestWeb {
+		// the title and description can be written in the browser
+		messageBuilder.Edit = flagPullRequestEdit
 	} else if flagPullRequestIssue == "" {
 		messageBuilder.Edit = true
 
@@ -255,7 +468,8 @@ of text is the title and the rest is the description.`, fullBase, fullHead))
 		commitLogs := ""
 
 		commits, _ := git.RefList(baseTracking, headForMessage)
-		if len(commits) == 1 {
+		noDefaultMessage := args.Flag.Bool("--no-default-message")
+		if len(commits) == 1 && !noDefaultMessage {
 			message, err = git.Show(commits[0])
 			utils.Check(err)
 
@@ -271,7 +485,7 @@ of text is the title and the rest is the description.`, fullBase, fullHead))
 		}
 
 		workdir, _ := git.WorkdirName()
-		if workdir != "" {
+		if workdir != "" && !noDefaultMessage {
 			template, _ := github.ReadTemplate(github.PullRequestTemplate, workdir)
 			if template != "" {
 				message = message + "\n\n\n" + template
@@ -281,13 +495,33 @@ of text is the title and the rest is the description.`, fullBase, fullHead))
 		messageBuilder.Message = message
 	}
 
+	if args.Flag.Bool("--body-from-commits") {
+		limit, err := bodyFromCommitsLimit(args)
+		utils.Check(err)
+		commits, _ := git.RefList(baseTracking, head)
+		body, err := commitsBody(commits, limit)
+		utils.Check(err)
+		// a single commit is already used as the whole message
+		if body != "" && !strings.HasPrefix(strings.TrimSpace(messageBuilder.Message), body) {
+			messageBuilder.Message = appendParagraph(messageBuilder.Message, body)
+		}
+	}
+
 	title, body, err := messageBuilder.Extract()
 	utils.Check(err)
 
-	if title == "" && flagPullRequestIssue == "" {
+	if title == "" && flagPullRequestIssue == "" && !flagPullRequestWeb {
 		utils.Check(fmt.Errorf("Aborting due to empty pull request title"))
 	}
 
+	if flagPullRequestReferences := args.Flag.AllValues("--references"); len(flagPullRequestReferences) > 0 {
+		references := []string{}
+		for _, ref := range flagPullRequestReferences {
+			references = append(references, describePullRequestReference(client, baseProject, ref, args.Noop))
+		}
+		body = replaceReferencesSection(body, references)
+	}
+
 	if flagPullRequestPush {
 		if args.Noop {
 			args.Before(fmt.Sprintf("Would push to %s/%s", remote.Name, head), "")
@@ -298,7 +532,8 @@ of text is the title and the rest is the description.`, fullBase, fullHead))
 	}
 
 	milestoneNumber := 0
-	if flagPullRequestMilestone := args.Flag.Value("--milestone"); flagPullRequestMilestone != "" {
+	flagPullRequestMilestone := args.Flag.Value("--milestone")
+	if flagPullRequestMilestone != "" && !flagPullRequestWeb {
 		// BC: Don't try to resolve milestone name if it's an integer
 		milestoneNumber, err = strconv.Atoi(flagPullRequestMilestone)
 		if err != nil {
@@ -309,8 +544,52 @@ of text is the title and the rest is the description.`, fullBase, fullHead))
 		}
 	}
 
+	var codeOwnerReviewers []string
+	if args.Flag.Bool("--reviewers-from-codeowners") {
+		exclude := append(commaSeparated(args.Flag.AllValues("--reviewer")), client.Host.User)
+		headForCodeOwners := headTracking
+		if flagPullRequestPush {
+			headForCodeOwners = head
+		}
+		codeOwnerReviewers, err = codeOwnersReviewers(baseTracking, headForCodeOwners, exclude)
+		utils.Check(err)
+	}
+
+	flagPullRequestAssignees := commaSeparated(args.Flag.AllValues("--assign"))
+	flagPullRequestReviewers := append(commaSeparated(args.Flag.AllValues("--reviewer")), codeOwnerReviewers...)
+
 	var pullRequestURL string
-	if args.Noop {
+	partialFailure := false
+	if flagPullRequestWeb {
+		if flagPullRequestCopyLabelsFrom != "" {
+			labels, err := copyLabelsFrom(client, baseProject, flagPullRequestCopyLabelsFrom)
+			utils.Check(err)
+			flagPullRequestLabels = mergeLabels(flagPullRequestLabels, labels)
+		}
+		if flagPullRequestCopyMilestoneFrom != "" && flagPullRequestMilestone == "" {
+			issue, _, err := fetchIssueReference(client, baseProject, flagPullRequestCopyMilestoneFrom)
+			utils.Check(err)
+			if issue.Milestone != nil {
+				flagPullRequestMilestone = issue.Milestone.Title
+			}
+		}
+
+		prefill := url.Values{}
+		for name, value := range map[string]string{
+			"title":     title,
+			"body":      body,
+			"labels":    strings.Join(flagPullRequestLabels, ","),
+			"assignees": strings.Join(flagPullRequestAssignees, ","),
+			"reviewers": strings.Join(flagPullRequestReviewers, ","),
+			"milestone": flagPullRequestMilestone,
+		} {
+			if value != "" {
+				prefill.Set(name, value)
+			}
+		}
+		pullRequestURL = pullRequestFormURL(baseProject, headProject, base, head, prefill)
+		defer messageBuilder.Cleanup()
+	} else if args.Noop {
 		args.Before(fmt.Sprintf("Would request a pull request to %s from %s", fullBase, fullHead), "")
 		pullRequestURL = "PULL_REQUEST_URL"
 	} else {
@@ -329,6 +608,10 @@ of text is the title and the rest is the description.`, fullBase, fullHead))
 			params["issue"] = issueNum
 		}
 
+		if flagPullRequestNoMaintainerEdits {
+			params["maintainer_can_modify"] = false
+		}
+
 		startedAt := time.Now()
 		numRetries := 0
 		retryDelay := 2
@@ -342,10 +625,28 @@ of text is the title and the rest is the description.`, fullBase, fullHead))
 			}
 		}
 
+		flagPullRequestAllowEmpty := args.Flag.Bool("--allow-empty")
+		pushedEmptyCommit := false
+
 		var pr *github.PullRequest
 		for {
 			pr, err = client.CreatePullRequest(baseProject, params)
-			if err != nil && strings.Contains(err.Error(), `Invalid value for "head"`) {
+			if err != nil && strings.Contains(err.Error(), "No commits between") {
+				if !flagPullRequestAllowEmpty || pushedEmptyCommit {
+					err = fmt.Errorf("Error creating pull request: there are no commits between %s and %s", fullBase, fullHead)
+					if !pushedEmptyCommit {
+						err = fmt.Errorf("%s\n(use `--allow-empty` to push an empty commit to %s and try again)", err, head)
+					}
+					break
+				}
+				if err = pushEmptyCommit(currentBranch, remote, head, title); err != nil {
+					break
+				}
+				pushedEmptyCommit = true
+				if retryAllowance == 0 {
+					retryAllowance = 9
+				}
+			} else if err != nil && strings.Contains(err.Error(), `Invalid value for "head"`) {
 				if retryAllowance > 0 {
 					retryAllowance -= retryDelay
 					time.Sleep(time.Duration(retryDelay) * time.Second)
@@ -370,13 +671,29 @@ of text is the title and the rest is the description.`, fullBase, fullHead))
 		utils.Check(err)
 
 		pullRequestURL = pr.HtmlUrl
+		recordLastURL(args, pullRequestURL)
+
+		if flagPullRequestCopyLabelsFrom != "" {
+			labels, err := copyLabelsFrom(client, baseProject, flagPullRequestCopyLabelsFrom)
+			if handlePostCreationError(args, err) {
+				partialFailure = true
+			} else {
+				flagPullRequestLabels = mergeLabels(flagPullRequestLabels, labels)
+			}
+		}
+		if flagPullRequestCopyMilestoneFrom != "" && milestoneNumber == 0 {
+			number, err := copyMilestoneFrom(client, baseProject, flagPullRequestCopyMilestoneFrom)
+			if handlePostCreationError(args, err) {
+				partialFailure = true
+			} else {
+				milestoneNumber = number
+			}
+		}
 
 		params = map[string]interface{}{}
-		flagPullRequestLabels := commaSeparated(args.Flag.AllValues("--labels"))
 		if len(flagPullRequestLabels) > 0 {
 			params["labels"] = flagPullRequestLabels
 		}
-		flagPullRequestAssignees := commaSeparated(args.Flag.AllValues("--assign"))
 		if len(flagPullRequestAssignees) > 0 {
 			params["assignees"] = flagPullRequestAssignees
 		}
@@ -386,10 +703,9 @@ of text is the title and the rest is the description.`, fullBase, fullHead))
 
 		if len(params) > 0 {
 			err = client.UpdateIssue(baseProject, pr.Number, params)
-			utils.Check(err)
+			partialFailure = handlePostCreationError(args, err) || partialFailure
 		}
 
-		flagPullRequestReviewers := commaSeparated(args.Flag.AllValues("--reviewer"))
 		if len(flagPullRequestReviewers) > 0 {
 			userReviewers := []string{}
 			teamReviewers := []string{}
@@ -408,13 +724,93 @@ of text is the title and the rest is the description.`, fullBase, fullHead))
 					"reviewers":      userReviewers,
 					"team_reviewers": teamReviewers,
 				})
-				utils.Check(err)
+				partialFailure = handlePostCreationError(args, err) || partialFailure
 			}
 		}
 	}
 
 	args.NoForward()
-	printBrowseOrCopy(args, pullRequestURL, args.Flag.Bool("--browse"), args.Flag.Bool("--copy"))
+	printBrowseOrCopy(args, pullRequestURL, args.Flag.Bool("--browse") || flagPullRequestWeb, args.Flag.Bool("--copy"))
+
+	if partialFailure {
+		args.AfterFn(func() error {
+			ui.Errorln("Warning: the pull request was created, but some of its properties could not be applied")
+			os.Exit(utils.ExitPartial)
+			return nil
+		})
+	}
+}
+
+// pullRequestFormURL returns the address of the page for opening a pull
+// request from head to base on GitHub, with the form fields set from prefill
+func pullRequestFormURL(baseProject, headProject *github.Project, base, head string, prefill url.Values) string {
+	if !baseProject.SameAs(headProject) {
+		head = fmt.Sprintf("%s:%s", headProject.Owner, head)
+	}
+	prefill.Set("expand", "1")
+	return baseProject.WebURL("", "", fmt.Sprintf("compare/%s...%s", base, head)) + "?" + prefill.Encode()
+}
+
+// fillMessage makes a pull request message out of commits, given newest
+// first, for '--fill'. A single commit message is used as is. Otherwise, the
+// title is derived from the branch name and the description lists the commit
+// subjects in the order they were made.
+func fillMessage(commits []string, branch string) (string, error) {
+	if len(commits) == 1 {
+		message, err := git.Show(commits[0])
+		return signedOffByRe.ReplaceAllString(message, ""), err
+	}
+
+	subjects := []string{}
+	for i := len(commits) - 1; i >= 0; i-- {
+		message, err := git.Show(commits[i])
+		if err != nil {
+			return "", err
+		}
+		subjects = append(subjects, "- "+strings.SplitN(message, "\n", 2)[0])
+	}
+	return humanizeBranchName(branch) + "\n\n" + strings.Join(subjects, "\n"), nil
+}
+
+// humanizeBranchName turns a branch name such as "fix-login_form" into a
+// title such as "Fix login form"
+func humanizeBranchName(branch string) string {
+	title := strings.NewReplacer("-", " ", "_", " ").Replace(branch)
+	if title == "" {
+		return title
+	}
+	return strings.ToUpper(title[:1]) + title[1:]
+}
+
+// pushEmptyCommit records an empty commit on the current branch and pushes it
+// to the head branch of the pull request so that GitHub accepts the pull request
+func pushEmptyCommit(currentBranch *github.Branch, remote *github.Remote, head, title string) error {
+	if remote == nil || currentBranch.ShortName() != head {
+		return fmt.Errorf("Error: can't create an empty commit on `%s' because it's not the current branch", head)
+	}
+
+	message := title
+	if message == "" {
+		message = "Empty commit to start a pull request"
+	}
+	if err := git.Spawn("commit", "--allow-empty", "--quiet", "-m", message); err != nil {
+		return err
+	}
+	return git.Spawn("push", "--quiet", remote.Name, fmt.Sprintf("HEAD:%s", head))
+}
+
+// handlePostCreationError reports a failure to update a newly created pull
+// request. It aborts in strict mode and otherwise returns whether a warning
+// was printed.
+func handlePostCreationError(args *Args, err error) bool {
+	if err == nil {
+		return false
+	}
+	if args.Flag.Bool("--strict") {
+		utils.Check(err)
+	}
+	ui.Errorln(err)
+	return true
 }
 
 func parsePullRequestProject(context *github.Project, s string) (p *github.Project, ref string) {
@@ -434,6 +830,162 @@ func parsePullRequestProject(context *github.Project, s string) (p *github.Proje
 	return
 }
 
+// pullRequestRemoteProject returns the GitHub repository that the git remote
+// named name points to
+func pullRequestRemoteProject(localRepo *github.GitHubRepo, name string) (*github.Project, error) {
+	remote, err := localRepo.RemoteByName(name)
+	if err != nil {
+		return nil, fmt.Errorf("Error: no git remote named `%s'", name)
+	}
+	project, err := remote.Project()
+	if err != nil {
+		return nil, fmt.Errorf("Error: git remote `%s' doesn't point to a GitHub repository", name)
+	}
+	return project, nil
+}
+
+func parsePullRequestHeadRepo(context *github.Project, s string) (*github.Project, error) {
+	re := regexp.MustCompile(NameWithOwnerRe)
+	if !strings.Contains(s, "/") || !re.MatchString(s) {
+		return nil, fmt.Errorf("Error: invalid head repository `%s'; expected <OWNER>/<REPO>", s)
+	}
+	split := strings.SplitN(s, "/", 2)
+	return github.NewProject(split[0], split[1], context.Host), nil
+}
+
+// repositoryNetwork returns the full name of the repository at the root of
+// the fork network that repo belongs to.
+func repositoryNetwork(repo *github.Repository) string {
+	if repo.Source != nil {
+		return strings.ToLower(repo.Source.FullName)
+	}
+	return strings.ToLower(repo.FullName)
+}
+
+const (
+	referencesSectionStart = "<!-- hub:references -->"
+	referencesSectionEnd   = "<!-- /hub:references -->"
+)
+
+var pullRequestReferenceRe = regexp.MustCompile(`^#?(\d+)$`)
+
+// describePullRequestReference turns a pull request number or commit SHA into
+// a line for the references section of a pull request description. A bare
+// number is only taken as a pull request if it doesn't name a commit, since
+// abbreviated SHAs can be all digits.
+func describePullRequestReference(client *github.Client, project *github.Project, ref string, noop bool) string {
+	if !strings.HasPrefix(ref, "#") {
+		if sha, err := git.Ref(ref + "^{commit}"); err == nil {
+			return fmt.Sprintf("- Cherry-picked from commit %s", sha)
+		}
+	}
+
+	if m := pullRequestReferenceRe.FindStringSubmatch(ref); m != nil {
+		if !noop {
+			if pr, err := client.PullRequest(project, m[1]); err == nil {
+				return fmt.Sprintf("- Cherry-picked from #%s: %s", m[1], pr.Title)
+			}
+		}
+		return fmt.Sprintf("- Cherry-picked from #%s", m[1])
+	}
+
+	return fmt.Sprintf("- Cherry-picked from commit %s", ref)
+}
+
+// replaceReferencesSection appends the references section to body, replacing
+// any such section that body already contains.
+func replaceReferencesSection(body string, references []string) string {
+	if start := strings.Index(body, referencesSectionStart); start >= 0 {
+		if end := strings.Index(body[start:], referencesSectionEnd); end >= 0 {
+			body = body[:start] + body[start+end+len(referencesSectionEnd):]
+		}
+	}
+	body = strings.TrimSpace(body)
+
+	section := fmt.Sprintf("%s\n%s\n%s", referencesSectionStart, strings.Join(references, "\n"), referencesSectionEnd)
+	if body == "" {
+		return section
+	}
+	return body + "\n\n" + section
+}
+
+var issueReferenceRe = regexp.MustCompile(`^(?:([^/\s#]+)/([^/\s#]+)#|#?)(\d+)$`)
+
+// fetchIssueReference fetches the issue or pull request that ref points to,
+// which is either a number in project or an "OWNER/REPO#NUMBER" reference. It
+// also returns the project that the issue belongs to.
+func fetchIssueReference(client *github.Client, project *github.Project, ref string) (*github.Issue, *github.Project, error) {
+	project, number := parseIssueReference(project, ref)
+	issue, err := client.FetchIssue(project, number)
+	return issue, project, err
+}
+
+// parseIssueReference splits a reference that matches issueReferenceRe into
+// the project and the number of the issue
+func parseIssueReference(project *github.Project, ref string) (*github.Project, string) {
+	m := issueReferenceRe.FindStringSubmatch(ref)
+	if m[1] != "" {
+		project = github.NewProject(m[1], m[2], project.Host)
+	}
+	return project, m[3]
+}
+
+// copyLabelsFrom returns the labels of the issue that ref points to. Labels of
+// an issue in another repository are checked against those of project.
+func copyLabelsFrom(client *github.Client, project *github.Project, ref string) ([]string, error) {
+	issue, issueProject, err := fetchIssueReference(client, project, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	labels := []string{}
+	for _, label := range issue.Labels {
+		labels = append(labels, label.Name)
+	}
+	if len(labels) == 0 || issueProject.SameAs(project) {
+		return labels, nil
+	}
+	return checkLabels(client, project, labels, false)
+}
+
+// copyMilestoneFrom returns the number of the milestone in project that
+// corresponds to the milestone of the issue that ref points to, or 0 if there
+// is none
+func copyMilestoneFrom(client *github.Client, project *github.Project, ref string) (int, error) {
+	issue, issueProject, err := fetchIssueReference(client, project, ref)
+	if err != nil || issue.Milestone == nil {
+		return 0, err
+	}
+	if issueProject.SameAs(project) {
+		return issue.Milestone.Number, nil
+	}
+
+	milestones, err := client.FetchMilestones(project)
+	if err != nil {
+		return 0, err
+	}
+	number, err := findMilestoneNumber(milestones, issue.Milestone.Title)
+	if err != nil {
+		ui.Errorf("Warning: milestone `%s' doesn't exist in %s\n", issue.Milestone.Title, project)
+	}
+	return number, nil
+}
+
+// mergeLabels appends the labels that aren't present yet, ignoring case
+func mergeLabels(labels, other []string) []string {
+	seen := map[string]bool{}
+	for _, label := range labels {
+		seen[strings.ToLower(label)] = true
+	}
+	for _, label := range other {
+		if !seen[strings.ToLower(label)] {
+			labels = append(labels, label)
+			seen[strings.ToLower(label)] = true
+		}
+	}
+	return labels
+}
+
 func parsePullRequestIssueNumber(url string) string {
 	u, e := github.ParseURL(url)
 	if e != nil {
@@ -459,6 +1011,54 @@ func findMilestoneNumber(milestones []github.Milestone, name string) (int, error
 	return 0, fmt.Errorf("error: no milestone found with name '%s'", name)
 }
 
+// codeOwnersReviewers looks up the code owners of the files changed on head
+// since it diverged from base, leaving out email addresses and the logins in
+// exclude. If the changed files can't be listed, nobody is requested.
+func codeOwnersReviewers(base, head string, exclude []string) ([]string, error) {
+	workdir, err := git.WorkdirName()
+	if err != nil {
+		return nil, err
+	}
+	codeOwners, err := github.ReadCodeOwners(workdir)
+	if err != nil {
+		return nil, err
+	} else if codeOwners == nil {
+		ui.Errorln("Warning: no CODEOWNERS file found to request reviews from")
+		return nil, nil
+	}
+
+	files, err := git.ChangedFiles(base, head)
+	if err != nil {
+		ui.Errorf("Warning: couldn't list the files changed between %s and %s, so no code owners were requested\n", base, head)
+		return nil, nil
+	}
+
+	seen := map[string]bool{}
+	for _, login := range exclude {
+		seen[strings.ToLower(login)] = true
+	}
+
+	reviewers := []string{}
+	for _, file := range files {
+		owners, matched := codeOwners.Owners(file)
+		if !matched {
+			ui.Errorf("Warning: no code owners found for `%s'\n", file)
+			continue
+		}
+		for _, owner := range owners {
+			if !strings.HasPrefix(owner, "@") {
+				continue
+			}
+			login := strings.TrimPrefix(owner, "@")
+			if !seen[strings.ToLower(login)] {
+				seen[strings.ToLower(login)] = true
+				reviewers = append(reviewers, login)
+			}
+		}
+	}
+	return reviewers, nil
+}
+
 func commaSeparated(l []string) []string {
 	res := []string{}
 	for _, i := range l {
@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"github.com/bmizerany/assert"
+	"github.com/github/hub/fixtures"
+	"github.com/github/hub/git"
 	"github.com/github/hub/github"
 )
 
@@ -31,3 +33,50 @@ func TestPullRequest_ParsePullRequestProject(t *testing.T) {
 	assert.Equal(t, "mojombo", p.Owner)
 	assert.Equal(t, "jekyll", p.Name)
 }
+
+func TestPullRequest_ParsePullRequestHeadRepo(t *testing.T) {
+	c := &github.Project{Host: "github.com", Owner: "jingweno", Name: "gh"}
+
+	p, err := parsePullRequestHeadRepo(c, "mojombo/gh-fork")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "github.com", p.Host)
+	assert.Equal(t, "mojombo", p.Owner)
+	assert.Equal(t, "gh-fork", p.Name)
+
+	_, err = parsePullRequestHeadRepo(c, "mojombo")
+	assert.NotEqual(t, nil, err)
+}
+
+func TestPullRequest_ReplaceReferencesSection(t *testing.T) {
+	body := replaceReferencesSection("", []string{"- Cherry-picked from #12"})
+	assert.Equal(t, "<!-- hub:references -->\n- Cherry-picked from #12\n<!-- /hub:references -->", body)
+
+	body = replaceReferencesSection("Backport\n\n"+body, []string{"- Cherry-picked from #13"})
+	assert.Equal(t, "Backport\n\n<!-- hub:references -->\n- Cherry-picked from #13\n<!-- /hub:references -->", body)
+}
+
+func TestPullRequest_MergeLabels(t *testing.T) {
+	assert.Equal(t, []string{"bug", "docs", "backport"}, mergeLabels([]string{"bug", "docs"}, []string{"Bug", "backport", "BACKPORT"}))
+	assert.Equal(t, []string{"feature"}, mergeLabels(nil, []string{"feature"}))
+}
+
+func TestPullRequest_HumanizeBranchName(t *testing.T) {
+	assert.Equal(t, "Fix login form", humanizeBranchName("fix-login_form"))
+	assert.Equal(t, "Mislav/docs update", humanizeBranchName("mislav/docs-update"))
+	assert.Equal(t, "", humanizeBranchName(""))
+}
+
+func TestPullRequest_DescribePullRequestReference(t *testing.T) {
+	repo := fixtures.SetupTestRepo()
+	defer repo.TearDown()
+
+	project := &github.Project{Host: "github.com", Owner: "mislav", Name: "coral"}
+	assert.Equal(t, "- Cherry-picked from #12", describePullRequestReference(nil, project, "12", true))
+	assert.Equal(t, "- Cherry-picked from #12", describePullRequestReference(nil, project, "#12", true))
+
+	// a ref that is all digits, like some abbreviated SHAs
+	sha, _ := git.Ref("HEAD")
+	assert.T(t, git.Quiet("tag", "1234", sha))
+	assert.Equal(t, "- Cherry-picked from commit "+sha, describePullRequestReference(nil, project, "1234", true))
+	assert.Equal(t, "- Cherry-picked from #1234", describePullRequestReference(nil, project, "#1234", true))
+}
@@ -1,13 +1,16 @@
 package commands
 
 import (
+	"bufio"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/github/hub/git"
 	"github.com/github/hub/github"
 	"github.com/github/hub/ui"
 	"github.com/github/hub/utils"
@@ -19,10 +22,10 @@ var (
 		Usage: `
 release [--include-drafts] [--exclude-prereleases] [-L <LIMIT>] [-f <FORMAT>]
 release show [-f <FORMAT>] <TAG>
-release create [-dpoc] [-a <FILE>] [-m <MESSAGE>|-F <FILE>] [-t <TARGET>] <TAG>
+release create [-dpoc] [--dump-url] [-a <FILE>] [-m <MESSAGE>|-F <FILE>] [--name <NAME>] [--generate-notes] [-t <TARGET>] <TAG>
 release edit [<options>] <TAG>
 release download <TAG>
-release delete <TAG>
+release delete [-y] [--delete-tag] <TAG>
 `,
 		Long: `Manage GitHub Releases for the current repository.
 
@@ -55,7 +58,14 @@ With '--exclude-prereleases', exclude non-stable releases from the listing.
 
 	* _delete_:
 		Delete the release and associated assets for the specified <TAG>. Note that
-		this does **not** remove the git tag <TAG>.
+		this does **not** remove the git tag <TAG> unless '--delete-tag' is given.
+
+		When run interactively, ask for confirmation first. Otherwise, the release
+		is only deleted with '--yes'.
+
+The _show_, _edit_, _download_, and _delete_ commands accept "latest" as <TAG>
+to operate on the most recent published release, which excludes drafts and
+pre-releases.
 
 ## Options:
 	-L, --limit
@@ -86,9 +96,27 @@ With '--exclude-prereleases', exclude non-stable releases from the listing.
 	-e, --edit
 		Further edit the contents of <FILE> in a text editor before submitting.
 
+	--name <NAME>
+		Use <NAME> as the release title instead of the first block of text of the
+		message. With '--name' alone, no text editor is opened and the release
+		has no description.
+
+	--generate-notes
+		Have GitHub generate release notes from the pull requests merged since the
+		previous release. The generated notes are appended to the description
+		given with '--message' or '--file', if any. Without either of those, no
+		text editor is opened and, unless '--name' is given, GitHub names the
+		release as well.
+
+		This requires GitHub Enterprise Server 3.4 or later on Enterprise hosts.
+
 	-o, --browse
 		Open the new release in a web browser.
 
+	--dump-url
+		With '--browse', print the URL of the new release instead of opening it in
+		a web browser.
+
 	-c, --copy
 		Put the URL of the new release to clipboard instead of printing it.
 
@@ -96,6 +124,14 @@ With '--exclude-prereleases', exclude non-stable releases from the listing.
 		A commit SHA or branch name to attach the release to, only used if <TAG>
 		does not already exist (default: main branch).
 
+		If <TAG> already exists locally or on GitHub but points to a different
+		commit than <TARGET>, abort unless '--overwrite-tag' is given. <TARGET> is
+		looked up on GitHub rather than in the local repository.
+
+	--overwrite-tag
+		When creating a release with '--commitish', move an existing <TAG> that
+		points to a different commit so that it points to <TARGET>.
+
 	-f, --format <FORMAT>
 		Pretty print releases using <FORMAT> (default: "%T%n"). See the "PRETTY
 		FORMATS" section of git-log(1) for some additional details on how
@@ -119,8 +155,17 @@ With '--exclude-prereleases', exclude non-stable releases from the listing.
 
 		%b: body
 
+		%d: "true" if this is a draft release, "false" otherwise
+
+		%P: "true" if this is a pre-release, "false" otherwise
+
 		%as: the list of assets attached to this release
 
+		%an: comma-separated names of the assets
+
+		%aS: comma-separated names of the assets with their sizes, such as
+		"hub.tgz (4.2 MB)"
+
 		%cD: created date-only (no time of day)
 
 		%cr: created date, relative
@@ -145,6 +190,14 @@ With '--exclude-prereleases', exclude non-stable releases from the listing.
 		Enable colored output even if stdout is not a terminal. <WHEN> can be one
 		of "always" (default for '--color'), "never", or "auto" (default).
 
+	--delete-tag
+		With _delete_, also delete the git tag <TAG> on GitHub and in the local
+		repository.
+
+	-y, --yes
+		With _delete_, skip the confirmation prompt. This is required when hub
+		isn't run interactively.
+
 	<TAG>
 		The git tag name for this release.
 
@@ -179,11 +232,15 @@ hub(1), git-tag(1)
 		-d, --draft
 		-p, --prerelease
 		-o, --browse
+		--dump-url
 		-c, --copy
 		-a, --attach FILE
 		-m, --message MSG
 		-F, --file FILE
 		-t, --commitish C
+		--overwrite-tag
+		--name NAME
+		--generate-notes
 `,
 	}
 
@@ -198,6 +255,7 @@ hub(1), git-tag(1)
 		-m, --message MSG
 		-F, --file FILE
 		-t, --commitish C
+		--name NAME
 `,
 	}
 
@@ -209,6 +267,10 @@ hub(1), git-tag(1)
 	cmdDeleteRelease = &Command{
 		Key: "delete",
 		Run: deleteRelease,
+		KnownFlags: `
+		-y, --yes
+		--delete-tag
+`,
 	}
 )
 
@@ -283,8 +345,12 @@ func formatRelease(release github.Release, format string, colorize bool) string
 	}
 
 	assets := make([]string, len(release.Assets))
+	assetNames := make([]string, len(release.Assets))
+	assetSizes := make([]string, len(release.Assets))
 	for i, asset := range release.Assets {
 		assets[i] = fmt.Sprintf("%s\t%s", asset.DownloadUrl, asset.Label)
+		assetNames[i] = asset.Name
+		assetSizes[i] = fmt.Sprintf("%s (%s)", asset.Name, formatAssetSize(asset.Size))
 	}
 
 	placeholders := map[string]string{
@@ -297,7 +363,11 @@ func formatRelease(release github.Release, format string, colorize bool) string
 		"t":  release.Name,
 		"T":  release.TagName,
 		"b":  release.Body,
+		"d":  strconv.FormatBool(release.Draft),
+		"P":  strconv.FormatBool(release.Prerelease),
 		"as": strings.Join(assets, "\n"),
+		"an": strings.Join(assetNames, ","),
+		"aS": strings.Join(assetSizes, ", "),
 		"cD": createdDate,
 		"cI": createdAtISO8601,
 		"ct": createdAtUnix,
@@ -311,6 +381,24 @@ func formatRelease(release github.Release, format string, colorize bool) string
 	return ui.Expand(format, placeholders, colorize)
 }
 
+// formatAssetSize describes a number of bytes in the largest unit that keeps
+// the number at least 1, with one decimal
+func formatAssetSize(size int64) string {
+	if size < 1000 {
+		return fmt.Sprintf("%d B", size)
+	}
+	value := float64(size)
+	units := []string{"kB", "MB", "GB", "TB"}
+	unit := ""
+	for _, unit = range units {
+		value /= 1000
+		if value < 1000 {
+			break
+		}
+	}
+	return fmt.Sprintf("%.1f %s", value, unit)
+}
+
 func showRelease(cmd *Command, args *Args) {
 	tagName := ""
 	if args.ParamsSize() > 0 {
@@ -333,7 +421,7 @@ func showRelease(cmd *Command, args *Args) {
 	if args.Noop {
 		ui.Printf("Would display information for `%s' release\n", tagName)
 	} else {
-		release, err := gh.FetchRelease(project, tagName)
+		release, err := fetchReleaseByTag(gh, project, tagName)
 		utils.Check(err)
 
 		body := strings.TrimSpace(release.Body)
@@ -378,11 +466,11 @@ func downloadRelease(cmd *Command, args *Args) {
 
 	gh := github.NewClient(project.Host)
 
-	release, err := gh.FetchRelease(project, tagName)
+	release, err := fetchReleaseByTag(gh, project, tagName)
 	utils.Check(err)
 
 	for _, asset := range release.Assets {
-		ui.Printf("Downloading %s ...\n", asset.Name)
+		ui.Infof("Downloading %s ...\n", asset.Name)
 		err := downloadReleaseAsset(asset, gh)
 		utils.Check(err)
 	}
@@ -438,7 +526,9 @@ func createRelease(cmd *Command, args *Args) {
 Write a message for this release. The first block of
 text is the title and the rest is the description.`, tagName, project))
 
+	flagReleaseGenerateNotes := args.Flag.Bool("--generate-notes")
 	flagReleaseMessage := args.Flag.AllValues("--message")
+	flagReleaseName := args.Flag.Value("--name")
 	if len(flagReleaseMessage) > 0 {
 		messageBuilder.Message = strings.Join(flagReleaseMessage, "\n\n")
 		messageBuilder.Edit = args.Flag.Bool("--edit")
@@ -447,13 +537,18 @@ text is the title and the rest is the description.`, tagName, project))
 		utils.Check(err)
 		messageBuilder.Edit = args.Flag.Bool("--edit")
 	} else {
-		messageBuilder.Edit = true
+		messageBuilder.Edit = !(flagReleaseGenerateNotes || flagReleaseName != "") || args.Flag.Bool("--edit")
 	}
 
 	title, body, err := messageBuilder.Extract()
 	utils.Check(err)
 
-	if title == "" {
+	if flagReleaseName != "" {
+		// the whole message is the description when the title is given apart
+		body = strings.TrimSpace(strings.Join([]string{title, body}, "\n\n"))
+		title = flagReleaseName
+	} else if title == "" && !flagReleaseGenerateNotes {
+		// with generated notes, an empty title lets GitHub name the release
 		utils.Check(fmt.Errorf("Aborting release due to empty release title"))
 	}
 
@@ -464,6 +559,11 @@ text is the title and the rest is the description.`, tagName, project))
 		Body:            body,
 		Draft:           args.Flag.Bool("--draft"),
 		Prerelease:      args.Flag.Bool("--prerelease"),
+		GenerateNotes:   flagReleaseGenerateNotes,
+	}
+
+	if params.TargetCommitish != "" {
+		checkReleaseTag(gh, project, tagName, params.TargetCommitish, args)
 	}
 
 	var release *github.Release
@@ -475,6 +575,13 @@ text is the title and the rest is the description.`, tagName, project))
 		release, err = gh.CreateRelease(project, params)
 		utils.Check(err)
 
+		// Enterprise versions that predate generated notes silently ignore the
+		// parameter, leaving the description as it was sent
+		if flagReleaseGenerateNotes && release.Body == body && project.Host != github.GitHubHost {
+			ui.Errorf("Warning: %s did not generate release notes; this requires GitHub Enterprise Server 3.4 or later\n", project.Host)
+		}
+
+		recordLastURL(args, release.HtmlUrl)
 		flagReleaseBrowse := args.Flag.Bool("--browse")
 		flagReleaseCopy := args.Flag.Bool("--copy")
 		printBrowseOrCopy(args, release.HtmlUrl, flagReleaseBrowse, flagReleaseCopy)
@@ -486,6 +593,64 @@ text is the title and the rest is the description.`, tagName, project))
 	uploadAssets(gh, release, flagReleaseAssets, args)
 }
 
+// checkReleaseTag aborts if tagName already exists locally or on GitHub but
+// points to a different commit than target, unless '--overwrite-tag' was
+// given, in which case the tag is moved to target.
+func checkReleaseTag(gh *github.Client, project *github.Project, tagName, target string, args *Args) {
+	localSha, _ := git.Ref(fmt.Sprintf("refs/tags/%s^{commit}", tagName))
+	remoteSha, err := gh.TagCommitSha(project, tagName)
+	utils.Check(err)
+
+	if localSha == "" && remoteSha == "" {
+		return
+	}
+
+	// GitHub creates the release at its own idea of target, which can differ
+	// from a local branch of the same name
+	targetSha, err := gh.CommitSha(project, target)
+	utils.Check(err)
+
+	overwrite := args.Flag.Bool("--overwrite-tag")
+	for _, tag := range []struct {
+		sha, where string
+	}{{remoteSha, "on GitHub"}, {localSha, "locally"}} {
+		if tag.sha == "" || tag.sha == targetSha {
+			continue
+		}
+		if !overwrite {
+			err = fmt.Errorf("Error: tag `%s' already exists %s at %s, not at %s", tagName, tag.where, shortSha(tag.sha), shortSha(targetSha))
+			err = fmt.Errorf("%s\n(use `--overwrite-tag` to move the tag to %s)", err, target)
+			utils.Check(err)
+		}
+	}
+
+	if !overwrite {
+		return
+	}
+
+	if remoteSha != "" && remoteSha != targetSha {
+		if args.Noop {
+			ui.Printf("Would move tag `%s' on GitHub to %s\n", tagName, shortSha(targetSha))
+		} else {
+			utils.Check(gh.UpdateTag(project, tagName, targetSha))
+		}
+	}
+	if localSha != "" && localSha != targetSha {
+		if args.Noop {
+			ui.Printf("Would move local tag `%s' to %s\n", tagName, shortSha(targetSha))
+		} else if !git.Quiet("tag", "-f", tagName, targetSha) {
+			utils.Check(fmt.Errorf("Error: could not move local tag `%s'", tagName))
+		}
+	}
+}
+
+func shortSha(sha string) string {
+	if len(sha) > 7 {
+		return sha[0:7]
+	}
+	return sha
+}
+
 func editRelease(cmd *Command, args *Args) {
 	tagName := ""
 	if args.ParamsSize() > 0 {
@@ -504,8 +669,9 @@ func editRelease(cmd *Command, args *Args) {
 
 	gh := github.NewClient(project.Host)
 
-	release, err := gh.FetchRelease(project, tagName)
+	release, err := fetchReleaseByTag(gh, project, tagName)
 	utils.Check(err)
+	tagName = release.TagName
 
 	params := map[string]interface{}{}
 	if args.Flag.HasReceived("--commitish") {
@@ -529,6 +695,7 @@ Write a message for this release. The first block of
 text is the title and the rest is the description.`, tagName, project))
 
 	flagReleaseMessage := args.Flag.AllValues("--message")
+	flagReleaseName := args.Flag.Value("--name")
 	if len(flagReleaseMessage) > 0 {
 		messageBuilder.Message = strings.Join(flagReleaseMessage, "\n\n")
 		messageBuilder.Edit = args.Flag.Bool("--edit")
@@ -536,7 +703,7 @@ text is the title and the rest is the description.`, tagName, project))
 		messageBuilder.Message, err = msgFromFile(args.Flag.Value("--file"))
 		utils.Check(err)
 		messageBuilder.Edit = args.Flag.Bool("--edit")
-	} else {
+	} else if flagReleaseName == "" {
 		messageBuilder.Edit = true
 		messageBuilder.Message = fmt.Sprintf("%s\n\n%s", release.Name, release.Body)
 	}
@@ -544,7 +711,10 @@ text is the title and the rest is the description.`, tagName, project))
 	title, body, err := messageBuilder.Extract()
 	utils.Check(err)
 
-	if title == "" && len(flagReleaseMessage) == 0 {
+	if flagReleaseName != "" {
+		body = strings.TrimSpace(strings.Join([]string{title, body}, "\n\n"))
+		title = flagReleaseName
+	} else if title == "" && len(flagReleaseMessage) == 0 {
 		utils.Check(fmt.Errorf("Aborting editing due to empty release title"))
 	}
 
@@ -589,20 +759,81 @@ func deleteRelease(cmd *Command, args *Args) {
 
 	gh := github.NewClient(project.Host)
 
-	release, err := gh.FetchRelease(project, tagName)
+	release, err := fetchReleaseByTag(gh, project, tagName)
 	utils.Check(err)
+	tagName = release.TagName
+
+	deleteTag := args.Flag.Bool("--delete-tag")
+	if !args.Noop && !args.Flag.Bool("--yes") {
+		utils.Check(confirmReleaseDeletion(project, tagName, deleteTag))
+	}
 
 	if args.Noop {
 		message := fmt.Sprintf("Deleting release related to %s...", tagName)
 		ui.Println(message)
+		if deleteTag {
+			ui.Printf("Would delete tag `%s' from %s and the local repository\n", tagName, project)
+		}
 	} else {
 		err = gh.DeleteRelease(release)
 		utils.Check(err)
+		ui.Printf("Deleted release `%s'\n", tagName)
+
+		if deleteTag {
+			err = gh.DeleteTag(project, tagName)
+			utils.Check(err)
+			ui.Printf("Deleted tag `%s' from %s\n", tagName, project)
+
+			if _, err := git.Ref(fmt.Sprintf("refs/tags/%s", tagName)); err == nil {
+				if !git.Quiet("tag", "--delete", tagName) {
+					utils.Check(fmt.Errorf("Error: could not delete local tag `%s'", tagName))
+				}
+				ui.Printf("Deleted local tag `%s'\n", tagName)
+			}
+		}
 	}
 
 	args.NoForward()
 }
 
+// confirmReleaseDeletion asks the user whether to go ahead with deleting a
+// release. Without a terminal to ask on, the deletion is refused.
+func confirmReleaseDeletion(project *github.Project, tagName string, deleteTag bool) error {
+	if !ui.IsTerminal(os.Stdin) || !ui.IsTerminal(os.Stdout) {
+		return fmt.Errorf("Aborted: pass --yes to delete release `%s' when not running interactively", tagName)
+	}
+
+	subject := fmt.Sprintf("release `%s'", tagName)
+	if deleteTag {
+		subject += " and its git tag"
+	}
+	ui.Printf("Delete %s from %s? [y/N] ", subject, project)
+
+	var confirm string
+	prompt := bufio.NewScanner(os.Stdin)
+	if prompt.Scan() {
+		confirm = prompt.Text()
+	}
+	if strings.EqualFold(confirm, "y") || strings.EqualFold(confirm, "yes") {
+		return nil
+	}
+	return fmt.Errorf("Aborted: release `%s' was not deleted", tagName)
+}
+
+// fetchReleaseByTag finds the release for tagName, resolving the literal tag
+// name "latest" to the most recent published release
+func fetchReleaseByTag(gh *github.Client, project *github.Project, tagName string) (*github.Release, error) {
+	if tagName != "latest" {
+		return gh.FetchRelease(project, tagName)
+	}
+
+	release, err := gh.LatestRelease(project)
+	if err == nil && release == nil {
+		err = fmt.Errorf("Error: %s has no published releases", project)
+	}
+	return release, err
+}
+
 func uploadAssets(gh *github.Client, release *github.Release, assets []string, args *Args) {
 	for _, asset := range assets {
 		var label string
@@ -626,7 +857,7 @@ func uploadAssets(gh *github.Client, release *github.Release, assets []string, a
 					break
 				}
 			}
-			ui.Errorf("Attaching release asset `%s'...\n", asset)
+			ui.Noticef("Attaching release asset `%s'...\n", asset)
 			_, err := gh.UploadReleaseAsset(release, asset, label)
 			utils.Check(err)
 		}
@@ -0,0 +1,16 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestFormatAssetSize(t *testing.T) {
+	assert.Equal(t, "0 B", formatAssetSize(0))
+	assert.Equal(t, "999 B", formatAssetSize(999))
+	assert.Equal(t, "1.0 kB", formatAssetSize(1000))
+	assert.Equal(t, "4.2 kB", formatAssetSize(4213))
+	assert.Equal(t, "12.3 MB", formatAssetSize(12345678))
+	assert.Equal(t, "5.0 GB", formatAssetSize(5000000000))
+}
@@ -16,6 +16,7 @@ var cmdRemote = &Command{
 	Usage: `
 remote add [-p] [<OPTIONS>] <USER>[/<REPOSITORY>]
 remote set-url [-p] [<OPTIONS>] <NAME> <USER>[/<REPOSITORY>]
+remote set-url [--push] -p <PROTOCOL> <NAME>
 `,
 	Long: `Add a git remote for a GitHub repository.
 
@@ -25,6 +26,16 @@ remote set-url [-p] [<OPTIONS>] <NAME> <USER>[/<REPOSITORY>]
 		The writeable 'ssh:' protocol is automatically used for own repos, GitHub
 		Enterprise remotes, and private or pushable repositories.
 
+	-p <PROTOCOL>
+		With 'set-url' and a single remote <NAME>, rewrite the URL of an existing
+		GitHub remote to use <PROTOCOL>: one of "ssh", "https", or "git". The
+		owner and name of the repository are preserved. If a remote is named
+		<PROTOCOL> but <NAME> isn't a remote, the arguments are taken as
+		'-p <NAME> <USER>' instead.
+
+	--push
+		With '-p <PROTOCOL>', only rewrite the push URL of the remote.
+
 	<USER>[/<REPOSITORY>]
 		If <USER> is "origin", that value will be substituted for your GitHub
 		username. <REPOSITORY> defaults to the name of the current working directory.
@@ -36,6 +47,9 @@ remote set-url [-p] [<OPTIONS>] <NAME> <USER>[/<REPOSITORY>]
 		$ hub remote add origin
 		> git remote add origin git@github.com:USER/REPO.git
 
+		$ hub remote set-url -p https origin
+		> git remote set-url origin https://github.com/USER/REPO.git
+
 ## See also:
 
 hub-fork(1), hub(1), git-remote(1)
@@ -49,11 +63,81 @@ func init() {
 /*
  */
 func remote(command *Command, args *Args) {
+	if !args.IsParamsEmpty() && args.FirstParam() == "set-url" && transformRemoteProtocolArgs(args) {
+		return
+	}
 	if !args.IsParamsEmpty() && (args.FirstParam() == "add" || args.FirstParam() == "set-url") {
 		transformRemoteArgs(args)
 	}
 }
 
+var remoteProtocols = []string{"ssh", "https", "git"}
+
+// transformRemoteProtocolArgs handles `remote set-url [--push] -p <PROTOCOL> <NAME>`
+// and reports whether the arguments were in that form.
+func transformRemoteProtocolArgs(args *Args) bool {
+	i := args.IndexOfParam("-p")
+	if i < 0 || i+1 >= args.ParamsSize() || !isRemoteProtocol(args.GetParam(i+1)) {
+		return false
+	}
+
+	words := []string{}
+	for j, p := range args.Params {
+		if j > 0 && j != i+1 && !looksLikeFlag(p) {
+			words = append(words, p)
+		}
+	}
+	if len(words) != 1 {
+		return false
+	}
+
+	protocol := args.GetParam(i + 1)
+	remoteName := words[0]
+	isPush := args.IndexOfParam("--push") >= 0
+
+	localRepo, err := github.LocalRepo()
+	utils.Check(err)
+
+	// `set-url -p <NAME> <USER>` has the same shape when a remote is named
+	// after a protocol, so the words decide which form this is
+	remote, err := localRepo.RemoteByName(remoteName)
+	if _, protocolErr := localRepo.RemoteByName(protocol); protocolErr == nil {
+		if err != nil {
+			return false
+		}
+		utils.Check(fmt.Errorf("Error: both `%s' and `%s' are remotes, so it's unclear which one to set the URL of", protocol, remoteName))
+	}
+	utils.Check(err)
+
+	remoteURL := remote.URL
+	if isPush && remote.PushURL != nil {
+		remoteURL = remote.PushURL
+	}
+	if remoteURL == nil {
+		utils.Check(fmt.Errorf("Error: remote `%s' has no URL configured", remoteName))
+	}
+
+	url, err := github.ParseURL(remoteURL.String())
+	if err != nil {
+		utils.Check(fmt.Errorf("Error: remote `%s' does not point to a GitHub repository: %s", remoteName, remoteURL))
+	}
+
+	args.RemoveParam(i + 1)
+	args.RemoveParam(i)
+	args.AppendParams(url.Project.ProtocolURL(protocol))
+
+	return true
+}
+
+func isRemoteProtocol(value string) bool {
+	for _, protocol := range remoteProtocols {
+		if value == protocol {
+			return true
+		}
+	}
+	return false
+}
+
 func transformRemoteArgs(args *Args) {
 	ownerWithName := args.LastParam()
 	owner, name := parseRepoNameOwner(ownerWithName)
@@ -62,3 +62,27 @@ func TestTransformRemoteArgs(t *testing.T) {
 	assert.Equal(t, "add", args.FirstParam())
 	assert.Equal(t, "git@github.com:jingweno/gh.git", args.GetParam(2))
 }
+
+func TestTransformRemoteProtocolArgs(t *testing.T) {
+	repo := fixtures.SetupTestRepo()
+	defer repo.TearDown()
+
+	repo.AddRemote("mislav", "git://github.com/mislav/dotfiles.git", "")
+
+	args := NewArgs([]string{"remote", "set-url", "-p", "ssh", "mislav"})
+	assert.T(t, transformRemoteProtocolArgs(args))
+	assert.Equal(t, []string{"set-url", "mislav", "git@github.com:mislav/dotfiles.git"}, args.Params)
+
+	args = NewArgs([]string{"remote", "set-url", "--push", "-p", "https", "mislav"})
+	assert.T(t, transformRemoteProtocolArgs(args))
+	assert.Equal(t, []string{"set-url", "--push", "mislav", "https://github.com/mislav/dotfiles.git"}, args.Params)
+
+	args = NewArgs([]string{"remote", "set-url", "-p", "mislav", "jingweno"})
+	assert.T(t, !transformRemoteProtocolArgs(args))
+
+	repo.AddRemote("ssh", "git://github.com/mislav/dotfiles.git", "")
+
+	args = NewArgs([]string{"remote", "set-url", "-p", "ssh", "jingweno"})
+	assert.T(t, !transformRemoteProtocolArgs(args))
+	assert.Equal(t, []string{"set-url", "-p", "ssh", "jingweno"}, args.Params)
+}
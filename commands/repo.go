@@ -0,0 +1,136 @@
+package commands
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/github/hub/github"
+	"github.com/github/hub/ui"
+	"github.com/github/hub/utils"
+)
+
+var (
+	cmdRepo = &Command{
+		Run: printHelp,
+		Usage: `
+repo topics [--add <TOPIC>] [--remove <TOPIC>] [<OWNER>/<REPO>]
+`,
+		Long: `Manage settings of a GitHub repository.
+
+## Commands:
+
+	* _topics_:
+		List the topics of a repository, one per line. With '--add' or
+		'--remove', update the topics on GitHub and print the resulting set.
+
+## Options:
+
+	--add <TOPIC>
+		Add <TOPIC> to the repository topics. Multiple topics can be given as a
+		comma-separated list or by repeating the flag.
+
+	--remove <TOPIC>
+		Remove <TOPIC> from the repository topics. Multiple topics can be given as
+		a comma-separated list or by repeating the flag.
+
+	<OWNER>/<REPO>
+		The repository to operate on (default: the repository of the current
+		project).
+
+## Examples:
+		$ hub repo topics
+		[ lists topics of the current repository ]
+
+		$ hub repo topics --add go,cli --remove ruby
+		[ adds "go" and "cli" and removes "ruby" from the topics ]
+
+		$ hub repo topics github/hub
+		[ lists topics of github/hub ]
+
+## See also:
+
+hub(1)
+`,
+	}
+
+	cmdRepoTopics = &Command{
+		Key: "topics",
+		Run: repoTopics,
+		KnownFlags: `
+		--add TOPIC
+		--remove TOPIC
+`,
+	}
+)
+
+func init() {
+	cmdRepo.Use(cmdRepoTopics)
+	CmdRunner.Use(cmdRepo)
+}
+
+func repoTopics(cmd *Command, args *Args) {
+	var project *github.Project
+	if args.IsParamsEmpty() {
+		localRepo, err := github.LocalRepo()
+		utils.Check(err)
+		project, err = localRepo.MainProject()
+		utils.Check(err)
+	} else {
+		repoName := args.FirstParam()
+		re := regexp.MustCompile(NameWithOwnerRe)
+		if !strings.Contains(repoName, "/") || !re.MatchString(repoName) {
+			utils.Check(cmd.UsageError(""))
+		}
+
+		host, err := github.CurrentConfig().DefaultHost()
+		if err != nil {
+			utils.Check(github.FormatError("getting repository topics", err))
+		}
+		split := strings.SplitN(repoName, "/", 2)
+		project = github.NewProject(split[0], split[1], host.Host)
+	}
+
+	toAdd := commaSeparated(args.Flag.AllValues("--add"))
+	toRemove := commaSeparated(args.Flag.AllValues("--remove"))
+
+	gh := github.NewClient(project.Host)
+	topics, err := gh.RepositoryTopics(project)
+	utils.Check(err)
+
+	if len(toAdd) > 0 || len(toRemove) > 0 {
+		topics = mergeTopics(topics, toAdd, toRemove)
+		if args.Noop {
+			ui.Printf("Would update topics of '%s' to: %s\n", project, strings.Join(topics, ", "))
+			args.NoForward()
+			return
+		}
+		topics, err = gh.ReplaceRepositoryTopics(project, topics)
+		utils.Check(err)
+	}
+
+	for _, topic := range topics {
+		ui.Println(topic)
+	}
+
+	args.NoForward()
+}
+
+func mergeTopics(topics, toAdd, toRemove []string) []string {
+	removed := map[string]bool{}
+	for _, topic := range toRemove {
+		removed[strings.ToLower(topic)] = true
+	}
+
+	seen := map[string]bool{}
+	result := []string{}
+	for _, topic := range append(topics, toAdd...) {
+		topic = strings.ToLower(topic)
+		if removed[topic] || seen[topic] {
+			continue
+		}
+		seen[topic] = true
+		result = append(result, topic)
+	}
+
+	return result
+}
@@ -2,10 +2,12 @@ package commands
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 
 	"github.com/github/hub/cmd"
 	"github.com/github/hub/git"
+	"github.com/github/hub/github"
 	"github.com/github/hub/ui"
 	"github.com/kballard/go-shellquote"
 )
@@ -51,12 +53,49 @@ func (r *Runner) Execute(cliArgs []string) error {
 	}
 
 	git.GlobalFlags = args.GlobalFlags // preserve git global flags
+	github.NoHTTPSUpgrade = args.NoHTTPSUpgrade
+	github.NoRepositoryCache = args.NoCache
+	ui.SetVerbosity(args.Verbosity)
+	if args.Host != "" {
+		// "--host" takes precedence over GITHUB_HOST
+		github.GitHubHostEnv = args.Host
+	}
+	if enabled, _ := git.Config("hub.useTokenForGit"); args.UseTokenForGit || enabled == "true" {
+		github.CurrentConfig().UseTokensForGit()
+	}
+	if args.Scheme != "" {
+		if args.Scheme != "https" && args.Scheme != "http" {
+			return fmt.Errorf("Error: invalid --scheme `%s'; expected \"https\" or \"http\"", args.Scheme)
+		}
+		github.ForcedScheme = args.Scheme
+	}
 	if !isBuiltInHubCommand(cmdName) {
 		expandAlias(args)
 		cmdName = args.Command
 	}
 
 	cmd := r.Lookup(cmdName)
+
+	// hub's own commands also take "--repo" after the command name, while git
+	// commands have their own meaning for "-R"
+	if cmd != nil && cmd.Runnable() && !cmd.GitExtension {
+		if repo, params := extractRepoParam(args.Params); repo != "" {
+			args.Repo = repo
+			args.Params = params
+		}
+	}
+
+	if args.Repo != "" {
+		if cmd == nil || !cmd.Runnable() || commandsRequiringCheckout[cmdName] {
+			return fmt.Errorf("Error: the `--repo' flag cannot be used with `%s'", cmdName)
+		}
+		project, err := parseRepoOverride(args.Repo)
+		if err != nil {
+			return err
+		}
+		github.RepoOverride = project
+	}
+
 	if cmd != nil && cmd.Runnable() {
 		err := callRunnableCommand(cmd, args)
 		if err == nil && forceFail {
@@ -101,6 +140,7 @@ func printCommands(cmds []*cmd.Cmd) {
 
 func executeCommands(cmds []*cmd.Cmd, execFinal bool) error {
 	for i, c := range cmds {
+		git.WithCredentials(c)
 		var err error
 		// Run with `Exec` for the last command in chain
 		if execFinal && i == len(cmds)-1 {
@@ -130,6 +170,39 @@ func expandAlias(args *Args) {
 	}
 }
 
+// commandsRequiringCheckout lists commands that operate on a local clone and
+// therefore can't target another repository with "--repo"
+var commandsRequiringCheckout = map[string]bool{
+	"am":          true,
+	"apply":       true,
+	"checkout":    true,
+	"cherry-pick": true,
+	"fetch":       true,
+	"merge":       true,
+	"push":        true,
+	"remote":      true,
+	"submodule":   true,
+	"sync":        true,
+}
+
+// parseRepoOverride parses the "[<HOST>/]<OWNER>/<REPO>" value of the "--repo"
+// global flag
+func parseRepoOverride(value string) (*github.Project, error) {
+	host := ""
+	nameWithOwner := value
+	if parts := strings.Split(value, "/"); len(parts) == 3 {
+		host = parts[0]
+		nameWithOwner = parts[1] + "/" + parts[2]
+	}
+
+	re := regexp.MustCompile(NameWithOwnerRe)
+	if !strings.Contains(nameWithOwner, "/") || !re.MatchString(nameWithOwner) {
+		return nil, fmt.Errorf("Error: invalid repository `%s'; expected [<HOST>/]<OWNER>/<REPO>", value)
+	}
+
+	return github.NewProject(nameWithOwner, "", host), nil
+}
+
 func isBuiltInHubCommand(command string) bool {
 	for hubCommand, _ := range CmdRunner.All() {
 		if hubCommand == command {
@@ -13,11 +13,12 @@ import (
 
 var cmdSync = &Command{
 	Run:   sync,
-	Usage: "sync [--color]",
+	Usage: "sync [--color|--porcelain] [--tags] [--fetch-all] [--branch <BRANCH> [--rebase]] [[--remote] <REMOTE>]",
 	Long: `Fetch git objects from upstream and update local branches.
 
 - If the local branch is outdated, fast-forward it;
-- If the local branch contains unpushed work, warn about it;
+- If the local branch contains unpushed work, warn about it and tell whether
+  the branch has diverged from upstream or upstream was force-pushed;
 - If the branch seems merged and its upstream branch was deleted, delete it.
 
 If a local branch does not have any upstream configuration, but has a
@@ -28,6 +29,66 @@ same-named branch on the remote, treat that as its upstream branch.
 		Enable colored output even if stdout is not a terminal. <WHEN> can be one
 		of "always" (default for '--color'), "never", or "auto" (default).
 
+	--remote <REMOTE>
+		The git remote to fetch from and to sync local branches against. Defaults
+		to "upstream", "github", or "origin", in that order of preference.
+
+	--porcelain
+		Output one line per local branch in the "<STATUS> <BRANCH>" format, meant
+		to be parsed by scripts. Human-readable messages and warnings are not
+		shown. The vocabulary of <STATUS> is stable across releases:
+
+		uptodate: the branch is identical to its upstream
+
+		ff: the branch was fast-forwarded to its upstream
+
+		conflict: the branch contains unpushed commits and was left as-is
+
+		rebased: with '--rebase', the branch was rebased onto its upstream
+
+		deleted: the upstream branch was deleted and the branch, being merged,
+		was deleted as well
+
+		unmerged: the upstream branch was deleted, but the branch appears not
+		merged and was left as-is
+
+		untracked: the branch has no upstream on <REMOTE>
+
+		newtag: with '--tags', a tag that didn't exist locally was fetched; the
+		tag name takes the place of <BRANCH>
+
+	--tags
+		Also fetch all tags from <REMOTE> and report how many new tags were
+		fetched. By default, only tags pointing into the fetched branches are
+		updated, as with a plain git-fetch(1).
+
+	--fetch-all
+		Fetch from all git remotes instead of just <REMOTE> before updating local
+		branches. Branches are still synced against <REMOTE>.
+
+	--branch <BRANCH>
+		Only update the local branch <BRANCH> and leave all other branches
+		untouched. The branch must have an upstream on <REMOTE>. Unlike when
+		syncing all branches, a branch that is already up to date is reported.
+
+	--rebase
+		With '--branch', rebase <BRANCH> onto its upstream if it contains
+		unpushed commits, instead of only warning about them. If the rebase
+		fails, for example because of conflicts, it is aborted and the branch is
+		left as-is. A branch that only has unpushed commits is left unchanged.
+		With '--porcelain', a rebased branch is reported with the "rebased"
+		status.
+
+## Examples:
+		$ hub sync
+		[ fetches from the main remote and updates local branches ]
+
+		$ hub sync fork
+		[ fetches from the "fork" remote and updates branches tracking it ]
+
+		$ hub sync --branch feature --rebase
+		[ rebases only "feature" onto its upstream branch ]
+
 ## See also:
 
 hub(1), git-fetch(1)
@@ -42,7 +103,20 @@ func sync(cmd *Command, args *Args) {
 	localRepo, err := github.LocalRepo()
 	utils.Check(err)
 
-	remote, err := localRepo.MainRemote()
+	remoteName := args.Flag.Value("--remote")
+	if remoteName == "" && !args.IsParamsEmpty() {
+		remoteName = args.FirstParam()
+	}
+
+	var remote *github.Remote
+	if remoteName != "" {
+		if _, err := git.Config(fmt.Sprintf("remote.%s.url", remoteName)); err != nil {
+			utils.Check(fmt.Errorf("Error: no git remote named `%s'", remoteName))
+		}
+		remote, err = localRepo.RemoteByName(remoteName)
+	} else {
+		remote, err = localRepo.MainRemote()
+	}
 	utils.Check(err)
 
 	defaultBranch := localRepo.DefaultBranch(remote).ShortName()
@@ -52,9 +126,66 @@ func sync(cmd *Command, args *Args) {
 		currentBranch = curBranch.ShortName()
 	}
 
-	err = git.Spawn("fetch", "--prune", "--quiet", "--progress", remote.Name)
+	porcelain := args.Flag.Bool("--porcelain")
+	fetchTags := args.Flag.Bool("--tags")
+
+	onlyBranch := args.Flag.Value("--branch")
+	rebase := args.Flag.Bool("--rebase")
+	if rebase && onlyBranch == "" {
+		utils.Check(fmt.Errorf("Error: --rebase can only be used with --branch"))
+	}
+	if onlyBranch != "" {
+		if _, err := git.Ref("refs/heads/" + onlyBranch); err != nil {
+			utils.Check(fmt.Errorf("Error: no local branch named `%s'", onlyBranch))
+		}
+	}
+
+	fetchArgs := []string{"fetch", "--prune", "--quiet"}
+	if !porcelain && !ui.IsQuiet() {
+		fetchArgs = append(fetchArgs, "--progress")
+	}
+	if fetchTags {
+		fetchArgs = append(fetchArgs, "--tags")
+	}
+	if args.Flag.Bool("--fetch-all") {
+		fetchArgs = append(fetchArgs, "--all")
+	} else {
+		fetchArgs = append(fetchArgs, remote.Name)
+	}
+
+	oldTags := map[string]bool{}
+	if fetchTags {
+		tags, err := git.Tags()
+		utils.Check(err)
+		for _, tag := range tags {
+			oldTags[tag] = true
+		}
+	}
+
+	err = git.Spawn(fetchArgs...)
 	utils.Check(err)
 
+	if fetchTags {
+		tags, err := git.Tags()
+		utils.Check(err)
+		newTags := 0
+		for _, tag := range tags {
+			if !oldTags[tag] {
+				newTags++
+				if porcelain {
+					ui.Printf("newtag %s\n", tag)
+				}
+			}
+		}
+		if !porcelain {
+			noun := "tags"
+			if newTags == 1 {
+				noun = "tag"
+			}
+			ui.Infof("Fetched %d new %s.\n", newTags, noun)
+		}
+	}
+
 	branchToRemote := map[string]string{}
 	if lines, err := git.ConfigAll("branch.*.remote"); err == nil {
 		configRe := regexp.MustCompile(`^branch\.(.+?)\.remote (.+)`)
@@ -68,6 +199,9 @@ func sync(cmd *Command, args *Args) {
 
 	branches, err := git.LocalBranches()
 	utils.Check(err)
+	if onlyBranch != "" {
+		branches = []string{onlyBranch}
+	}
 
 	var green,
 		lightGreen,
@@ -76,7 +210,7 @@ func sync(cmd *Command, args *Args) {
 		resetColor string
 
 	colorize := colorizeOutput(args.Flag.HasReceived("--color"), args.Flag.Value("--color"))
-	if colorize {
+	if colorize && !porcelain {
 		green = "\033[32m"
 		lightGreen = "\033[32;1m"
 		red = "\033[31m"
@@ -100,21 +234,51 @@ func sync(cmd *Command, args *Args) {
 			remoteBranch = ""
 		}
 
+		if onlyBranch != "" && remoteBranch == "" && !gone {
+			utils.Check(fmt.Errorf("Error: branch `%s' doesn't track an upstream branch on %s", branch, remote.Name))
+		}
+
 		if remoteBranch != "" {
 			diff, err := git.NewRange(fullBranch, remoteBranch)
 			utils.Check(err)
 
 			if diff.IsIdentical() {
-				continue
+				if porcelain {
+					ui.Printf("uptodate %s\n", branch)
+				} else if onlyBranch != "" {
+					ui.Infof("Branch %s is up to date.\n", branch)
+				}
 			} else if diff.IsAncestor() {
 				if branch == currentBranch {
 					git.Quiet("merge", "--ff-only", "--quiet", remoteBranch)
 				} else {
 					git.Quiet("update-ref", fullBranch, remoteBranch)
 				}
-				ui.Printf("%sUpdated branch %s%s%s (was %s).\n", green, lightGreen, branch, resetColor, diff.A[0:7])
-			} else {
+				if porcelain {
+					ui.Printf("ff %s\n", branch)
+				} else {
+					ui.Infof("%sUpdated branch %s%s%s (was %s).\n", green, lightGreen, branch, resetColor, diff.A[0:7])
+				}
+			} else if rebase && (&git.Range{A: remoteBranch, B: fullBranch}).IsAncestor() {
+				if porcelain {
+					ui.Printf("conflict %s\n", branch)
+				} else {
+					ui.Infof("Branch %s is ahead of %s, so it was left unchanged.\n", branch, strings.TrimPrefix(remoteBranch, "refs/remotes/"))
+				}
+			} else if rebase && rebaseBranch(branch, remoteBranch, currentBranch) {
+				if porcelain {
+					ui.Printf("rebased %s\n", branch)
+				} else {
+					ui.Infof("%sRebased branch %s%s%s onto %s (was %s).\n", green, lightGreen, branch, resetColor, strings.TrimPrefix(remoteBranch, "refs/remotes/"), diff.A[0:7])
+				}
+			} else if porcelain {
+				ui.Printf("conflict %s\n", branch)
+			} else if rebase {
+				ui.Errorf("warning: rebasing `%s' onto %s failed, so the rebase was aborted\n", branch, strings.TrimPrefix(remoteBranch, "refs/remotes/"))
+			} else if (&git.Range{A: remoteBranch, B: fullBranch}).IsAncestor() {
 				ui.Errorf("warning: `%s' seems to contain unpushed commits\n", branch)
+			} else {
+				warnDiverged(branch, strings.TrimPrefix(remoteBranch, "refs/remotes/"), branch == currentBranch, upstreamRewritten(remoteBranch))
 			}
 		} else if gone {
 			diff, err := git.NewRange(fullBranch, fullDefaultBranch)
@@ -126,12 +290,69 @@ func sync(cmd *Command, args *Args) {
 					currentBranch = defaultBranch
 				}
 				git.Quiet("branch", "-D", branch)
-				ui.Printf("%sDeleted branch %s%s%s (was %s).\n", red, lightRed, branch, resetColor, diff.A[0:7])
+				if porcelain {
+					ui.Printf("deleted %s\n", branch)
+				} else {
+					ui.Infof("%sDeleted branch %s%s%s (was %s).\n", red, lightRed, branch, resetColor, diff.A[0:7])
+				}
+			} else if porcelain {
+				ui.Printf("unmerged %s\n", branch)
 			} else {
 				ui.Errorf("warning: `%s' was deleted on %s, but appears not merged into %s\n", branch, remote.Name, defaultBranch)
 			}
+		} else if porcelain {
+			ui.Printf("untracked %s\n", branch)
 		}
 	}
 
 	args.NoForward()
 }
+
+// rebaseBranch rebases branch onto upstream and switches back to the branch
+// that was checked out before, even if the rebase failed and was aborted.
+func rebaseBranch(branch, upstream, currentBranch string) bool {
+	if branch == currentBranch {
+		if git.Quiet("rebase", "--quiet", upstream) {
+			return true
+		}
+		git.Quiet("rebase", "--abort")
+		return false
+	}
+
+	previous := currentBranch
+	if previous == "" {
+		previous, _ = git.Ref("HEAD")
+	}
+	ok := git.Quiet("rebase", "--quiet", upstream, branch)
+	if !ok {
+		git.Quiet("rebase", "--abort")
+	}
+	git.Quiet("checkout", "--quiet", previous)
+	return ok
+}
+
+// upstreamRewritten reports whether the last update of a remote-tracking
+// branch dropped commits that it used to have, as happens after a force-push
+func upstreamRewritten(remoteBranch string) bool {
+	previous, err := git.Ref(remoteBranch + "@{1}")
+	if err != nil {
+		return false
+	}
+	diff := &git.Range{A: previous, B: remoteBranch}
+	return !diff.IsAncestor()
+}
+
+// warnDiverged explains why a branch that has commits its upstream doesn't
+// have, and vice versa, was left as-is
+func warnDiverged(branch, upstream string, isCurrent, forcePushed bool) {
+	if forcePushed {
+		ui.Errorf("warning: `%s' can't be fast-forwarded because %s was force-pushed\n", branch, upstream)
+	} else {
+		ui.Errorf("warning: `%s' and %s have diverged; each has commits the other doesn't\n", branch, upstream)
+	}
+	checkout := ""
+	if !isCurrent {
+		checkout = fmt.Sprintf("`git checkout %s`, then ", branch)
+	}
+	ui.Errorf("(use %s`git pull --rebase` to replay local commits on top of %s, or `git reset --hard @{u}` to discard them)\n", checkout, upstream)
+}
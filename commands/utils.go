@@ -2,14 +2,20 @@ package commands
 
 import (
 	"bufio"
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/atotto/clipboard"
 	"github.com/github/hub/git"
+	"github.com/github/hub/github"
 	"github.com/github/hub/ui"
 	"github.com/github/hub/utils"
 )
@@ -103,6 +109,13 @@ func msgFromFile(filename string) (string, error) {
 }
 
 func printBrowseOrCopy(args *Args, msg string, openBrowser bool, performCopy bool) {
+	// "--dump-url" is accepted by every command that can open a web browser so
+	// that the URL can be obtained on machines without one
+	dumpURL := openBrowser && args.Flag.Bool("--dump-url")
+	if dumpURL {
+		openBrowser = false
+	}
+
 	if performCopy {
 		if err := clipboard.WriteAll(msg); err != nil {
 			ui.Errorf("Error copying %s to clipboard:\n%s\n", msg, err.Error())
@@ -110,16 +123,220 @@ func printBrowseOrCopy(args *Args, msg string, openBrowser bool, performCopy boo
 	}
 
 	if openBrowser {
-		launcher, err := utils.BrowserLauncher()
-		utils.Check(err)
-		args.Replace(launcher[0], "", launcher[1:]...)
-		args.AppendParams(msg)
+		if launcher, err := utils.BrowserLauncher(); err == nil {
+			args.Replace(launcher[0], "", launcher[1:]...)
+			args.AppendParams(msg)
+		} else {
+			// on a headless machine, behave as if "--dump-url" was given
+			ui.Errorln("No web browser found; printing the URL instead (set $HUB_BROWSER or $BROWSER to a web launcher)")
+			openBrowser = false
+			dumpURL = true
+		}
 	}
 
-	if !openBrowser && !performCopy {
+	if (!openBrowser && !performCopy) || dumpURL {
 		args.AfterFn(func() error {
 			ui.Println(msg)
 			return nil
 		})
 	}
 }
+
+// lastURLFile is the file in the git directory that keeps the URL of the
+// pull request, issue, release, or repository that was created last
+const lastURLFile = "hub-last-url"
+
+// recordLastURL remembers u for 'browse --last'. Failing to record it isn't
+// worth interrupting the command that created something.
+func recordLastURL(args *Args, u string) {
+	if args.Noop {
+		return
+	}
+	if gitDir, err := git.Dir(); err == nil {
+		ioutil.WriteFile(filepath.Join(gitDir, lastURLFile), []byte(u+"\n"), 0644)
+	}
+}
+
+// readLastURL returns the URL that was last recorded in this repository
+func readLastURL() (string, error) {
+	gitDir, err := git.Dir()
+	if err != nil {
+		return "", err
+	}
+	content, err := ioutil.ReadFile(filepath.Join(gitDir, lastURLFile))
+	if os.IsNotExist(err) || err == nil && strings.TrimSpace(string(content)) == "" {
+		return "", fmt.Errorf("Error: nothing has been created from this repository yet")
+	} else if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(content)), nil
+}
+
+func printJSONLine(raw json.RawMessage) {
+	line := &bytes.Buffer{}
+	if err := json.Compact(line, raw); err != nil {
+		utils.Check(err)
+	}
+	ui.Println(line.String())
+}
+
+// checkLabels compares labels against the ones defined in project, matching
+// names case-insensitively, and returns the known labels as spelled in the
+// repository. Unknown labels abort with an error in strict mode and are
+// otherwise reported as warnings and left out.
+func checkLabels(gh *github.Client, project *github.Project, labels []string, strict bool) ([]string, error) {
+	repoLabels, err := gh.FetchLabels(project)
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	byName := map[string]string{}
+	for _, label := range repoLabels {
+		names = append(names, label.Name)
+		byName[strings.ToLower(label.Name)] = label.Name
+	}
+
+	known := []string{}
+	for _, label := range labels {
+		if name, ok := byName[strings.ToLower(label)]; ok {
+			known = append(known, name)
+			continue
+		}
+
+		problem := fmt.Sprintf("no such label: %s", label)
+		if suggestion := closestMatch(label, names); suggestion != "" {
+			problem = fmt.Sprintf("%s (did you mean %s?)", problem, suggestion)
+		}
+		if strict {
+			return nil, fmt.Errorf("Error: %s", problem)
+		}
+		ui.Errorf("Warning: %s\n", problem)
+	}
+
+	return known, nil
+}
+
+// closestMatch returns the candidate that is the fewest edits away from s,
+// ignoring case, or an empty string if none of them is reasonably close
+func closestMatch(s string, candidates []string) string {
+	best := ""
+	bestDistance := len(s)/3 + 2
+	for _, candidate := range candidates {
+		distance := editDistance(strings.ToLower(s), strings.ToLower(candidate))
+		if distance < bestDistance {
+			best = candidate
+			bestDistance = distance
+		}
+	}
+	return best
+}
+
+// editDistance computes the Levenshtein distance between two strings
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		cur := make([]int, len(rb)+1)
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = minInt(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+
+	return prev[len(rb)]
+}
+
+func minInt(first int, rest ...int) int {
+	min := first
+	for _, n := range rest {
+		if n < min {
+			min = n
+		}
+	}
+	return min
+}
+
+var relativeDateRe = regexp.MustCompile(`^(\d+)\s*(h|hours?|d|days?|w|weeks?|mo|months?|y|years?)(?:\s+ago)?$`)
+
+// parseDate interprets a date given on the command line. It accepts a date in
+// the "YYYY-MM-DD" format, a timestamp in the ISO 8601 format, or a duration
+// relative to now such as "3d", "2 weeks", or "1 month ago".
+func parseDate(value string, now time.Time) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	if matches := relativeDateRe.FindStringSubmatch(strings.TrimSpace(value)); matches != nil {
+		n, _ := strconv.Atoi(matches[1])
+		switch matches[2][0:1] {
+		case "h":
+			return now.Add(-time.Duration(n) * time.Hour), nil
+		case "d":
+			return now.AddDate(0, 0, -n), nil
+		case "w":
+			return now.AddDate(0, 0, -7*n), nil
+		case "m":
+			return now.AddDate(0, -n, 0), nil
+		case "y":
+			return now.AddDate(-n, 0, 0), nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid date `%s'; expected YYYY-MM-DD, an ISO 8601 timestamp, or a relative date such as \"2 weeks ago\"", value)
+}
+
+var signedOffByRe = regexp.MustCompile(`\nSigned-off-by:\s.*$`)
+
+// commitsBody joins the messages of commits, given newest first, into a
+// description that lists the oldest one first. With a positive limit, only
+// that many of the newest commits are used.
+func commitsBody(commits []string, limit int) (string, error) {
+	if limit > 0 && len(commits) > limit {
+		commits = commits[:limit]
+	}
+
+	messages := []string{}
+	for i := len(commits) - 1; i >= 0; i-- {
+		message, err := git.Show(commits[i])
+		if err != nil {
+			return "", err
+		}
+		messages = append(messages, signedOffByRe.ReplaceAllString(message, ""))
+	}
+	return strings.Join(messages, "\n\n"), nil
+}
+
+// bodyFromCommitsLimit reads the optional <N> of "--body-from-commits=<N>".
+// It returns 0 when no number was given.
+func bodyFromCommitsLimit(args *Args) (int, error) {
+	value := args.Flag.Value("--body-from-commits")
+	if value == "" {
+		return 0, nil
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit <= 0 {
+		return 0, fmt.Errorf("Error: invalid --body-from-commits value `%s'; expected a positive number", value)
+	}
+	return limit, nil
+}
+
+// appendParagraph adds text as a new paragraph at the end of message
+func appendParagraph(message, text string) string {
+	if strings.TrimSpace(message) == "" {
+		return text
+	}
+	return strings.TrimRight(message, "\n") + "\n\n" + text
+}
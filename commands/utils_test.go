@@ -4,6 +4,7 @@ import (
 	"io/ioutil"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/bmizerany/assert"
 )
@@ -30,3 +31,42 @@ func createTempDir(t *testing.T) string {
 	}
 	return dir
 }
+
+func TestEditDistance(t *testing.T) {
+	assert.Equal(t, 0, editDistance("bug", "bug"))
+	assert.Equal(t, 1, editDistance("enhancment", "enhancement"))
+	assert.Equal(t, 2, editDistance("bgu", "bug"))
+	assert.Equal(t, 3, editDistance("", "bug"))
+}
+
+func TestClosestMatch(t *testing.T) {
+	labels := []string{"bug", "documentation", "Enhancement"}
+	assert.Equal(t, "Enhancement", closestMatch("enhancment", labels))
+	assert.Equal(t, "bug", closestMatch("bgu", labels))
+	assert.Equal(t, "documentation", closestMatch("documentaton", labels))
+	assert.Equal(t, "", closestMatch("wontfix", labels))
+}
+
+func TestParseDate(t *testing.T) {
+	now := time.Date(2019, 3, 15, 12, 0, 0, 0, time.UTC)
+
+	date, err := parseDate("2019-01-02", now)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, time.Date(2019, 1, 2, 0, 0, 0, 0, time.Local), date)
+
+	date, err = parseDate("2019-01-02T10:30:00Z", now)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, time.Date(2019, 1, 2, 10, 30, 0, 0, time.UTC), date.UTC())
+
+	date, _ = parseDate("3d", now)
+	assert.Equal(t, time.Date(2019, 3, 12, 12, 0, 0, 0, time.UTC), date)
+
+	date, _ = parseDate("2 weeks ago", now)
+	assert.Equal(t, time.Date(2019, 3, 1, 12, 0, 0, 0, time.UTC), date)
+
+	date, _ = parseDate("1 month", now)
+	assert.Equal(t, time.Date(2019, 2, 15, 12, 0, 0, 0, time.UTC), date)
+
+	_, err = parseDate("yesterday", now)
+	assert.NotEqual(t, nil, err)
+}
@@ -1,15 +1,30 @@
 package commands
 
 import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/github/hub/git"
 	"github.com/github/hub/ui"
 	"github.com/github/hub/utils"
 	"github.com/github/hub/version"
 )
 
 var cmdVersion = &Command{
-	Run:          runVersion,
-	Usage:        "version",
-	Long:         "Shows git version and hub client version.",
+	Run:   runVersion,
+	Usage: "version [--short|--json]",
+	Long: `Shows git version and hub client version.
+
+## Options:
+	--short
+		Print only the version of hub, such as "2.14.2".
+
+	--json
+		Print the versions of hub and git as a JSON object with the "hub" and
+		"git" keys, for example '{"hub":"2.14.2","git":"2.30.1"}'. The "git" value
+		is null if the version of git couldn't be determined.
+`,
 	GitExtension: true,
 }
 
@@ -18,10 +33,40 @@ func init() {
 }
 
 func runVersion(cmd *Command, args *Args) {
+	args.NoForward()
+
+	short := args.IndexOfParam("--short") >= 0
+	asJSON := args.IndexOfParam("--json") >= 0
+	if short && asJSON {
+		utils.Check(fmt.Errorf("Error: the `--short' and `--json' flags can't be used together"))
+	}
+
+	if short {
+		ui.Println(version.Version)
+		return
+	}
+
+	if asJSON {
+		info := struct {
+			Hub string  `json:"hub"`
+			Git *string `json:"git"`
+		}{Hub: version.Version}
+
+		gitVersion, err := git.Version()
+		if err == nil {
+			gitVersion = strings.TrimPrefix(gitVersion, "git version ")
+			info.Git = &gitVersion
+		}
+		output, jsonErr := json.Marshal(info)
+		utils.Check(jsonErr)
+		ui.Println(string(output))
+		utils.Check(err)
+		return
+	}
+
 	output, err := version.FullVersion()
 	if output != "" {
 		ui.Println(output)
 	}
 	utils.Check(err)
-	args.NoForward()
 }
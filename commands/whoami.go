@@ -0,0 +1,123 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/github/hub/github"
+	"github.com/github/hub/ui"
+	"github.com/github/hub/utils"
+)
+
+var cmdWhoami = &Command{
+	Run:   whoami,
+	Usage: "whoami [--all]",
+	Long: `Show the GitHub account that hub is authenticated as.
+
+For the default GitHub host, print the login of the authenticated user, the
+OAuth scopes granted to the access token, a redacted form of the token, and the
+URL of the host. The exit status is nonzero if hub is not authenticated.
+GITHUB_TOKEN only applies to the default host. The account is looked up again
+after a minute, or right away with 'hub --no-cache whoami'.
+
+## Options:
+	--all
+		Show information for every host in the hub configuration file instead of
+		just the default one.
+
+## Examples:
+		$ hub whoami
+		github.com
+		  User: octocat
+		  Scopes: gist, repo
+		  Token: ********d1f4
+		  URL: https://github.com
+
+## See also:
+
+hub(1)
+`,
+}
+
+func init() {
+	CmdRunner.Use(cmdWhoami)
+}
+
+func whoami(cmd *Command, args *Args) {
+	config := github.CurrentConfig()
+
+	// the default host is looked up without DefaultHostNoPrompt, which would
+	// ask for credentials instead of reporting that hub isn't logged in
+	defaultHost := config.DefaultHostName()
+
+	// GITHUB_TOKEN is only ever sent to the default host
+	envToken := config.DetectToken()
+
+	hosts := []*github.Host{}
+	if args.Flag.Bool("--all") {
+		hosts = append(hosts, config.Hosts...)
+	}
+	if len(hosts) == 0 || (envToken != "" && config.Find(defaultHost) == nil) {
+		host := config.Find(defaultHost)
+		if host == nil {
+			host = &github.Host{Host: defaultHost, Protocol: "https"}
+		}
+		hosts = append(hosts, host)
+	}
+
+	errors := []string{}
+	printed := 0
+	for _, configHost := range hosts {
+		host := *configHost
+		if envToken != "" && host.Host == defaultHost {
+			host.AccessToken = envToken
+		}
+		if host.AccessToken == "" {
+			errors = append(errors, fmt.Sprintf("Error: not logged in to %s", host.Host))
+			continue
+		}
+
+		gh := github.NewClientWithHost(&host)
+		user, scopes, err := gh.CachedCurrentUserScopes()
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("Error: not logged in to %s\n%s", host.Host, err))
+			continue
+		}
+
+		scopeList := "(none)"
+		if len(scopes) > 0 {
+			scopeList = strings.Join(scopes, ", ")
+		}
+
+		if printed > 0 {
+			ui.Println()
+		}
+		printed++
+		ui.Println(host.Host)
+		ui.Printf("  User: %s\n", user.Login)
+		ui.Printf("  Scopes: %s\n", scopeList)
+		ui.Printf("  Token: %s\n", redactToken(host.AccessToken))
+		ui.Printf("  URL: %s\n", hostURL(&host))
+	}
+
+	args.NoForward()
+	if len(errors) > 0 {
+		utils.Check(fmt.Errorf("%s", strings.Join(errors, "\n")))
+	}
+}
+
+// redactToken masks all but the last four characters of an access token
+func redactToken(token string) string {
+	if len(token) <= 8 {
+		return strings.Repeat("*", len(token))
+	}
+	return "********" + token[len(token)-4:]
+}
+
+func hostURL(host *github.Host) string {
+	protocol := host.Protocol
+	if protocol == "" {
+		protocol = "https"
+	}
+	return fmt.Sprintf("%s://%s", protocol, host.Host)
+}
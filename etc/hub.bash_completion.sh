@@ -20,13 +20,16 @@ pull-request
 pr
 issue
 release
+repo
 fork
 create
 delete
 browse
 compare
 ci-status
+prune-remote
 sync
+whoami
 EOF
     __git_list_all_commands_without_hub
   }
@@ -259,7 +262,7 @@ EOF
         # (Doesn't seem to need this...)
         # Uncomment the following line when 'owner/repo:[TAB]' misbehaved
         #_get_comp_words_by_ref -n : cur
-        __gitcomp_nl "$(__hub_heads)"
+        __gitcomp_nl "$( (__hub_heads; __hub_complete branches) | sort -u)"
         # __ltrim_colon_completions "$cur"
         ;;
       -F)
@@ -271,10 +274,83 @@ EOF
     esac
   }
 
+  # hub pr list [-b BASE] [-h HEAD]
+  # hub pr checkout PR-NUMBER [BRANCH]
+  _git_pr() {
+    local i c=2 subcommand
+    while [ $c -lt $cword ]; do
+      i="${words[c]}"
+      case "$i" in
+        -*)
+          ;;
+        *)
+          if [ -z "$subcommand" ]; then
+            subcommand=$i
+          fi
+          ;;
+      esac
+      ((c++))
+    done
+    case "$subcommand" in
+      "")
+        __gitcomp "list checkout edit"
+        ;;
+      checkout)
+        if [ "$prev" = checkout ]; then
+          __gitcomp_nl "$(__hub_complete prs | cut -f1)"
+        else
+          __gitcomp_nl "$(__hub_complete branches)"
+        fi
+        ;;
+      list)
+        case "$prev" in
+          -b|--base|-h|--head)
+            __gitcomp_nl "$(__hub_complete branches)"
+            ;;
+          *)
+            __gitcomp "--state --base --head --labels --limit --format"
+            ;;
+        esac
+        ;;
+    esac
+  }
+
+  # hub prune-remote [--remote REMOTE]
+  _git_prune_remote() {
+    case "$prev" in
+      --remote)
+        __gitcomp_nl "$(__hub_complete remotes)"
+        ;;
+      *)
+        __gitcomp "--remote --dry-run --yes"
+        ;;
+    esac
+  }
+
+  # hub sync [--remote REMOTE]
+  _git_sync() {
+    case "$prev" in
+      --remote)
+        __gitcomp_nl "$(__hub_complete remotes)"
+        ;;
+      *)
+        __gitcomp "--remote --porcelain --color"
+        ;;
+    esac
+  }
+
   ###################
   # Helper functions
   ###################
 
+  # __hub_complete TYPE
+  # List the candidates that hub computes at tab-time, one per line
+  # TYPE - One of "remotes", "branches", or "prs". Pull requests are printed
+  #        as "NUMBER<Tab>TITLE".
+  __hub_complete() {
+    command hub __complete "$1" 2>/dev/null
+  }
+
   # __hub_github_user [HOST]
   # Return $GITHUB_USER or the default github user defined in hub config
   # HOST - Host to be looked-up in hub config. Default is "github.com"
@@ -49,6 +49,59 @@ Feature: hub api
       """
     And the stderr should contain exactly ""
 
+  Scenario: Non-success response with rate limit details
+    Given the GitHub API server:
+      """
+      get('/hello/world') {
+        response.headers['X-RateLimit-Limit'] = '60'
+        response.headers['X-RateLimit-Remaining'] = '0'
+        response.headers['X-RateLimit-Reset'] = '1546300800'
+        status 403
+        json :message => "API rate limit exceeded",
+             :documentation_url => "https://developer.github.com/v3/#rate-limiting"
+      }
+      """
+    When I run `hub api --include-rate-limit-in-error hello/world`
+    Then the exit status should be 22
+    And the stdout should contain exactly:
+      """
+      {"message":"API rate limit exceeded","documentation_url":"https://developer.github.com/v3/#rate-limiting"}
+      """
+    And the stderr should contain exactly:
+      """
+      Error: HTTP 403 Forbidden
+      Message: API rate limit exceeded
+      Documentation: https://developer.github.com/v3/#rate-limiting
+      Rate limit remaining: 0 of 60
+      Rate limit resets at: 2019-01-01T00:00:00Z\n
+      """
+
+  Scenario: Silent non-success response
+    Given the GitHub API server:
+      """
+      get('/hello/world') {
+        status 404
+        json :message => "Not Found"
+      }
+      """
+    When I run `hub api -i --silent hello/world`
+    Then the exit status should be 22
+    And the stdout should contain exactly ""
+    And the stderr should contain exactly ""
+
+  Scenario: Silent successful response
+    Given the GitHub API server:
+      """
+      get('/hello/world') {
+        json :name => "Ed"
+      }
+      """
+    When I successfully run `hub api --silent hello/world`
+    Then the output should contain exactly:
+      """
+      {"name":"Ed"}
+      """
+
   Scenario: Non-success response flat output
     Given the GitHub API server:
       """
@@ -65,6 +118,111 @@ Feature: hub api
       """
     And the stderr should contain exactly ""
 
+  Scenario: Flat output of a non-JSON response
+    Given the GitHub API server:
+      """
+      get('/hello/world') {
+        content_type :text
+        'Hello, world'
+      }
+      """
+    When I successfully run `hub api -t hello/world`
+    Then the output should contain exactly "Hello, world"
+
+  Scenario: Write the response body to a file
+    Given the GitHub API server:
+      """
+      get('/repos/octocat/hello/tarball/main') {
+        content_type 'application/x-gzip'
+        "tarball\tcontents"
+      }
+      """
+    When I successfully run `hub api -t repos/octocat/hello/tarball/main -o out.tgz`
+    Then the output should contain exactly ""
+    And the file "out.tgz" should contain exactly "tarball\tcontents"
+
+  Scenario: Write a JSON response to a file without formatting it
+    Given the GitHub API server:
+      """
+      get('/hello/world') {
+        json :name => "Ed"
+      }
+      """
+    When I successfully run `hub api --flat --out hello.json hello/world`
+    Then the output should contain exactly ""
+    And the file "hello.json" should contain exactly "{\"name\":\"Ed\"}"
+
+  Scenario: Format the response with a template
+    Given the GitHub API server:
+      """
+      get('/repos/octocat/hello/contributors') {
+        json [
+          { :login => "mislav", :contributions => 1200 },
+          { :login => "octocat", :contributions => 7 },
+        ]
+      }
+      """
+    When I successfully run `hub api repos/octocat/hello/contributors --template '{{range .}}{{.login}}: {{.contributions}}{{"\n"}}{{end}}{{pluck "login" . | join ", "}}{{"\n"}}{{index . 1 | json}}'`
+    Then the output should contain exactly:
+      """
+      mislav: 1200
+      octocat: 7
+      mislav, octocat
+      {"contributions":7,"login":"octocat"}
+      """
+
+  Scenario: Colorize template output
+    Given the GitHub API server:
+      """
+      get('/hello/world') {
+        json :name => "Ed"
+      }
+      """
+    When I successfully run `hub api --color=always hello/world --template '{{color "green" .name}}'`
+    Then the output should contain exactly "\e[32mEd\e[m"
+
+  Scenario: Template can't be combined with flat output
+    When I run `hub api -t hello/world --template '{{.name}}'`
+    Then the exit status should be 1
+    And the stderr should contain exactly "Error: the `--template' and `--flat' flags can't be used together\n"
+
+  Scenario: Filter the response with --jq
+    Given the GitHub API server:
+      """
+      get('/repos/octocat/hello/pulls') {
+        json [
+          { :number => 12, :title => "Fix bug", :draft => false },
+          { :number => 13, :title => "WIP", :draft => true },
+        ]
+      }
+      """
+    When I successfully run `hub api repos/octocat/hello/pulls --jq '.[] | select(.draft == false) | .number, .title'`
+    Then the output should contain exactly:
+      """
+      12
+      Fix bug\n
+      """
+
+  Scenario: Unsupported --jq syntax
+    When I run `hub api hello/world --jq 'map(.name)'`
+    Then the exit status should be 1
+    And the stderr should contain exactly "Error: invalid --jq expression: unsupported function `map' at position 1\n"
+
+  Scenario: Print the error response as is with --jq
+    Given the GitHub API server:
+      """
+      get('/repos/octocat/hello/pulls') {
+        status 404
+        json :message => "Not Found"
+      }
+      """
+    When I run `hub api repos/octocat/hello/pulls --jq '.[].title'`
+    Then the exit status should be 22
+    And the stdout should contain exactly:
+      """
+      {"message":"Not Found"}
+      """
+
   Scenario: Non-success response doesn't choke on non-JSON
     Given the GitHub API server:
       """
@@ -95,6 +253,37 @@ Feature: hub api
       {"bool":"false","name":"Ed","num":"12","void":""}
       """
 
+  Scenario: Method in lowercase
+    Given the GitHub API server:
+      """
+      patch('/repos/mislav/dotfiles') {
+        json :description => params[:description]
+      }
+      """
+    When I successfully run `hub api -X patch -F description=dots repos/mislav/dotfiles`
+    Then the output should contain exactly:
+      """
+      {"description":"dots"}
+      """
+    And the stderr should contain exactly ""
+
+  Scenario: Invalid method
+    When I run `hub api -X FETCH hello/world`
+    Then the exit status should be 1
+    And the stderr should contain exactly "Error: invalid --method `FETCH'; expected one of GET, HEAD, POST, PUT, PATCH, DELETE, OPTIONS\n"
+
+  Scenario: Warn about inferred POST to a read-only endpoint
+    Given the GitHub API server:
+      """
+      post('/search/issues') {
+        status 404
+        json :message => "Not Found"
+      }
+      """
+    When I run `hub api -F q=hub search/issues`
+    Then the exit status should be 22
+    And the stderr should contain "Warning: sending a POST request to `search/issues' because fields were given; use -XGET to send them in the query string instead\n"
+
   Scenario: GET full URL
     Given the GitHub API server:
       """
@@ -109,6 +298,242 @@ Feature: hub api
       {"name":"Faye"}
       """
 
+  Scenario: Preview media type for an endpoint that requires it
+    Given the GitHub API server:
+      """
+      get('/repos/mislav/dotfiles/topics') {
+        halt 400 unless request.env['HTTP_ACCEPT'] == 'application/vnd.github.mercy-preview+json'
+        json :names => ["vim"]
+      }
+      """
+    When I successfully run `hub api repos/mislav/dotfiles/topics`
+    Then the output should contain exactly:
+      """
+      {"names":["vim"]}
+      """
+
+  Scenario: Accept header given on the command line overrides the preview
+    Given the GitHub API server:
+      """
+      get('/repos/mislav/dotfiles/topics') {
+        halt 400 unless request.env['HTTP_ACCEPT'] == 'application/json'
+        json :names => ["vim"]
+      }
+      """
+    When I successfully run `hub api -H "accept: application/json" repos/mislav/dotfiles/topics`
+    Then the output should contain exactly:
+      """
+      {"names":["vim"]}
+      """
+
+  Scenario: Disable preview media types
+    Given the GitHub API server:
+      """
+      get('/repos/mislav/dotfiles/topics') {
+        halt 400 unless request.env['HTTP_ACCEPT'] == 'application/vnd.github.v3+json;charset=utf-8'
+        json :names => ["vim"]
+      }
+      """
+    When I successfully run `hub api --no-preview repos/mislav/dotfiles/topics`
+    Then the output should contain exactly:
+      """
+      {"names":["vim"]}
+      """
+
+  Scenario: Paginate REST results
+    Given the GitHub API server:
+      """
+      get('/comments') {
+        assert :per_page => "2"
+        case params[:page]
+        when nil
+          response.headers["Link"] = %(<https://api.github.com/comments?per_page=2&page=2>; rel="next", <https://api.github.com/comments?per_page=2&page=3>; rel="last")
+          json [{:id => 1}, {:id => 2}]
+        when "2"
+          response.headers["Link"] = %(<https://api.github.com/comments?per_page=2&page=3>; rel="next", <https://api.github.com/comments?per_page=2&page=3>; rel="last")
+          json [{:id => 3}, {:id => 4}]
+        else
+          json [{:id => 5}]
+        end
+      }
+      """
+    When I successfully run `hub api --paginate --jq '.[].id' -XGET -F per_page=2 comments`
+    Then the output should contain exactly:
+      """
+      1
+      2
+      3
+      4
+      5\n
+      """
+
+  Scenario: Paginated request stops at the first failed page
+    Given the GitHub API server:
+      """
+      get('/comments') {
+        case params[:page]
+        when nil
+          response.headers["Link"] = %(<https://api.github.com/comments?page=2>; rel="next", <https://api.github.com/comments?page=3>; rel="last")
+          json [{:id => 1}]
+        when "2"
+          halt 502
+        else
+          json [{:id => 3}]
+        end
+      }
+      """
+    When I run `hub api --paginate --silent --jq '.[].id' comments`
+    Then the exit status should be 22
+    And the stdout should contain exactly "1\n"
+    And the stderr should contain exactly "Error fetching page 2: HTTP 502 Bad Gateway\n"
+
+  Scenario: Paginated request keeps going past a failed page
+    Given the GitHub API server:
+      """
+      get('/comments') {
+        case params[:page]
+        when nil
+          response.headers["Link"] = %(<https://api.github.com/comments?page=2>; rel="next", <https://api.github.com/comments?page=3>; rel="last")
+          json [{:id => 1}]
+        when "2"
+          halt 502
+        else
+          json [{:id => 3}]
+        end
+      }
+      """
+    When I run `hub api --paginate --keep-going --silent --jq '.[].id' comments`
+    Then the exit status should be 22
+    And the stdout should contain exactly "1\n3\n"
+    And the stderr should contain exactly "Error fetching page 2: HTTP 502 Bad Gateway\n"
+
+  Scenario: Slurp paginated arrays into one array
+    Given the GitHub API server:
+      """
+      get('/comments') {
+        case params[:page]
+        when nil
+          response.headers["Link"] = %(<https://api.github.com/comments?page=2>; rel="next")
+          json [{:id => 1}, {:id => 2}]
+        else
+          json [{:id => 3}]
+        end
+      }
+      """
+    When I successfully run `hub api --paginate --slurp comments`
+    Then the output should contain exactly:
+      """
+      [{"id":1},{"id":2},{"id":3}]
+      """
+
+  Scenario: Slurp paginated objects
+    Given the GitHub API server:
+      """
+      get('/search/issues') {
+        case params[:page]
+        when nil
+          response.headers["Link"] = %(<https://api.github.com/search/issues?q=hub&page=2>; rel="next")
+          json :total_count => 2, :items => [{:number => 1}]
+        else
+          json :total_count => 2, :items => [{:number => 2}]
+        end
+      }
+      """
+    When I successfully run `hub api --paginate --slurp --jq '.[].items[].number' -XGET -F q=hub search/issues`
+    Then the output should contain exactly:
+      """
+      1
+      2\n
+      """
+
+  Scenario: Slurp requires paginate
+    When I run `hub api --slurp comments`
+    Then the exit status should be 1
+    And the stderr should contain exactly "Error: --slurp can only be used with --paginate\n"
+
+  Scenario: Stop paginating after a number of pages
+    Given the GitHub API server:
+      """
+      get('/comments') {
+        case params[:page]
+        when nil
+          response.headers["Link"] = %(<https://api.github.com/comments?page=2>; rel="next")
+          json [{:id => 1}, {:id => 2}]
+        when "2"
+          response.headers["Link"] = %(<https://api.github.com/comments?page=3>; rel="next")
+          json [{:id => 3}, {:id => 4}]
+        else
+          json [{:id => 5}]
+        end
+      }
+      """
+    When I successfully run `hub api --paginate --max-pages 2 --jq '.[].id' comments`
+    Then the stdout should contain exactly "1\n2\n3\n4\n"
+    And the stderr should contain exactly "Stopped after reaching --max-pages 2\n"
+
+  Scenario: Stop paginating after a number of items
+    Given the GitHub API server:
+      """
+      get('/comments') {
+        case params[:page]
+        when nil
+          response.headers["Link"] = %(<https://api.github.com/comments?page=2>; rel="next")
+          json [{:id => 1}, {:id => 2}]
+        when "2"
+          response.headers["Link"] = %(<https://api.github.com/comments?page=3>; rel="next")
+          json [{:id => 3}, {:id => 4}]
+        else
+          json [{:id => 5}]
+        end
+      }
+      """
+    When I successfully run `hub api --paginate --max-items 3 --jq '.[].id' comments`
+    Then the stdout should contain exactly "1\n2\n3\n"
+    And the stderr should contain exactly "Stopped after reaching --max-items 3\n"
+
+  Scenario: Slurp a limited number of items
+    Given the GitHub API server:
+      """
+      get('/comments') {
+        case params[:page]
+        when nil
+          response.headers["Link"] = %(<https://api.github.com/comments?page=2>; rel="next")
+          json [{:id => 1}, {:id => 2}]
+        when "2"
+          response.headers["Link"] = %(<https://api.github.com/comments?page=3>; rel="next")
+          json [{:id => 3}, {:id => 4}]
+        else
+          json [{:id => 5}]
+        end
+      }
+      """
+    When I successfully run `hub api --paginate --slurp --max-items 3 comments`
+    Then the output should contain exactly:
+      """
+      [{"id":1},{"id":2},{"id":3}]
+      """
+
+  Scenario: Item limit requires array responses
+    Given the GitHub API server:
+      """
+      get('/search/issues') {
+        json :total_count => 2, :items => [{:number => 1}]
+      }
+      """
+    When I run `hub api --paginate --max-items 1 search/issues`
+    Then the exit status should be 1
+    And the stderr should contain exactly "Error: --max-items requires JSON array responses, but page 1 isn't one\n"
+
+  Scenario: Pagination limits require paginate
+    When I run `hub api --max-pages 2 comments`
+    Then the exit status should be 1
+    And the stderr should contain exactly "Error: --max-pages and --max-items can only be used with --paginate\n"
+
+  Scenario: Paginate only GET requests
+    When I run `hub api --paginate -F name=bug comments`
+    Then the exit status should be 1
+    And the stderr should contain exactly "Error: --paginate can only be used with GET requests\n"
+
   Scenario: Avoid leaking token to a 3rd party
     Given the GitHub API server:
       """
@@ -137,6 +562,37 @@ Feature: hub api
       {"accept":"text/json","foo":"bar"}
       """
 
+  Scenario: Configured request headers
+    Given the "X-Foo" header is configured as "configured" for github.com
+    And the "Accept" header is configured as "application/vnd.github.preview+json" for github.com
+    Given the GitHub API server:
+      """
+      get('/hello/world') {
+        json :accept => request.env['HTTP_ACCEPT'],
+             :foo => request.env['HTTP_X_FOO'],
+             :auth => request.env['HTTP_AUTHORIZATION']
+      }
+      """
+    When I successfully run `hub api hello/world`
+    Then the output should contain exactly:
+      """
+      {"accept":"application/vnd.github.preview+json","foo":"configured","auth":"token OTOKEN"}
+      """
+
+  Scenario: Flag headers override configured headers
+    Given the "X-Foo" header is configured as "configured" for github.com
+    Given the GitHub API server:
+      """
+      get('/hello/world') {
+        json :foo => request.env['HTTP_X_FOO']
+      }
+      """
+    When I successfully run `hub api hello/world -H 'X-Foo: flag'`
+    Then the output should contain exactly:
+      """
+      {"foo":"flag"}
+      """
+
   Scenario: Response headers
     Given the GitHub API server:
       """
@@ -210,6 +666,33 @@ Feature: hub api
       ["one", 2, nil]
       """
 
+  Scenario: Upload a raw file with a content type
+    Given the GitHub API server:
+      """
+      post('/repos/mislav/dotfiles/releases/12/assets', :host_name => 'uploads.github.com') {
+        halt 401 unless request.env['HTTP_AUTHORIZATION'] == 'token OTOKEN'
+        halt 415 unless request.env['CONTENT_TYPE'] == 'application/octet-stream'
+        halt 411 unless request.env['CONTENT_LENGTH'] == '10'
+        assert :name => 'app.bin'
+        status 201
+        json :name => params[:name], :size => request.body.read.bytesize
+      }
+      """
+    Given a file named "app.bin" with:
+      """
+      ZIPDATA123
+      """
+    When I successfully run `hub api --input app.bin --content-type application/octet-stream https://uploads.github.com/repos/mislav/dotfiles/releases/12/assets?name=app.bin`
+    Then the output should contain exactly:
+      """
+      {"name":"app.bin","size":10}
+      """
+
+  Scenario: Reject --content-type without --input
+    When I run `hub api user/repos -f name=dotfiles --content-type text/plain`
+    Then the exit status should be 1
+    And the stderr should contain exactly "Error: --content-type can only be used with --input\n"
+
   Scenario: POST body from stdin
     Given the GitHub API server:
       """
@@ -240,6 +723,67 @@ Feature: hub api
       {"name":"Jet","size":2}
       """
 
+  Scenario: Typed and raw GraphQL variables
+    Given the GitHub API server:
+      """
+      post('/graphql') {
+        json(params[:variables])
+      }
+      """
+    When I successfully run `hub api graphql -F query='query($number: Int!) {}' -F number=12 -f title=12 -F draft=false -f label=true`
+    Then the output should contain exactly:
+      """
+      {"draft":false,"label":"true","number":12,"title":"12"}
+      """
+
+  Scenario: GraphQL query from file
+    Given the GitHub API server:
+      """
+      post('/graphql') {
+        json :query => params[:query], :variables => params[:variables]
+      }
+      """
+    And a file named "viewer.graphql" with:
+      """
+      query($size: Int) { viewer { login } }
+      """
+    When I successfully run `hub api --graphql-file viewer.graphql -F size=2`
+    Then the output should contain exactly:
+      """
+      {"query":"query($size: Int) { viewer { login } }","variables":{"size":2}}
+      """
+
+  Scenario: GraphQL query from standard input
+    Given the GitHub API server:
+      """
+      post('/graphql') {
+        json :query => params[:query]
+      }
+      """
+    When I run `hub api -t --graphql-file -` interactively
+    And I pass in:
+      """
+      query { viewer { login } }
+      """
+    Then the output should contain exactly:
+      """
+      .query	query { viewer { login } }\n\n
+      """
+
+  Scenario: Missing GraphQL query file
+    When I run `hub api --graphql-file missing.graphql`
+    Then the exit status should be 1
+    And the stderr should contain "Error: unable to read GraphQL query from `missing.graphql'"
+
+  Scenario: Empty GraphQL query file
+    Given an empty file named "empty.graphql"
+    When I run `hub api --graphql-file empty.graphql`
+    Then the exit status should be 1
+    And the stderr should contain exactly:
+      """
+      Error: GraphQL query in `empty.graphql' is empty\n
+      """
+
   Scenario: Repo context
     Given I am in "git://github.com/octocat/Hello-World.git" git repo
     Given the GitHub API server:
@@ -365,3 +909,81 @@ Feature: hub api
       .count	1
       .count	2\n
       """
+
+  Scenario: Invalid connect timeout
+    When I run `hub api --connect-timeout soon user`
+    Then the exit status should be 1
+    And the stderr should contain exactly:
+      """
+      Error: invalid connect timeout `soon'; expected a duration such as "5s"\n
+      """
+
+  Scenario: Expect an error status
+    Given the GitHub API server:
+      """
+      get('/repos/mislav/dotfiles') {
+        status 404
+        json :message => 'Not Found'
+      }
+      """
+    When I run `hub api --expect-status 404 repos/mislav/dotfiles`
+    Then the exit status should be 0
+    And the output should contain exactly:
+      """
+      {"message":"Not Found"}
+      """
+
+  Scenario: Unexpected status
+    Given the GitHub API server:
+      """
+      get('/repos/mislav/dotfiles') {
+        json :name => 'dotfiles'
+      }
+      """
+    When I run `hub api --expect-status 201,204 repos/mislav/dotfiles`
+    Then the exit status should be 1
+    And the stdout should contain exactly:
+      """
+      {"name":"dotfiles"}
+      """
+    And the stderr should contain exactly:
+      """
+      Error: expected HTTP status 201 or 204, got 200 OK\n
+      """
+
+  Scenario: Expect a jq expression to be true
+    Given the GitHub API server:
+      """
+      get('/repos/mislav/dotfiles') {
+        json :name => 'dotfiles', :private => false
+      }
+      """
+    When I run `hub api --expect-jq '.name == "dotfiles"' repos/mislav/dotfiles`
+    Then the exit status should be 0
+    When I run `hub api --expect-jq .private repos/mislav/dotfiles`
+    Then the exit status should be 1
+    And the stderr should contain exactly:
+      """
+      Error: expected `.private' to be true, got false\n
+      """
+
+  Scenario: Expect a jq expression to be true for an error response
+    Given the GitHub API server:
+      """
+      get('/repos/mislav/dotfiles') {
+        status 404
+        json :message => 'Not Found'
+      }
+      """
+    When I run `hub api --expect-jq .message repos/mislav/dotfiles`
+    Then the exit status should be 22
+    When I run `hub api --expect-status 404 --expect-jq .message repos/mislav/dotfiles`
+    Then the exit status should be 0
+
+  Scenario: Expectations can't be combined with paginate
+    When I run `hub api --paginate --expect-status 200 repos/mislav/dotfiles/issues`
+    Then the exit status should be 1
+    And the stderr should contain exactly:
+      """
+      Error: --expect-status and --expect-jq can't be used with --paginate\n
+      """
@@ -262,6 +262,78 @@ Feature: OAuth authentication
     And the file "../home/.config/hub" should contain "user: mislav"
     And the file "../home/.config/hub" should contain "oauth_token: OTOKEN"
 
+  Scenario: Credentials from .netrc
+    Given the GitHub API server:
+      """
+      get('/user') {
+        halt 401 unless request.env["HTTP_AUTHORIZATION"] == "token NTOKEN"
+        json :login => 'mislav'
+      }
+      post('/user/repos') {
+        halt 401 unless request.env["HTTP_AUTHORIZATION"] == "token NTOKEN"
+        status 201
+        json :full_name => 'mislav/dotfiles'
+      }
+      """
+    Given a file named "../home/.netrc" with:
+      """
+      machine example.com login someone password SECRET
+      machine api.github.com
+        login mislav
+        password NTOKEN
+      """
+    And I successfully run `git config --global hub.netrc true`
+    When I successfully run `hub create`
+    Then the output should not contain "github.com password"
+    And the output should not contain "github.com username"
+    And the file "../home/.config/hub" should not exist
+
+  Scenario: Credentials from .netrc are ignored unless enabled
+    Given the GitHub API server:
+      """
+      post('/authorizations') {
+        assert_basic_auth 'mislav', 'kitty'
+        status 201
+        json :token => 'OTOKEN'
+      }
+      get('/user') {
+        halt 401 unless request.env["HTTP_AUTHORIZATION"] == "token OTOKEN"
+        json :login => 'mislav'
+      }
+      post('/user/repos') {
+        halt 401 unless request.env["HTTP_AUTHORIZATION"] == "token OTOKEN"
+        status 201
+        json :full_name => 'mislav/dotfiles'
+      }
+      """
+    Given a file named "../home/.netrc" with:
+      """
+      machine api.github.com login mislav password NTOKEN
+      """
+    When I run `hub create` interactively
+    When I type "mislav"
+    And I type "kitty"
+    Then the output should contain "github.com password for mislav (never stored):"
+    And the exit status should be 0
+    And the file "../home/.config/hub" should contain "oauth_token: OTOKEN"
+
+  Scenario: Hand the token to git for HTTPS remotes
+    Given I am in "git://github.com/mislav/dotfiles.git" git repo
+    And I am "mislav" on github.com with OAuth token "OTOKEN"
+    When I successfully run `hub --use-token-for-git push origin master`
+    Then the file "../home/.history" should contain "username=mislav\npassword=OTOKEN\n"
+
+  Scenario: Hand the token from the environment to the host given by --host
+    Given I am in "git://github.com/mislav/dotfiles.git" git repo
+    And $GITHUB_TOKEN is "ETOKEN"
+    When I successfully run `hub --host git.my.org --use-token-for-git push origin master`
+    Then the file "../home/.history" should not contain "ETOKEN"
+
+  Scenario: Don't hand the token to other commands
+    Given I am "mislav" on github.com with OAuth token "OTOKEN"
+    When I successfully run `hub --use-token-for-git -c alias.env='!env' env`
+    Then the output should not contain "OTOKEN"
+
   Scenario: Wrong password
     Given the GitHub API server:
       """
@@ -278,7 +350,7 @@ Feature: OAuth authentication
       Bad credentials
 
       """
-    And the exit status should be 1
+    And the exit status should be 3
     And the file "../home/.config/hub" should not exist
 
   Scenario: Two-factor authentication, create authorization
@@ -400,6 +472,71 @@ Feature: OAuth authentication
     And the file "../home/.config/hub" should contain "oauth_token: OTOKEN"
     And the url for "mislav" should be "git@git.my.org:mislav/dotfiles.git"
 
+  Scenario: Enterprise authentication with a forced scheme
+    Given the GitHub API server:
+      """
+      require 'rack/auth/basic'
+      get('/api/v3/meta', :host_name => 'git.my.org') {
+        halt 500, "unexpected probe"
+      }
+      post('/api/v3/authorizations', :host_name => 'git.my.org') {
+        auth = Rack::Auth::Basic::Request.new(env)
+        halt 401 unless auth.credentials == %w[mislav kitty]
+        status 201
+        json :token => 'OTOKEN', :note_url => 'https://hub.github.com/'
+      }
+      get('/api/v3/user', :host_name => 'git.my.org') {
+        json :login => 'mislav'
+      }
+      post('/api/v3/repos/evilchelu/dotfiles/forks', :host_name => 'git.my.org') {
+        status 202
+        json :name => 'dotfiles', :owner => { :login => 'mislav' }
+      }
+      """
+    And "git.my.org" is a whitelisted Enterprise host
+    And the "origin" remote has url "git@git.my.org:evilchelu/dotfiles.git"
+    And $HUB_VERBOSE is "1"
+    When I run `hub --scheme http fork` interactively
+    And I type "mislav"
+    And I type "kitty"
+    Then the exit status should be 0
+    And the stderr should not contain "/api/v3/meta"
+    And the stderr should contain "> POST http://git.my.org/api/v3/authorizations"
+    And the file "../home/.config/hub" should contain "protocol: http\n"
+
+  Scenario: Enterprise authentication probes the API protocol
+    Given the GitHub API server:
+      """
+      get('/api/v3/meta', :host_name => 'git.my.org') {
+        json :verifiable_password_authentication => true
+      }
+      post('/api/v3/authorizations', :host_name => 'git.my.org') {
+        status 201
+        json :token => 'OTOKEN', :note_url => 'https://hub.github.com/'
+      }
+      get('/api/v3/user', :host_name => 'git.my.org') {
+        json :login => 'mislav'
+      }
+      post('/api/v3/repos/evilchelu/dotfiles/forks', :host_name => 'git.my.org') {
+        status 202
+        json :name => 'dotfiles', :owner => { :login => 'mislav' }
+      }
+      """
+    And "git.my.org" is a whitelisted Enterprise host
+    And the "origin" remote has url "git@git.my.org:evilchelu/dotfiles.git"
+    And $HUB_VERBOSE is "1"
+    When I run `hub fork` interactively
+    And I type "mislav"
+    And I type "kitty"
+    Then the exit status should be 0
+    And the stderr should contain "> GET https://git.my.org/api/v3/meta"
+    And the file "../home/.config/hub" should contain "protocol: https\n"
+
+  Scenario: Invalid scheme
+    When I run `hub --scheme ftp fork`
+    Then the exit status should be 1
+    And the stderr should contain exactly "Error: invalid --scheme `ftp'; expected \"https\" or \"http\"\n"
+
   Scenario: Broken config is missing user.
     Given a file named "../home/.config/hub" with:
       """
@@ -4,7 +4,7 @@ Feature: hub browse
 
   Scenario: No repo
     When I run `hub browse`
-    Then the exit status should be 1
+    Then the exit status should be 2
     Then the output should contain exactly "Usage: hub browse [-uc] [[<USER>/]<REPOSITORY>|--] [<SUBPAGE>]\n"
 
   Scenario: Project with owner
@@ -12,6 +12,12 @@ Feature: hub browse
     Then there should be no output
     And "open https://github.com/mislav/dotfiles" should be run
 
+  Scenario: HUB_BROWSER takes precedence over BROWSER
+    Given $HUB_BROWSER is "open -n"
+    When I successfully run `hub browse mislav/dotfiles`
+    Then there should be no output
+    And "open -n https://github.com/mislav/dotfiles" should be run
+
   Scenario: Project without owner
     Given I am "mislav" on github.com
     When I successfully run `hub browse dotfiles`
@@ -44,6 +50,40 @@ Feature: hub browse
     Then the output should contain exactly "https://github.com/mislav/dotfiles\n"
     But "open https://github.com/mislav/dotfiles" should not be run
 
+  Scenario: Latest release
+    Given I am in "git://github.com/mislav/dotfiles.git" git repo
+    When I successfully run `hub browse --latest`
+    Then "open https://github.com/mislav/dotfiles/releases/latest" should be run
+
+  Scenario: Latest release URL of another project
+    When I successfully run `hub browse -u --latest mislav/dotfiles`
+    Then the output should contain exactly "https://github.com/mislav/dotfiles/releases/latest\n"
+
+  Scenario: Issue by number
+    Given I am in "git://github.com/mislav/dotfiles.git" git repo
+    When I successfully run `hub browse --issue 12`
+    Then "open https://github.com/mislav/dotfiles/issues/12" should be run
+
+  Scenario: Pull request URL of another repository
+    When I successfully run `hub -R github/hub browse -u --pr 2600`
+    Then the output should contain exactly "https://github.com/github/hub/pull/2600\n"
+
+  Scenario: Invalid pull request number
+    Given I am in "git://github.com/mislav/dotfiles.git" git repo
+    When I run `hub browse --pr 0`
+    Then the exit status should be 1
+    And the stderr should contain exactly "Error: invalid pull request number: 0\n"
+
+  Scenario: Latest release keyword
+    Given I am in "git://github.com/mislav/dotfiles.git" git repo
+    When I successfully run `hub browse -- latest`
+    Then "open https://github.com/mislav/dotfiles/releases/latest" should be run
+
+  Scenario: Dump the URL instead of browse
+    When I successfully run `hub browse --dump-url mislav/dotfiles`
+    Then the output should contain exactly "https://github.com/mislav/dotfiles\n"
+    And "open https://github.com/mislav/dotfiles" should not be run
+
   Scenario: Current project
     Given I am in "git://github.com/mislav/dotfiles.git" git repo
     When I successfully run `hub browse`
@@ -147,6 +187,72 @@ Feature: hub browse
     When I successfully run `hub browse -- pulls`
     Then "open https://github.com/mislav/dotfiles/pulls" should be run
 
+  Scenario: Actions subpage
+    Given I am in "git://github.com/mislav/dotfiles.git" git repo
+    When I successfully run `hub browse -- actions`
+    Then "open https://github.com/mislav/dotfiles/actions" should be run
+
+  Scenario: Releases subpage URL
+    Given I am in "git://github.com/mislav/dotfiles.git" git repo
+    When I successfully run `hub browse -u -- releases`
+    Then the output should contain exactly "https://github.com/mislav/dotfiles/releases\n"
+
+  Scenario: Security subpage for explicit project
+    When I successfully run `hub browse mislav/dotfiles security`
+    Then "open https://github.com/mislav/dotfiles/security" should be run
+
+  Scenario: Invalid subpage
+    Given I am in "git://github.com/mislav/dotfiles.git" git repo
+    When I run `hub browse -- "wat ever"`
+    Then the exit status should be 1
+    And the stderr should contain exactly "Error: unknown subpage 'wat ever'\n"
+
+  Scenario: Permalink to the current commit
+    Given I am in "git://github.com/mislav/dotfiles.git" git repo
+    And I am on the "feature" branch with upstream "origin/feature"
+    When I successfully run `hub browse -u --permalink`
+    Then the output should match /\Ahttps:\/\/github\.com\/mislav\/dotfiles\/tree\/[0-9a-f]{40}\n\z/
+
+  Scenario: Permalink to lines of a file
+    Given I am in "git://github.com/mislav/dotfiles.git" git repo
+    And I am on the "feature" branch with upstream "origin/feature"
+    And a file named "lib/my file.rb" with:
+      """
+      puts "hello"
+      """
+    When I cd to "lib"
+    And I successfully run `hub browse -u --permalink -- "my file.rb:12-20"`
+    Then the output should match /\Ahttps:\/\/github\.com\/mislav\/dotfiles\/blob\/[0-9a-f]{40}\/lib\/my%20file\.rb#L12-L20\n\z/
+
+  Scenario: Permalink to a symlinked file
+    Given I am in "git://github.com/mislav/dotfiles.git" git repo
+    And I am on the "feature" branch with upstream "origin/feature"
+    And a file named "lib/real.rb" with:
+      """
+      puts "hello"
+      """
+    And I successfully run `ln -s lib/real.rb link.rb`
+    When I successfully run `hub browse -u --permalink -- link.rb`
+    Then the output should match /\Ahttps:\/\/github\.com\/mislav\/dotfiles\/blob\/[0-9a-f]{40}\/link\.rb\n\z/
+
+  Scenario: Permalink to an unknown file
+    Given I am in "git://github.com/mislav/dotfiles.git" git repo
+    And I am on the "feature" branch
+    When I run `hub browse --permalink -- issues`
+    Then the exit status should be 1
+    And the stderr should contain exactly:
+      """
+      Error: --permalink can only be used with "tree", "commits", or a file in the working tree, not `issues'\n
+      """
+
+  Scenario: Default flags from git config
+    Given I am in "git://github.com/mislav/dotfiles.git" git repo
+    And git "hub.browse.flags" is set to "--url"
+    When I successfully run `hub browse -- issues`
+    Then the output should contain exactly "https://github.com/mislav/dotfiles/issues\n"
+    When I successfully run `hub browse --url=false -- issues`
+    Then "open https://github.com/mislav/dotfiles/issues" should be run
+
   Scenario: Dot Delimited branch
     Given I am in "git://github.com/mislav/dotfiles.git" git repo
     And git "push.default" is set to "upstream"
@@ -217,3 +323,39 @@ Feature: hub browse
     Given I am in "git@github.com:suan/git-sanity.git" git repo
     When I successfully run `hub browse`
     Then "open https://github.com/suan/git-sanity" should be run
+
+  Scenario: Notifications page
+    Given I am in "git://github.com/mislav/dotfiles.git" git repo
+    When I successfully run `hub browse --notifications`
+    Then "open https://github.com/notifications" should be run
+
+  Scenario: Profile of the authenticated user outside of a git repository
+    Given the current dir is not a repo
+    When I successfully run `hub browse -u --profile`
+    Then the output should contain exactly "https://github.com/mislav\n"
+
+  Scenario: Profile of another user on Enterprise
+    Given I am in "git://git.my.org/mislav/dotfiles.git" git repo
+    And I am "mislav" on git.my.org with OAuth token "FITOKEN"
+    And "git.my.org" is a whitelisted Enterprise host
+    When I successfully run `hub browse --profile octocat`
+    Then "open https://git.my.org/octocat" should be run
+
+  Scenario: Open what was created last
+    Given I am in "git://github.com/mislav/dotfiles.git" git repo
+    And the GitHub API server:
+      """
+      post('/repos/mislav/dotfiles/issues') {
+        status 201
+        json :html_url => "https://github.com/mislav/dotfiles/issues/1337"
+      }
+      """
+    And I successfully run `hub issue create -m "Not workie, pls fix"`
+    When I successfully run `hub browse --last`
+    Then "open https://github.com/mislav/dotfiles/issues/1337" should be run
+
+  Scenario: Nothing was created yet
+    Given I am in "git://github.com/mislav/dotfiles.git" git repo
+    When I run `hub browse --last`
+    Then the exit status should be 1
+    And the stderr should contain exactly "Error: nothing has been created from this repository yet\n"
@@ -7,6 +7,22 @@ Feature: hub checkout <PULLREQ-URL>
     When I run `hub checkout master`
     Then "git checkout master" should be run
 
+  Scenario: Unchanged command creating a branch from a start point
+    When I run `hub checkout -b newbranch origin/master`
+    Then "git checkout -b newbranch origin/master" should be run
+
+  Scenario: Unchanged command creating a tracking branch
+    When I run `hub checkout --track origin/feature`
+    Then "git checkout --track origin/feature" should be run
+
+  Scenario: Unchanged command creating an orphan branch
+    When I run `hub checkout --orphan gh-pages master`
+    Then "git checkout --orphan gh-pages master" should be run
+
+  Scenario: Unchanged command with a branch name that looks like a URL
+    When I run `hub checkout -b https://github.com/mojombo/jekyll/pull/77 master`
+    Then "git checkout -b https://github.com/mojombo/jekyll/pull/77 master" should be run
+
   Scenario: Checkout a pull request
     Given the GitHub API server:
       """
@@ -219,7 +235,7 @@ Feature: hub checkout <PULLREQ-URL>
       }
       """
     And the "mislav" remote has url "git://github.com/mislav/jekyll.git"
-    And I am on the "fixes" branch
+    And I am on the "fixes" branch with upstream "mislav/fixes"
     When I successfully run `hub checkout -f https://github.com/mojombo/jekyll/pull/77 -q`
     Then "git fetch mislav +refs/heads/fixes:refs/remotes/mislav/fixes" should be run
     And "git checkout -f fixes -q" should be run
@@ -200,3 +200,90 @@ Feature: hub ci-status
       """
     When I successfully run `hub ci-status the_sha`
     Then the output should contain exactly "success\n"
+
+  Scenario: Watch checks until they complete
+    Given there is a commit named "the_sha"
+    And the GitHub API server:
+      """
+      polls = 0
+      get('/repos/michiels/pencilbox/commits/:sha/status') {
+        json({ :state => "pending", :statuses => [] })
+      }
+      get('/repos/michiels/pencilbox/commits/:sha/check-runs') {
+        polls += 1
+        json({ :check_runs => [
+                 { :status => "completed",
+                   :conclusion => "success",
+                   :name => "check 1",
+                   :html_url => "the://url" },
+                 { :status => polls > 1 ? "completed" : "in_progress",
+                   :conclusion => polls > 1 ? "failure" : "",
+                   :name => "check 2",
+                   :html_url => "the://url" },
+               ]
+        })
+      }
+      """
+    When I run `hub ci-status --watch --interval 1 the_sha`
+    Then the stdout should contain exactly "failure\n"
+    And the stderr should contain exactly:
+      """
+      1 of 2 checks completed
+      2 of 2 checks completed\n
+      """
+    And the exit status should be 1
+
+  Scenario: Stop watching a commit that no checks start for
+    Given there is a commit named "the_sha"
+    And the GitHub API server:
+      """
+      get('/repos/michiels/pencilbox/commits/:sha/status') {
+        json({ :state => "pending", :statuses => [] })
+      }
+      get('/repos/michiels/pencilbox/commits/:sha/check-runs') {
+        json({ :check_runs => [] })
+      }
+      """
+    When I run `hub ci-status --watch --interval 1 the_sha`
+    Then the stdout should contain exactly "no status\n"
+    And the stderr should contain exactly:
+      """
+      waiting for checks to start
+      No checks started after 3 polls\n
+      """
+    And the exit status should be 3
+
+  Scenario: Reject polling intervals below one second
+    Given there is a commit named "the_sha"
+    When I run `hub ci-status --watch --interval 0 the_sha`
+    Then the stderr should contain exactly:
+      """
+      Error: invalid --interval `0'; expected a number of seconds of at least 1\n
+      """
+    And the exit status should be 1
+
+  Scenario: Stop watching checks after a timeout
+    Given there is a commit named "the_sha"
+    And the GitHub API server:
+      """
+      get('/repos/michiels/pencilbox/commits/:sha/status') {
+        json({ :state => "pending",
+               :statuses => [
+                 { :state => "pending",
+                   :context => "travis-ci",
+                   :target_url => "the://url"}
+               ]
+        })
+      }
+      get('/repos/michiels/pencilbox/commits/:sha/check-runs') {
+        json({ :check_runs => [] })
+      }
+      """
+    When I run `hub ci-status --watch --interval 1 --timeout 1 the_sha`
+    Then the stdout should contain exactly "pending\n"
+    And the stderr should contain exactly:
+      """
+      0 of 1 checks completed
+      Timed out after 1s waiting for checks to complete\n
+      """
+    And the exit status should be 2
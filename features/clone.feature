@@ -83,6 +83,32 @@ Feature: hub clone
     Then "git clone --bare git://github.com/rtomayko/ronn.git" should be run
     And there should be no output
 
+  Scenario: Partial clone
+    Given the GitHub API server:
+      """
+      get('/repos/rtomayko/ronn') {
+        json :private => false,
+             :name => 'ronn', :owner => { :login => 'rtomayko' },
+             :permissions => { :push => false }
+      }
+      """
+    When I successfully run `hub clone --filter blob:none rtomayko/ronn`
+    Then it should clone "--filter blob:none git://github.com/rtomayko/ronn.git"
+    And there should be no output
+
+  Scenario: Partial clone with filter attached
+    Given the GitHub API server:
+      """
+      get('/repos/rtomayko/ronn') {
+        json :private => false,
+             :name => 'ronn', :owner => { :login => 'rtomayko' },
+             :permissions => { :push => false }
+      }
+      """
+    When I successfully run `hub clone --filter=tree:0 rtomayko/ronn ronn-trees`
+    Then it should clone "--filter=tree:0 git://github.com/rtomayko/ronn.git ronn-trees"
+    And there should be no output
+
   Scenario: Unchanged public clone
     When I successfully run `hub clone git://github.com/rtomayko/ronn.git`
     Then the git command should be unchanged
@@ -228,6 +254,44 @@ Feature: hub clone
     Then it should clone "git@git.my.org:myorg/myrepo.git"
     And there should be no output
 
+  Scenario: Clone my repo from the host given by --host
+    Given I am "mifi" on git.my.org with OAuth token "FITOKEN"
+    Given the GitHub API server:
+      """
+      get('/api/v3/repos/mifi/dotfiles', :host_name => 'git.my.org') {
+        json :private => false,
+             :name => 'dotfiles', :owner => { :login => 'mifi' },
+             :permissions => { :push => true }
+      }
+      """
+    When I successfully run `hub --host git.my.org clone dotfiles`
+    Then it should clone "git@git.my.org:mifi/dotfiles.git"
+    And there should be no output
+
+  Scenario: Clone my repo with several hosts configured and no terminal
+    Given I am "mifi" on git.my.org with OAuth token "FITOKEN"
+    When I run `hub clone dotfiles`
+    Then the exit status should be 1
+    And the stderr should contain "Error: multiple hosts are configured; choose one with `--host' or GITHUB_HOST:"
+    And the stderr should contain "  github.com"
+    And the stderr should contain "  git.my.org"
+    But it should not clone anything
+
+  Scenario: Clone from an Enterprise host alias
+    Given I am "mifi" on git.my.org with OAuth token "FITOKEN"
+    And I successfully run `git config --global hub.hostAlias.work git.my.org`
+    Given the GitHub API server:
+      """
+      get('/api/v3/repos/myorg/myrepo', :host_name => 'git.my.org') {
+        json :private => true,
+             :name => 'myrepo', :owner => { :login => 'myorg' },
+             :permissions => { :push => false }
+      }
+      """
+    When I successfully run `hub clone work:myorg/myrepo`
+    Then it should clone "git@git.my.org:myorg/myrepo.git"
+    And there should be no output
+
   Scenario: Clone from existing directory is a local clone
     Given a directory named "dotfiles/.git"
     When I successfully run `hub clone dotfiles`
@@ -286,3 +350,36 @@ Feature: hub clone
     When I successfully run `hub clone rtomayko/ronn`
     Then it should clone "git://github.com/RTomayko/ronin.git"
     And there should be no output
+
+  Scenario: Clone a fork and add its parent as upstream
+    Given the GitHub API server:
+      """
+      get('/repos/mislav/dotfiles') {
+        json :private => false,
+             :name => 'dotfiles', :owner => { :login => 'mislav' },
+             :permissions => { :push => true },
+             :parent => {
+               :private => false,
+               :name => 'dotfiles', :owner => { :login => 'evilchelu' },
+             }
+      }
+      """
+    And a git repo in "my-dotfiles"
+    When I successfully run `hub clone --set-upstream dotfiles my-dotfiles`
+    Then it should clone "git@github.com:mislav/dotfiles.git"
+    And "git -C my-dotfiles remote add upstream git://github.com/evilchelu/dotfiles.git" should be run
+    And the output should contain exactly "Added remote `upstream' for evilchelu/dotfiles\n"
+
+  Scenario: Skip adding upstream for a repo that isn't a fork
+    Given the GitHub API server:
+      """
+      get('/repos/mislav/dotfiles') {
+        json :private => false,
+             :name => 'dotfiles', :owner => { :login => 'mislav' },
+             :permissions => { :push => true }
+      }
+      """
+    When I successfully run `hub clone --set-upstream dotfiles`
+    Then it should clone "git@github.com:mislav/dotfiles.git"
+    And "remote add upstream" should not be run
+    And there should be no output
@@ -20,7 +20,7 @@ Feature: hub compare
 
   Scenario: No args, no upstream
     When I run `hub compare`
-    Then the exit status should be 1
+    Then the exit status should be 2
     And the stderr should contain:
       """
       Usage: hub compare [-uc] [<USER>] [[<START>...]<END>]
@@ -31,7 +31,7 @@ Feature: hub compare
     Given the default branch for "origin" is "develop"
     And I am on the "develop" branch with upstream "origin/develop"
     When I run `hub compare`
-    Then the exit status should be 1
+    Then the exit status should be 2
     And the stderr should contain "Usage: hub compare"
 
   Scenario: No args, has upstream branch
@@ -80,14 +80,14 @@ Feature: hub compare
     And git "push.default" is set to "upstream"
     When I run `hub compare -b experimental`
     Then "open https://github.com/mislav/dotfiles/compare/experimental...experimental" should not be run
-    And the exit status should be 1
+    And the exit status should be 2
     And the stderr should contain "Usage: hub compare"
 
   Scenario: Compare base with parameters
     Given I am on the "master" branch with upstream "origin/master"
     When I run `hub compare -b master experimental..master`
     Then "open https://github.com/mislav/dotfiles/compare/experimental...master" should not be run
-    And the exit status should be 1
+    And the exit status should be 2
     And the stderr should contain "Usage: hub compare"
 
   Scenario: Compare 2-dots range for tags
@@ -0,0 +1,69 @@
+Feature: hub __complete
+  Background:
+    Given I am in "git://github.com/github/hub.git" git repo
+    And I am "mislav" on github.com with OAuth token "OTOKEN"
+
+  Scenario: Complete remote names
+    Given the "upstream" remote has url "git://github.com/mislav/hub.git"
+    When I successfully run `hub __complete remotes`
+    Then the output should contain exactly:
+      """
+      origin
+      upstream\n
+      """
+
+  Scenario: Complete local and remote branch names
+    Given the "upstream" remote has url "git://github.com/mislav/hub.git"
+    And I am on the "feature" branch pushed to "origin/feature"
+    And I am on the "topic" branch pushed to "upstream/fixes"
+    When I successfully run `hub __complete branches`
+    Then the output should contain exactly:
+      """
+      feature
+      fixes
+      master
+      topic\n
+      """
+
+  Scenario: Complete pull request numbers
+    Given the GitHub API server:
+      """
+      get('/repos/github/hub/pulls') {
+        assert :state => "open"
+        json [
+          { :number => 102, :title => "Second", :state => "open",
+            :base => { :ref => "master", :label => "github:master" },
+            :head => { :ref => "patch-2", :label => "octocat:patch-2" },
+            :user => { :login => "octocat" },
+          },
+          { :number => 44, :title => "First", :state => "open",
+            :base => { :ref => "master", :label => "github:master" },
+            :head => { :ref => "patch-1", :label => "octocat:patch-1" },
+            :user => { :login => "octocat" },
+          },
+        ]
+      }
+      """
+    When I successfully run `hub __complete prs`
+    Then the output should contain exactly:
+      """
+      102	Second
+      44	First\n
+      """
+
+  Scenario: Don't prompt for credentials when completing pull requests
+    Given I am "mislav" on github.com
+    When I successfully run `hub __complete prs`
+    Then there should be no output
+
+  Scenario: Unknown completion type
+    When I run `hub __complete labels`
+    Then the exit status should be 1
+    And the stderr should contain exactly:
+      """
+      Error: unknown completion type `labels'\n
+      """
+
+  Scenario: Not listed among hub commands
+    When I successfully run `hub help --all`
+    Then the output should not contain "__complete"
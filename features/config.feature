@@ -0,0 +1,49 @@
+Feature: hub config
+
+  Background:
+    Given I am in "git://github.com/mislav/dotfiles.git" git repo
+
+  Scenario: List hub settings with their defaults
+    Given git "hub.protocol" is set to "https"
+    When I successfully run `hub config --hub`
+    Then the output should contain "hub.noHttpsUpgrade=false\n"
+    And the output should contain "hub.prepareMessage=\n"
+    And the output should contain "hub.protocol=https\n"
+
+  Scenario: Leave a bare config command to git
+    When I run `hub config`
+    Then the exit status should be 129
+    And the output should not contain "hub.protocol"
+
+  Scenario: Read a single setting
+    When I successfully run `hub config protocol`
+    Then the output should contain exactly "git\n"
+
+  Scenario: Write a setting
+    When I successfully run `hub config hub.protocol ssh`
+    Then the output should contain exactly ""
+    When I successfully run `git config hub.protocol`
+    Then the output should contain exactly "ssh\n"
+
+  Scenario: Reject an invalid value
+    When I run `hub config protocol ftp`
+    Then the stderr should contain exactly "Error: invalid value `ftp' for hub.protocol; expected one of: https, ssh, git\n"
+    And the exit status should be 1
+
+  Scenario: Write settings of other hub features
+    When I successfully run `hub config hub.postCheckout "npm install"`
+    And I successfully run `hub config hub.prList.flags "--state all"`
+    And I successfully run `hub config --hub`
+    Then the output should contain "hub.postCheckout=npm install\n"
+    And the output should contain "hub.prList.flags=--state all\n"
+
+  Scenario: Pass an unknown hub setting through to git with a warning
+    When I successfully run `hub config hub.nope yes`
+    Then the stderr should contain exactly "Warning: `hub.nope' isn't a known hub setting\n"
+    When I successfully run `git config hub.nope`
+    Then the output should contain exactly "yes\n"
+
+  Scenario: Pass other settings through to git
+    When I successfully run `hub config user.name Mona`
+    And I successfully run `git config user.name`
+    Then the output should contain "Mona\n"
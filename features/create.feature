@@ -16,6 +16,57 @@ Feature: hub create
     Then the url for "origin" should be "git@github.com:mislav/dotfiles.git"
     And the output should contain exactly "https://github.com/mislav/dotfiles\n"
 
+  Scenario: Track the default branch of the new repo
+    Given I successfully run `git symbolic-ref HEAD refs/heads/trunk`
+    Given the GitHub API server:
+      """
+      post('/user/repos') {
+        status 201
+        json :full_name => 'mislav/dotfiles', :default_branch => 'trunk'
+      }
+      """
+    When I successfully run `hub create`
+    Then "trunk" should merge "refs/heads/trunk" from remote "origin"
+
+  Scenario: Don't track an unrelated origin remote
+    Given I successfully run `git symbolic-ref HEAD refs/heads/trunk`
+    And the "origin" remote has url "git://example.com/unrelated.git"
+    Given the GitHub API server:
+      """
+      post('/user/repos') {
+        status 201
+        json :full_name => 'mislav/dotfiles', :default_branch => 'trunk'
+      }
+      """
+    When I successfully run `hub create`
+    Then "git config branch.trunk.remote origin" should not be run
+
+  Scenario: Rename the local branch to the default branch of the new repo
+    Given I successfully run `git symbolic-ref HEAD refs/heads/master`
+    Given the GitHub API server:
+      """
+      post('/user/repos') {
+        status 201
+        json :full_name => 'mislav/dotfiles', :default_branch => 'main'
+      }
+      """
+    When I successfully run `hub create --set-default-branch`
+    Then "git symbolic-ref HEAD refs/heads/main" should be run
+    And "main" should merge "refs/heads/main" from remote "origin"
+
+  Scenario: Refuse to rename a local branch with commits
+    Given I am on the "feature" branch
+    Given the GitHub API server:
+      """
+      post('/user/repos') {
+        status 201
+        json :full_name => 'mislav/dotfiles', :default_branch => 'main'
+      }
+      """
+    When I successfully run `hub create --set-default-branch`
+    Then the stderr should contain exactly "Not renaming branch `feature' to `main' because the repository already has commits\n"
+    And "git symbolic-ref HEAD refs/heads/main" should not be run
+
   Scenario: Create private repo
     Given the GitHub API server:
       """
@@ -99,6 +150,39 @@ Feature: hub create
     When I successfully run `hub create -d mydesc -h http://example.com`
     Then the url for "origin" should be "git@github.com:mislav/dotfiles.git"
 
+  Scenario: Create repo with an initial commit
+    Given the GitHub API server:
+      """
+      post('/user/repos') {
+        assert :auto_init => true,
+               :gitignore_template => 'Go',
+               :license_template => 'mit'
+        status 201
+        json :full_name => 'mislav/dotfiles', :default_branch => 'main'
+      }
+      """
+    When I successfully run `hub create --add-readme --gitignore Go --license mit`
+    Then "git remote add -f origin git@github.com:mislav/dotfiles.git" should be run
+    And the output should contain exactly "https://github.com/mislav/dotfiles\n"
+    And the stderr should contain exactly:
+      """
+      Created README.md, .gitignore, LICENSE in the initial commit of mislav/dotfiles
+      (use `git pull origin main` to get them)\n
+      """
+
+  Scenario: Fetch the initial commit into an existing remote
+    Given the "origin" remote has url "git@github.com:mislav/dotfiles.git"
+    Given the GitHub API server:
+      """
+      post('/user/repos') {
+        assert :auto_init => true
+        status 201
+        json :full_name => 'mislav/dotfiles', :default_branch => 'main'
+      }
+      """
+    When I successfully run `hub create --add-readme`
+    Then "git fetch origin" should be run
+
   Scenario: Not in git repo
     Given the current dir is not a repo
     When I run `hub create`
@@ -266,15 +350,15 @@ Feature: hub create
         json :full_name => 'mislav/dotfiles'
       }
       """
-    And $HUB_VERBOSE is "on"
+    And $HUB_VERBOSE is "3"
     When I successfully run `hub create`
     Then the stderr should contain:
       """
       > GET https://api.github.com/repos/mislav/dotfiles
       > Authorization: token [REDACTED]
       > Accept: application/vnd.github.v3+json;charset=utf-8
-      < HTTP 404
       """
+    And the stderr should contain "< HTTP 404"
     And the stderr should contain:
       """
       > POST https://api.github.com/user/repos
@@ -284,9 +368,36 @@ Feature: hub create
       """
       < HTTP 201
       < Location: http://disney.com
+      """
+    And the stderr should contain:
+      """
       {"full_name":"mislav/dotfiles"}\n
       """
 
+  Scenario: Verbose API output with only request lines
+    Given the GitHub API server:
+      """
+      get('/repos/mislav/dotfiles') { status 404 }
+      post('/user/repos') {
+        status 201
+        json :full_name => 'mislav/dotfiles'
+      }
+      """
+    And $HUB_VERBOSE is "on"
+    When I successfully run `hub create`
+    Then the stderr should contain:
+      """
+      > GET https://api.github.com/repos/mislav/dotfiles
+      < HTTP 404
+      """
+    And the stderr should contain:
+      """
+      > POST https://api.github.com/user/repos
+      < HTTP 201
+      """
+    And the stderr should not contain "Authorization"
+    And the stderr should not contain "full_name"
+
   Scenario: Create Enterprise repo
     Given I am "nsartor" on git.my.org with OAuth token "FITOKEN"
     Given the GitHub API server:
@@ -302,6 +413,20 @@ Feature: hub create
     Then the url for "origin" should be "git@git.my.org:nsartor/dotfiles.git"
     And the output should contain exactly "https://git.my.org/nsartor/dotfiles\n"
 
+  Scenario: Create repo on an Enterprise host alias
+    Given I am "nsartor" on git.my.org with OAuth token "FITOKEN"
+    And git "hub.hostAlias.work" is set to "git.my.org"
+    Given the GitHub API server:
+      """
+      post('/api/v3/orgs/myteam/repos', :host_name => 'git.my.org') {
+        status 201
+        json :full_name => 'myteam/dotfiles'
+      }
+      """
+    When I successfully run `hub create work:myteam/dotfiles`
+    Then the url for "origin" should be "git@git.my.org:myteam/dotfiles.git"
+    And the output should contain exactly "https://git.my.org/myteam/dotfiles\n"
+
   Scenario: Invalid GITHUB_HOST
     Given I am "nsartor" on {} with OAuth token "FITOKEN"
     And $GITHUB_HOST is "{}"
@@ -5,7 +5,7 @@ Feature: hub delete
   Scenario: No argument in current repo
     Given I am in "git://github.com/github/hub.git" git repo
     When I run `hub delete`
-    Then the exit status should be 1
+    Then the exit status should be 2
     And the stderr should contain exactly:
       """
       Usage: hub delete [-y] [<ORGANIZATION>/]<NAME>\n
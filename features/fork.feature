@@ -219,7 +219,7 @@ Feature: hub fork
       """
     And I am "mislav" on github.com with OAuth token "WRONGTOKEN"
     When I run `hub fork`
-    Then the exit status should be 1
+    Then the exit status should be 3
     And the stderr should contain exactly:
       """
       Error creating fork: Unauthorized (HTTP 401)\n
@@ -38,6 +38,83 @@ Feature: hub issue
            #13  Second issue\n
       """
 
+  Scenario: Group issues by label
+    Given the GitHub API server:
+    """
+    get('/repos/github/hub/issues') {
+      json [
+        { :number => 102,
+          :title => "First issue",
+          :state => "open",
+          :user => { :login => "octocat" },
+          :labels => [{ :name => "ui", :color => "ededed" }, { :name => "Bug", :color => "ee0701" }],
+        },
+        { :number => 13,
+          :title => "Second issue",
+          :state => "open",
+          :user => { :login => "octocat" },
+          :labels => [],
+        },
+        { :number => 7,
+          :title => "Third issue",
+          :state => "open",
+          :user => { :login => "octocat" },
+          :labels => [{ :name => "ui", :color => "ededed" }],
+        },
+      ]
+    }
+    """
+    When I successfully run `hub issue --group-by label --format='%i %t%n'`
+    Then the output should contain exactly:
+      """
+      Bug (1)
+      #102 First issue
+
+      ui (2)
+      #102 First issue
+      #7 Third issue
+
+      No label (1)
+      #13 Second issue\n
+      """
+
+  Scenario: Group issues by milestone
+    Given the GitHub API server:
+    """
+    get('/repos/github/hub/issues') {
+      json [
+        { :number => 102,
+          :title => "First issue",
+          :state => "open",
+          :user => { :login => "octocat" },
+          :milestone => { :number => 1, :title => "v2.0" },
+        },
+        { :number => 13,
+          :title => "Second issue",
+          :state => "open",
+          :user => { :login => "octocat" },
+        },
+      ]
+    }
+    """
+    When I successfully run `hub issue --group-by milestone`
+    Then the output should contain exactly:
+      """
+      v2.0 (1)
+          #102  First issue
+
+      No milestone (1)
+           #13  Second issue\n
+      """
+
+  Scenario: Invalid grouping
+    When I run `hub issue --group-by author`
+    Then the exit status should be 1
+    And the stderr should contain exactly:
+      """
+      Error: invalid --group-by `author'; expected "assignee", "label", or "milestone"\n
+      """
+
   Scenario: List limited number of issues
     Given the GitHub API server:
     """
@@ -156,6 +233,40 @@ Feature: hub issue
     """
     When I successfully run `hub issue -d 2016-08-18T09:11:32Z`
 
+  Scenario: Fetch issues closed since a date
+    Given the GitHub API server:
+    """
+    get('/repos/github/hub/issues') {
+      assert :state => "closed",
+             :since => "2016-08-18T09:11:32Z",
+             :labels => "bug"
+      json [
+        { :number => 102,
+          :title => "Closed recently",
+          :state => "closed",
+          :closed_at => "2016-08-20T10:00:00Z",
+          :user => { :login => "octocat" },
+        },
+        { :number => 13,
+          :title => "Closed long ago",
+          :state => "closed",
+          :closed_at => "2015-01-01T10:00:00Z",
+          :user => { :login => "octocat" },
+        },
+      ]
+    }
+    """
+    When I successfully run `hub issue --closed-since 2016-08-18T09:11:32Z -l bug`
+    Then the output should contain exactly:
+      """
+          #102  Closed recently\n
+      """
+
+  Scenario: Closed since can't be combined with open state
+    When I run `hub issue --closed-since 2016-08-18 -s open`
+    Then the exit status should be 1
+    And the stderr should contain exactly "Error: --closed-since can't be combined with --state=open\n"
+
   Scenario: Fetch issues sorted by number of comments ascending
     Given the GitHub API server:
     """
@@ -168,6 +279,64 @@ Feature: hub issue
     """
     When I successfully run `hub issue -o comments -^`
 
+  Scenario: Fetch issues with a minimum number of comments
+    Given the GitHub API server:
+    """
+    get('/repos/github/hub/issues') {
+      assert :sort => "comments"
+
+      json [
+        { :number => 102,
+          :title => "Busy issue",
+          :state => "open",
+          :comments => 12,
+          :user => { :login => "octocat" },
+        },
+        { :number => 13,
+          :title => "Some comments",
+          :state => "open",
+          :comments => 3,
+          :user => { :login => "octocat" },
+        },
+        { :number => 1,
+          :title => "Quiet issue",
+          :state => "open",
+          :comments => 0,
+          :user => { :login => "octocat" },
+        },
+      ]
+    }
+    """
+    When I successfully run `hub issue --sort comments --min-comments 3 -f "%I %cc%n"`
+    Then the output should contain exactly:
+      """
+      102 12
+      13 3\n
+      """
+
+  Scenario: Limit applies to issues matching the minimum number of comments
+    Given the GitHub API server:
+    """
+    get('/repos/github/hub/issues') {
+      json [
+        { :number => 102,
+          :title => "No comments",
+          :state => "open",
+          :comments => 0,
+          :user => { :login => "octocat" },
+        },
+        { :number => 13,
+          :title => "Some comments",
+          :state => "open",
+          :comments => 5,
+          :user => { :login => "octocat" },
+        },
+      ]
+    }
+    """
+    When I successfully run `hub issue --min-comments 1 -L 1 -f "%I%n"`
+    Then the output should contain exactly "13\n"
+
   Scenario: Fetch issues across multiple pages
     Given the GitHub API server:
     """
@@ -221,6 +390,135 @@ Feature: hub issue
            #21  Even more issuez\n
       """
 
+  Scenario: Search issues
+    Given the GitHub API server:
+    """
+    get('/search/issues') {
+      assert :q => "repo:github/hub is:issue is:open label:bug sort:comments",
+             :per_page => "100"
+      json :total_count => 2,
+           :incomplete_results => false,
+           :items => [
+        { :number => 102,
+          :title => "First issue",
+          :state => "open",
+          :user => { :login => "octocat" },
+        },
+        { :number => 13,
+          :title => "Second issue",
+          :state => "open",
+          :user => { :login => "octocat" },
+        },
+      ]
+    }
+    """
+    When I successfully run `hub issue --search "is:open label:bug sort:comments"`
+    Then the output should contain exactly:
+      """
+          #102  First issue
+           #13  Second issue\n
+      """
+
+  Scenario: Search issues ignores other filters
+    Given the GitHub API server:
+    """
+    get('/search/issues') {
+      assert :q => "repo:github/hub is:issue author:octocat",
+             :state => :no,
+             :labels => :no
+      json :total_count => 1,
+           :incomplete_results => false,
+           :items => [
+        { :number => 102,
+          :title => "First issue",
+          :state => "open",
+          :user => { :login => "octocat" },
+        },
+      ]
+    }
+    """
+    When I successfully run `hub issue -s closed -l bug --search author:octocat`
+    Then the stderr should contain exactly:
+      """
+      Warning: ignoring --state, --labels in favor of --search\n
+      """
+    And the stdout should contain exactly:
+      """
+          #102  First issue\n
+      """
+
+  Scenario: Search issues including pull requests across multiple pages
+    Given the GitHub API server:
+    """
+    get('/search/issues') {
+      assert :q => "repo:github/hub author:octocat"
+      if params[:page] == "2"
+        json :total_count => 1500,
+             :incomplete_results => false,
+             :items => [
+          { :number => 13,
+            :title => "Second issue",
+            :state => "open",
+            :user => { :login => "octocat" },
+          },
+        ]
+      else
+        response.headers["Link"] = %(<https://api.github.com/search/issues?q=repo%3Agithub%2Fhub+author%3Aoctocat&per_page=100&page=2>; rel="next")
+        json :total_count => 1500,
+             :incomplete_results => false,
+             :items => [
+          { :number => 102,
+            :title => "First pull",
+            :state => "open",
+            :user => { :login => "octocat" },
+            :pull_request => { },
+          },
+        ]
+      end
+    }
+    """
+    When I successfully run `hub issue --include-pulls --search author:octocat -f "%I%n"`
+    Then the stdout should contain exactly:
+      """
+      102
+      13\n
+      """
+    And the stderr should contain exactly:
+      """
+      Warning: the search matched 1500 issues, but only the first 1000 can be displayed\n
+      """
+
+  Scenario: Idempotent issue creation finds the already opened issue
+    Given the GitHub API server:
+    """
+    posted = false
+    post('/repos/github/hub/issues') {
+      halt 400 if posted
+      posted = true
+      status 503
+      json :message => "Service Unavailable"
+    }
+    get('/repos/github/hub/issues') {
+      assert :creator => "cornwe19",
+             :sort => "created",
+             :direction => "desc"
+      json [
+        { :number => 1337,
+          :title => "Not this one",
+          :created_at => Time.now.utc.strftime("%Y-%m-%dT%H:%M:%SZ"),
+          :html_url => "https://github.com/github/hub/issues/1337",
+        },
+        { :number => 1338,
+          :title => "hello",
+          :created_at => Time.now.utc.strftime("%Y-%m-%dT%H:%M:%SZ"),
+          :html_url => "https://github.com/github/hub/issues/1338",
+        },
+      ]
+    }
+    """
+    When I successfully run `hub issue create -m hello --idempotent`
+    Then the output should contain exactly "https://github.com/github/hub/issues/1338\n"
+
   Scenario: Custom format for issues list
     Given the GitHub API server:
     """
@@ -247,6 +545,28 @@ Feature: hub issue
       13,mislav\n
       """
 
+  Scenario: Stream issues as JSON Lines
+    Given the GitHub API server:
+    """
+    get('/repos/github/hub/issues') {
+      json [
+        { :number => 999,
+          :title => "First pull",
+          :pull_request => { },
+        },
+        { :number => 102,
+          :title => "First issue",
+          :labels => [{ :name => "bug" }],
+        },
+      ]
+    }
+    """
+    When I successfully run `hub issue --jsonl`
+    Then the output should contain exactly:
+      """
+      {"number":102,"title":"First issue","labels":[{"name":"bug"}]}\n
+      """
+
   Scenario: List all assignees
     Given the GitHub API server:
     """
@@ -329,6 +649,53 @@ Feature: hub issue
       https://github.com/github/hub/issues/1337\n
       """
 
+  Scenario: Abort issue creation on an unknown label
+    Given the GitHub API server:
+      """
+      get('/repos/github/hub/labels') {
+        json [
+          { :name => "bug", :color => "fc2929" },
+          { :name => "documentation", :color => "0075ca" },
+        ]
+      }
+      post('/repos/github/hub/issues') {
+        halt 400
+      }
+      """
+    When I run `hub issue create -m "hello" -l bug,documentaton --strict-labels`
+    Then the exit status should be 1
+    And the stderr should contain exactly:
+      """
+      Error: no such label: documentaton (did you mean documentation?)\n
+      """
+
+  Scenario: Check labels before creating an issue
+    Given the GitHub API server:
+      """
+      get('/repos/github/hub/labels') {
+        json [
+          { :name => "Bug", :color => "fc2929" },
+          { :name => "documentation", :color => "0075ca" },
+        ]
+      }
+      post('/repos/github/hub/issues') {
+        assert :title => "hello",
+               :labels => ["Bug"]
+
+        status 201
+        json :html_url => "https://github.com/github/hub/issues/1337"
+      }
+      """
+    When I successfully run `hub issue create -m "hello" -l bug,wontfix --check-labels`
+    Then the output should contain exactly:
+      """
+      https://github.com/github/hub/issues/1337\n
+      """
+    And the stderr should contain exactly:
+      """
+      Warning: no such label: wontfix\n
+      """
+
   Scenario: Create an issue with milestone and assignees
     Given the GitHub API server:
       """
@@ -340,7 +707,8 @@ Feature: hub issue
                :labels => :no
 
         status 201
-        json :html_url => "https://github.com/github/hub/issues/1337"
+        json :html_url => "https://github.com/github/hub/issues/1337",
+             :assignees => [{ :login => "mislav" }, { :login => "josh" }, { :login => "pcorpet" }]
       }
       """
     When I successfully run `hub issue create -m "hello" -M 12 --assign mislav,josh -apcorpet`
@@ -349,6 +717,183 @@ Feature: hub issue
       https://github.com/github/hub/issues/1337\n
       """
 
+  Scenario: Create an issue assigned to myself and a team
+    Given the GitHub API server:
+      """
+      get('/user') {
+        json :login => 'mislav'
+      }
+      get('/orgs/github/teams/hubbers/members') {
+        json [{ :login => "josh" }, { :login => "Mislav" }, { :login => "pcorpet" }]
+      }
+      post('/repos/github/hub/issues') {
+        assert :assignees => ["mislav", "josh", "pcorpet"]
+
+        status 201
+        json :html_url => "https://github.com/github/hub/issues/1337",
+             :assignees => [{ :login => "mislav" }, { :login => "josh" }]
+      }
+      """
+    When I successfully run `hub issue create -m "hello" --assignee @me,github/hubbers -a josh`
+    Then the stdout should contain exactly:
+      """
+      https://github.com/github/hub/issues/1337\n
+      """
+    And the stderr should contain exactly:
+      """
+      Warning: pcorpet could not be assigned; they might not be a collaborator of github/hub\n
+      """
+
+  Scenario: Create an issue and add it to a project
+    Given the GitHub API server:
+      """
+      post('/repos/github/hub/issues') {
+        status 201
+        json :html_url => "https://github.com/github/hub/issues/1337",
+             :node_id => "I_1337"
+      }
+      post('/graphql') {
+        if params[:query].include?("repositoryOwner")
+          assert :variables => { :owner => "github", :number => 5 }
+          json :data => { :repositoryOwner => { :projectV2 => { :id => "PVT_5" } } }
+        else
+          assert :variables => { :projectId => "PVT_5", :contentId => "I_1337" }
+          json :data => { :addProjectV2ItemById => { :item => { :id => "PVTI_1" } } }
+        end
+      }
+      """
+    When I successfully run `hub issue create -m "hello" --project github/5`
+    Then the output should contain exactly:
+      """
+      https://github.com/github/hub/issues/1337\n
+      """
+
+  Scenario: Adding a new issue to a project fails
+    Given the GitHub API server:
+      """
+      post('/repos/github/hub/issues') {
+        status 201
+        json :html_url => "https://github.com/github/hub/issues/1337",
+             :node_id => "I_1337"
+      }
+      post('/graphql') {
+        json :data => { :repositoryOwner => nil },
+             :errors => [{ :message => "Could not resolve to a ProjectV2 with the number 5." }]
+      }
+      """
+    When I successfully run `hub issue create -m "hello" --project github/5`
+    Then the stdout should contain exactly:
+      """
+      https://github.com/github/hub/issues/1337\n
+      """
+    And the stderr should contain exactly:
+      """
+      Warning: could not add issue to project github/5
+      Error performing GraphQL query: Could not resolve to a ProjectV2 with the number 5.\n
+      """
+
+  Scenario: Adding a new issue to a project fails in strict mode
+    Given the GitHub API server:
+      """
+      post('/repos/github/hub/issues') {
+        status 201
+        json :html_url => "https://github.com/github/hub/issues/1337",
+             :node_id => "I_1337"
+      }
+      post('/graphql') {
+        json :data => { :repositoryOwner => nil }
+      }
+      """
+    When I run `hub issue create -m "hello" --project github/5 --strict`
+    Then the exit status should be 1
+    And the stderr should contain exactly:
+      """
+      Error finding project: github/5 does not exist\n
+      """
+
+  Scenario: Create a sub-issue
+    Given the GitHub API server:
+      """
+      post('/repos/github/hub/issues') {
+        status 201
+        json :html_url => "https://github.com/github/hub/issues/1337",
+             :number => 1337, :id => 91337
+      }
+      post('/repos/github/hub/issues/12/sub_issues') {
+        assert :sub_issue_id => 91337
+        status 201
+        json :number => 12
+      }
+      """
+    When I successfully run `hub issue create -m "hello" --parent 12`
+    Then the stdout should contain exactly:
+      """
+      https://github.com/github/hub/issues/1337\n
+      """
+    And the stderr should contain exactly:
+      """
+      Added #1337 as a sub-issue of #12\n
+      """
+
+  Scenario: Create a sub-issue of an issue in another repository
+    Given the GitHub API server:
+      """
+      post('/repos/github/hub/issues') {
+        status 201
+        json :html_url => "https://github.com/github/hub/issues/1337",
+             :number => 1337, :id => 91337
+      }
+      post('/repos/github/roadmap/issues/4/sub_issues') {
+        assert :sub_issue_id => 91337
+        status 201
+        json :number => 4
+      }
+      """
+    When I successfully run `hub issue create -m "hello" --parent github/roadmap#4`
+    Then the stderr should contain exactly:
+      """
+      Added #1337 as a sub-issue of github/roadmap#4\n
+      """
+
+  Scenario: Sub-issues are not supported
+    Given the GitHub API server:
+      """
+      post('/repos/github/hub/issues') {
+        status 201
+        json :html_url => "https://github.com/github/hub/issues/1337",
+             :number => 1337, :id => 91337
+      }
+      post('/repos/github/hub/issues/12/sub_issues') {
+        status 404
+      }
+      """
+    When I successfully run `hub issue create -m "hello" --parent '#12'`
+    Then the stdout should contain exactly:
+      """
+      https://github.com/github/hub/issues/1337\n
+      """
+    And the stderr should contain exactly:
+      """
+      Warning: could not make #1337 a sub-issue of #12; sub-issues might not be supported on github.com
+      Error adding sub-issue: Not Found (HTTP 404)\n
+      """
+
+  Scenario: Create an issue with an invalid parent
+    When I run `hub issue create -m "hello" --parent hub#12`
+    Then the exit status should be 1
+    And the stderr should contain exactly:
+      """
+      Error: invalid parent issue `hub#12'; expected a number or <OWNER>/<REPO>#<NUMBER>\n
+      """
+
+  Scenario: Create an issue with an invalid project
+    When I run `hub issue create -m "hello" --project github`
+    Then the exit status should be 1
+    And the stderr should contain exactly:
+      """
+      Error: invalid project `github'; expected <OWNER>/<NUMBER>\n
+      """
+
   Scenario: Editing empty issue message
     Given the git commit editor is "vim"
     And the text editor adds:
@@ -373,6 +918,71 @@ Feature: hub issue
       https://github.com/github/hub/issues/1337\n
       """
 
+  Scenario: Issue description from the latest commits
+    Given I make a commit with message:
+      """
+      Fix typo in README
+
+      The word was misspelled.
+      """
+    And I make a commit with message "Update copyright year"
+    Given the GitHub API server:
+      """
+      post('/repos/github/hub/issues') {
+        assert :title => "Small fixes",
+               :body => "Fix typo in README\n\nThe word was misspelled.\n\nUpdate copyright year"
+
+        status 201
+        json :html_url => "https://github.com/github/hub/issues/1337"
+      }
+      """
+    When I successfully run `hub issue create -m "Small fixes" --body-from-commits=2`
+    Then the output should contain exactly:
+      """
+      https://github.com/github/hub/issues/1337\n
+      """
+
+  Scenario: Invalid number of commits for the issue description
+    When I run `hub issue create -m "Small fixes" --body-from-commits=none`
+    Then the exit status should be 1
+    And the stderr should contain exactly "Error: invalid --body-from-commits value `none'; expected a positive number\n"
+
+  Scenario: Reopen the draft of an issue that failed to be created
+    Given the GitHub API server:
+      """
+      post('/repos/github/hub/issues') {
+        status 502
+        json :message => "Server Error"
+      }
+      """
+    When I run `hub issue create -m hello -m "my nice issue"`
+    Then the exit status should be 1
+    Given the git commit editor is "true"
+    Given the GitHub API server:
+      """
+      post('/repos/github/hub/issues') {
+        assert :title => "hello",
+               :body => "my nice issue"
+
+        status 201
+        json :html_url => "https://github.com/github/hub/issues/1337"
+      }
+      """
+    When I successfully run `hub issue create --edit-last`
+    Then the output should contain exactly:
+      """
+      https://github.com/github/hub/issues/1337\n
+      """
+    And the file ".git/hub-drafts/ISSUE_EDITMSG" should not exist
+
+  Scenario: No issue draft to reopen
+    When I run `hub issue create --edit-last`
+    Then the exit status should be 1
+    And the stderr should contain exactly:
+      """
+      Aborted: no previous issue draft to edit\n
+      """
+
   Scenario: Issue template
     Given the git commit editor is "vim"
     And the text editor adds:
@@ -594,6 +1204,35 @@ Feature: hub issue
       I did the thing\n
       """
 
+  Scenario: Show issue with labels
+    Given the GitHub API server:
+      """
+      get('/repos/github/hub/issues/102') {
+        json \
+          :number => 102,
+          :state => "closed",
+          :body => "I want this feature",
+          :title => "Feature request for hub issue show",
+          :created_at => "2017-04-14T16:00:49Z",
+          :user => { :login => "royels" },
+          :labels => [{:name => "feature"}, {:name => "help wanted"}],
+          :comments => 0
+      }
+      get('/repos/github/hub/issues/102/comments') {
+        json []
+      }
+      """
+    When I successfully run `hub issue show 102`
+    Then the output should contain exactly:
+      """
+      # [CLOSED] Feature request for hub issue show
+
+      * created by @royels on 2017-04-14 16:00:49 +0000 UTC
+      * labels: feature, help wanted
+
+      I want this feature\n
+      """
+
   Scenario: Format single issue
     Given the GitHub API server:
       """
@@ -651,7 +1290,7 @@ Feature: hub issue
 
   Scenario: Did not supply an issue number
     When I run `hub issue show`
-    Then the exit status should be 1
+    Then the exit status should be 2
     Then the stderr should contain "Usage: hub issue"
 
   Scenario: Show error message if http code is not 200 for issues endpoint
@@ -687,3 +1326,155 @@ Feature: hub issue
       """
       Error fetching comments for issue: Not Found (HTTP 404)\n
       """
+
+  Scenario: React to an issue
+    Given the GitHub API server:
+      """
+      post('/repos/github/hub/issues/12/reactions') {
+        halt 415 unless request.env['HTTP_ACCEPT'] == 'application/vnd.github.squirrel-girl-preview+json'
+        assert :content => "+1"
+        status 201
+        json :id => 1, :content => "+1", :user => { :login => "cornwe19" }
+      }
+      get('/repos/github/hub/issues/12') {
+        halt 415 unless request.env['HTTP_ACCEPT'] == 'application/vnd.github.squirrel-girl-preview+json'
+        json :number => 12,
+          :reactions => { :total_count => 4, :"+1" => 3, :"-1" => 0, :heart => 1 }
+      }
+      """
+    When I successfully run `hub issue react --reaction thumbsup 12`
+    Then the output should contain exactly:
+      """
+      Reacted with +1 to #12
+      Reactions: +1 (3), heart (1)\n
+      """
+
+  Scenario: Remove a reaction from an issue
+    Given the GitHub API server:
+      """
+      get('/user') {
+        json :login => "cornwe19"
+      }
+      get('/repos/github/hub/issues/12/reactions') {
+        assert :content => "heart"
+        json [
+          { :id => 5, :content => "heart", :user => { :login => "octocat" } },
+          { :id => 6, :content => "heart", :user => { :login => "Cornwe19" } },
+        ]
+      }
+      delete('/repos/github/hub/issues/12/reactions/6') {
+        status 204
+      }
+      get('/repos/github/hub/issues/12') {
+        json :number => 12, :reactions => { :total_count => 0 }
+      }
+      """
+    When I successfully run `hub issue react --remove --reaction heart 12`
+    Then the output should contain exactly:
+      """
+      Removed your heart reaction from #12
+      Reactions: none\n
+      """
+
+  Scenario: Invalid reaction
+    When I run `hub issue react --reaction tada 12`
+    Then the exit status should be 1
+    And the stderr should contain exactly:
+      """
+      Error: invalid reaction `tada'; expected one of: +1, -1, laugh, confused, heart, hooray, rocket, eyes\n
+      """
+
+  Scenario: Lock an issue
+    Given the GitHub API server:
+      """
+      put('/repos/github/hub/issues/12/lock') {
+        assert :lock_reason => "too heated"
+        status 204
+      }
+      """
+    When I successfully run `hub issue lock --reason too-heated 12`
+    Then the output should contain exactly "Locked the conversation of #12 as too heated\n"
+
+  Scenario: Lock a pull request without a reason
+    Given the GitHub API server:
+      """
+      put('/repos/github/hub/issues/13/lock') {
+        assert :lock_reason => :no
+        status 204
+      }
+      """
+    When I successfully run `hub issue lock 13`
+    Then the output should contain exactly "Locked the conversation of #13\n"
+
+  Scenario: Unlock an issue
+    Given the GitHub API server:
+      """
+      delete('/repos/github/hub/issues/12/lock') {
+        status 204
+      }
+      """
+    When I successfully run `hub issue unlock 12`
+    Then the output should contain exactly "Unlocked the conversation of #12\n"
+
+  Scenario: Invalid lock reason
+    When I run `hub issue lock --reason rude 12`
+    Then the exit status should be 1
+    And the stderr should contain exactly:
+      """
+      Error: invalid lock reason `rude'; expected one of: off-topic, too-heated, resolved, spam\n
+      """
+
+  Scenario: Edit the title of an issue
+    Given the GitHub API server:
+      """
+      get('/repos/github/hub/issues/12') {
+        json :number => 12, :title => "Old title", :body => "Old body",
+          :html_url => "https://github.com/github/hub/issues/12"
+      }
+      patch('/repos/github/hub/issues/12') {
+        assert :title => "New title", :body => :no
+        json :number => 12
+      }
+      """
+    When I successfully run `hub issue edit 12 --title "New title"`
+    Then the output should contain exactly:
+      """
+      https://github.com/github/hub/issues/12\n
+      """
+
+  Scenario: Edit an issue in a text editor
+    Given the GitHub API server:
+      """
+      get('/repos/github/hub/issues/12') {
+        json :number => 12, :title => "Old title", :body => "Old body",
+          :html_url => "https://github.com/github/hub/issues/12"
+      }
+      patch('/repos/github/hub/issues/12') {
+        assert :title => "New title", :body => "Old title\n\nOld body"
+        json :number => 12
+      }
+      """
+    Given the git commit editor is "vim"
+    And the text editor adds:
+      """
+      New title
+      """
+    When I successfully run `hub issue edit --editor 12`
+    Then the output should contain exactly:
+      """
+      https://github.com/github/hub/issues/12\n
+      """
+
+  Scenario: Abort editing an issue that wouldn't change
+    Given the GitHub API server:
+      """
+      get('/repos/github/hub/issues/12') {
+        json :number => 12, :title => "Old title", :body => "Old body"
+      }
+      """
+    When I run `hub issue edit 12 --title "Old title" --body "Old body"`
+    Then the exit status should be 1
+    And the stderr should contain exactly:
+      """
+      Aborted: issue #12 was left unchanged\n
+      """
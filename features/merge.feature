@@ -70,6 +70,78 @@ Feature: hub merge
     Then "git fetch origin refs/pull/164/head" should be run
     And "git merge --squash --no-edit FETCH_HEAD -m Merge pull request #164 from jfirebaugh/hub_merge" should be run
 
+  Scenario: Merge pull request with a custom message
+    Given the GitHub API server:
+      """
+      get('/repos/defunkt/hub/pulls/164') { json \
+        :base => {
+          :repo => {
+            :owner => { :login => "defunkt" },
+            :name => "hub",
+            :private => false
+          }
+        },
+        :head => {
+          :ref => "hub_merge",
+          :repo => {
+            :owner => { :login => "jfirebaugh" },
+            :name => "hub",
+            :private => false
+          }
+        },
+        :title => "Add `hub merge` command"
+      }
+      """
+    And there is a git FETCH_HEAD
+    When I successfully run `hub merge -m "Merge it" https://github.com/defunkt/hub/pull/164 --no-ff -m "Thanks!"`
+    Then "git fetch origin refs/pull/164/head" should be run
+    When I successfully run `git show -s --format=%B`
+    Then the output should contain:
+      """
+      Merge pull request #164 from jfirebaugh/hub_merge
+
+      Merge it
+
+      Thanks!
+      """
+
+  Scenario: Merge pull request with a message from a file
+    Given the GitHub API server:
+      """
+      get('/repos/defunkt/hub/pulls/164') { json \
+        :base => {
+          :repo => {
+            :owner => { :login => "defunkt" },
+            :name => "hub",
+            :private => false
+          }
+        },
+        :head => {
+          :ref => "hub_merge",
+          :repo => {
+            :owner => { :login => "jfirebaugh" },
+            :name => "hub",
+            :private => false
+          }
+        },
+        :title => "Add `hub merge` command"
+      }
+      """
+    And there is a git FETCH_HEAD
+    Given a file named "merge-message.txt" with:
+      """
+      Adds the merge command.
+      """
+    When I successfully run `hub merge https://github.com/defunkt/hub/pull/164 -F merge-message.txt`
+    Then "git merge FETCH_HEAD --no-ff -m Merge pull request #164 from jfirebaugh/hub_merge" should be run
+    When I successfully run `git show -s --format=%B`
+    Then the output should contain:
+      """
+      Merge pull request #164 from jfirebaugh/hub_merge
+
+      Adds the merge command.
+      """
+
   Scenario: Merge pull request no repo
     Given the GitHub API server:
       """
@@ -30,6 +30,94 @@ Feature: hub pr checkout <PULLREQ-NUMBER>
     And "git checkout fixes" should be run
     And "fixes" should merge "refs/pull/77/head" from remote "origin"
 
+  Scenario: Checkout a pull request and set a commit template
+    Given the GitHub API server:
+      """
+      get('/repos/mojombo/jekyll/pulls/77') {
+        json :number => 77, :head => {
+          :ref => "fixes",
+          :repo => {
+            :owner => { :login => "mislav" },
+            :name => "jekyll",
+            :private => false
+          }
+        }, :base => {
+          :repo => {
+            :name => 'jekyll',
+            :html_url => 'https://github.com/mojombo/jekyll',
+            :owner => { :login => "mojombo" },
+          }
+        },
+        :maintainer_can_modify => false,
+        :html_url => 'https://github.com/mojombo/jekyll/pull/77'
+      }
+      """
+    When I successfully run `hub pr checkout --commit-template 77`
+    Then "git checkout fixes" should be run
+    And the output should contain "Set commit.template to reference mojombo/jekyll#77\n"
+    And the file ".git/hub-commit-template" should contain "Refs: mojombo/jekyll#77"
+    When I successfully run `git config commit.template`
+    Then the output should contain ".git/hub-commit-template"
+
+  Scenario: Run a command after checking out a pull request
+    Given the GitHub API server:
+      """
+      get('/repos/mojombo/jekyll/pulls/77') {
+        json :number => 77, :head => {
+          :ref => "fixes",
+          :repo => {
+            :owner => { :login => "mislav" },
+            :name => "jekyll",
+            :private => false
+          }
+        }, :base => {
+          :ref => "master",
+          :repo => {
+            :name => 'jekyll',
+            :html_url => 'https://github.com/mojombo/jekyll',
+            :owner => { :login => "mojombo" },
+          }
+        },
+        :maintainer_can_modify => false,
+        :html_url => 'https://github.com/mojombo/jekyll/pull/77'
+      }
+      """
+    And I successfully run `git config hub.postCheckout 'echo "$HUB_PR_NUMBER $HUB_PR_HEAD $HUB_PR_BASE $HUB_PR_URL" > checkout.log'`
+    When I successfully run `hub pr checkout 77`
+    Then "git checkout fixes" should be run
+    And the file "checkout.log" should contain "77 fixes master https://github.com/mojombo/jekyll/pull/77"
+
+  Scenario: A failing post-checkout command keeps the checkout
+    Given the GitHub API server:
+      """
+      get('/repos/mojombo/jekyll/pulls/77') {
+        json :number => 77, :head => {
+          :ref => "fixes",
+          :repo => {
+            :owner => { :login => "mislav" },
+            :name => "jekyll",
+            :private => false
+          }
+        }, :base => {
+          :ref => "master",
+          :repo => {
+            :name => 'jekyll',
+            :html_url => 'https://github.com/mojombo/jekyll',
+            :owner => { :login => "mojombo" },
+          }
+        },
+        :maintainer_can_modify => false,
+        :html_url => 'https://github.com/mojombo/jekyll/pull/77'
+      }
+      """
+    And I successfully run `git config hub.postCheckout "exit 3"`
+    When I successfully run `hub pr checkout 77`
+    Then "git checkout fixes" should be run
+    And the stderr should contain exactly:
+      """
+      Warning: the hub.postCheckout command failed: exit status 3\n
+      """
+
   Scenario: Custom name for new branch
     Given the GitHub API server:
       """
@@ -80,3 +168,264 @@ Feature: hub pr checkout <PULLREQ-NUMBER>
     Then "git fetch origin +refs/heads/fixes:refs/remotes/origin/fixes" should be run
     And "git checkout -b fixes --no-track origin/fixes" should be run
     And "fixes" should merge "refs/heads/fixes" from remote "origin"
+
+  Scenario: Existing branch tracking the pull request
+    Given the GitHub API server:
+      """
+      get('/repos/mojombo/jekyll/pulls/77') {
+        json :number => 77, :head => {
+          :ref => "fixes",
+          :repo => {
+            :name => "jekyll",
+            :owner => { :login => "mislav" },
+          }
+        }, :base => {
+          :repo => {
+            :name => "jekyll",
+            :html_url => "https://github.com/mojombo/jekyll",
+            :owner => { :login => "mojombo" },
+          }
+        },
+        :maintainer_can_modify => false,
+        :html_url => 'https://github.com/mojombo/jekyll/pull/77'
+      }
+      """
+    And I am on the "fixes" branch
+    And git "branch.fixes.remote" is set to "origin"
+    And git "branch.fixes.merge" is set to "refs/pull/77/head"
+    When I successfully run `hub pr checkout 77`
+    Then "git fetch origin refs/pull/77/head" should be run
+    And "git checkout fixes" should be run
+    And "git merge --ff-only FETCH_HEAD" should be run
+
+  Scenario: Existing branch unrelated to the pull request
+    Given the GitHub API server:
+      """
+      get('/repos/mojombo/jekyll/pulls/77') {
+        json :number => 77, :head => {
+          :ref => "fixes",
+          :repo => {
+            :name => "jekyll",
+            :owner => { :login => "mislav" },
+          }
+        }, :base => {
+          :repo => {
+            :name => "jekyll",
+            :html_url => "https://github.com/mojombo/jekyll",
+            :owner => { :login => "mojombo" },
+          }
+        },
+        :maintainer_can_modify => false,
+        :html_url => 'https://github.com/mojombo/jekyll/pull/77'
+      }
+      """
+    And I am on the "fixes" branch
+    When I run `hub pr checkout 77`
+    Then the stderr should contain exactly:
+      """
+      Error: branch `fixes' already exists and doesn't track pull request #77
+      (pass a different <BRANCH> name, or use `hub pr checkout --force 77` to reset it)\n
+      """
+    And the exit status should be 1
+    And "git fetch origin refs/pull/77/head:fixes" should not be run
+
+  Scenario: Reset an existing branch to the pull request head
+    Given the GitHub API server:
+      """
+      get('/repos/mojombo/jekyll/pulls/77') {
+        json :number => 77, :head => {
+          :ref => "fixes",
+          :repo => {
+            :name => "jekyll",
+            :owner => { :login => "mislav" },
+          }
+        }, :base => {
+          :repo => {
+            :name => "jekyll",
+            :html_url => "https://github.com/mojombo/jekyll",
+            :owner => { :login => "mojombo" },
+          }
+        },
+        :maintainer_can_modify => false,
+        :html_url => 'https://github.com/mojombo/jekyll/pull/77'
+      }
+      """
+    And I am on the "fixes" branch
+    When I successfully run `hub pr checkout --force 77`
+    Then "git fetch origin refs/pull/77/head" should be run
+    And "git checkout -B fixes FETCH_HEAD" should be run
+    And "fixes" should merge "refs/pull/77/head" from remote "origin"
+
+  Scenario: Track the branch of a pull request from a fork
+    Given the GitHub API server:
+      """
+      get('/repos/mojombo/jekyll/pulls/77') {
+        json :number => 77, :head => {
+          :ref => "fixes",
+          :repo => {
+            :owner => { :login => "mislav" },
+            :name => "jekyll",
+            :private => false
+          }
+        }, :base => {
+          :repo => {
+            :name => 'jekyll',
+            :html_url => 'https://github.com/mojombo/jekyll',
+            :owner => { :login => "mojombo" },
+          }
+        },
+        :maintainer_can_modify => false,
+        :html_url => 'https://github.com/mojombo/jekyll/pull/77'
+      }
+      """
+    When I successfully run `hub pr checkout --track 77`
+    Then "git remote add mislav git://github.com/mislav/jekyll.git" should be run
+    And "git fetch mislav +refs/heads/fixes:refs/remotes/mislav/fixes" should be run
+    And "git checkout -b fixes --no-track mislav/fixes" should be run
+    And "fixes" should merge "refs/heads/fixes" from remote "mislav"
+
+  Scenario: Track a pull request when the fork owner's remote name is taken
+    Given the "mislav" remote has url "git://github.com/mislav/dotfiles.git"
+    Given the GitHub API server:
+      """
+      get('/repos/mojombo/jekyll/pulls/77') {
+        json :number => 77, :head => {
+          :ref => "fixes",
+          :repo => {
+            :owner => { :login => "mislav" },
+            :name => "jekyll",
+            :private => false
+          }
+        }, :base => {
+          :repo => {
+            :name => 'jekyll',
+            :html_url => 'https://github.com/mojombo/jekyll',
+            :owner => { :login => "mojombo" },
+          }
+        },
+        :maintainer_can_modify => false,
+        :html_url => 'https://github.com/mojombo/jekyll/pull/77'
+      }
+      """
+    When I run `hub pr checkout --track 77`
+    Then the exit status should be 1
+    And the stderr should contain exactly "Error: can't track pull request #77: a git remote named `mislav' already exists and points to 'git://github.com/mislav/dotfiles.git'\n"
+
+  Scenario: Detached HEAD
+    Given the GitHub API server:
+      """
+      get('/repos/mojombo/jekyll/pulls/77') {
+        json :number => 77, :head => {
+          :ref => "fixes",
+          :repo => {
+            :name => "jekyll",
+            :owner => { :login => "mislav" },
+          }
+        }, :base => {
+          :repo => {
+            :name => "jekyll",
+            :html_url => "https://github.com/mojombo/jekyll",
+            :owner => { :login => "mojombo" },
+          }
+        },
+        :html_url => 'https://github.com/mojombo/jekyll/pull/77'
+      }
+      """
+    And there is a git FETCH_HEAD
+    When I successfully run `hub pr checkout --detach 77`
+    Then "git fetch origin refs/pull/77/head" should be run
+    And "git checkout --detach FETCH_HEAD" should be run
+    And the output should contain "Checked out pull request #77 at "
+    And the stderr should contain "(use `git checkout -b <BRANCH>` to create a branch from it)"
+
+  Scenario: Checkout a pull request by its head branch
+    Given the GitHub API server:
+      """
+      get('/repos/mojombo/jekyll/pulls') {
+        assert :head => "mislav:fixes",
+               :state => "open"
+        json [
+          { :number => 77, :head => {
+              :ref => "fixes",
+              :repo => {
+                :owner => { :login => "mislav" },
+                :name => "jekyll",
+                :private => false
+              }
+            }, :base => {
+              :repo => {
+                :name => 'jekyll',
+                :html_url => 'https://github.com/mojombo/jekyll',
+                :owner => { :login => "mojombo" },
+              }
+            },
+            :maintainer_can_modify => false,
+            :html_url => 'https://github.com/mojombo/jekyll/pull/77'
+          },
+        ]
+      }
+      """
+    When I successfully run `hub pr checkout mislav:fixes`
+    Then "git fetch origin refs/pull/77/head:fixes" should be run
+    And "git checkout fixes" should be run
+    And "fixes" should merge "refs/pull/77/head" from remote "origin"
+
+  Scenario: No pull request matches the head branch
+    Given the GitHub API server:
+      """
+      get('/repos/mojombo/jekyll/pulls') {
+        json []
+      }
+      """
+    When I run `hub pr checkout mislav:fixes`
+    Then the stderr should contain exactly:
+      """
+      Error: no open pull request found for `mislav:fixes'
+      (check the spelling of <OWNER>:<HEAD>, or use `hub pr list --state all --head mislav:fixes` to find closed ones)\n
+      """
+    And the exit status should be 1
+
+  Scenario: Multiple pull requests match the head branch
+    Given the GitHub API server:
+      """
+      get('/repos/mojombo/jekyll/pulls') {
+        json [
+          { :number => 77 },
+          { :number => 78 },
+        ]
+      }
+      """
+    When I run `hub pr checkout mislav:fixes`
+    Then the stderr should contain exactly:
+      """
+      Error: multiple open pull requests found for `mislav:fixes': #77, #78
+      (pass the pull request number instead)\n
+      """
+    And the exit status should be 1
+
+  Scenario: Recurse into submodules in a repository without submodules
+    Given the GitHub API server:
+      """
+      get('/repos/mojombo/jekyll/pulls/77') {
+        json :number => 77, :head => {
+          :ref => "fixes",
+          :repo => {
+            :owner => { :login => "mislav" },
+            :name => "jekyll",
+            :private => false
+          }
+        }, :base => {
+          :repo => {
+            :name => 'jekyll',
+            :html_url => 'https://github.com/mojombo/jekyll',
+            :owner => { :login => "mojombo" },
+          }
+        },
+        :maintainer_can_modify => false,
+        :html_url => 'https://github.com/mojombo/jekyll/pull/77'
+      }
+      """
+    When I successfully run `hub pr checkout --recurse-submodules 77`
+    Then "git checkout fixes" should be run
+    And "git submodule --quiet update --init --recursive" should not be run
+    And the output should not contain "Updated submodule"
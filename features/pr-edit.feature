@@ -0,0 +1,46 @@
+Feature: hub pr edit
+  Background:
+    Given I am in "git://github.com/github/hub.git" git repo
+    And I am "mislav" on github.com with OAuth token "OTOKEN"
+
+  Scenario: Replace the description of a pull request
+    Given the GitHub API server:
+      """
+      get('/repos/github/hub/issues/42') {
+        json :number => 42, :title => "Fix all the things", :body => "",
+          :pull_request => { :url => "https://api.github.com/repos/github/hub/pulls/42" },
+          :html_url => "https://github.com/github/hub/pull/42"
+      }
+      patch('/repos/github/hub/issues/42') {
+        assert :title => :no, :body => "Fixes #12"
+        json :number => 42
+      }
+      """
+    Given a file named "body.md" with:
+      """
+      Fixes #12
+      """
+    When I successfully run `hub pr edit 42 --body-file body.md`
+    Then the output should contain exactly:
+      """
+      https://github.com/github/hub/pull/42\n
+      """
+
+  Scenario: Edit an issue as a pull request
+    Given the GitHub API server:
+      """
+      get('/repos/github/hub/issues/12') {
+        json :number => 12, :title => "Bug", :body => ""
+      }
+      """
+    When I run `hub pr edit 12 --title "Feature"`
+    Then the exit status should be 1
+    And the stderr should contain exactly:
+      """
+      Error: #12 is an issue, not a pull request\n
+      """
+
+  Scenario: Nothing to edit
+    When I run `hub pr edit 42`
+    Then the exit status should be 2
+    And the stderr should contain "Usage: hub pr list"
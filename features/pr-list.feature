@@ -163,6 +163,29 @@ Feature: hub pr list
       8 \e[31m closed \e[m\n
       """
 
+  Scenario: Stream pull requests as JSON Lines
+    Given the GitHub API server:
+    """
+    get('/repos/github/hub/pulls') {
+      response.headers["Link"] = %(<https://api.github.com/repositories/12345?per_page=100&page=2>; rel="next")
+      json [
+        { :number => 999, :title => "First" },
+      ]
+    }
+    get('/repositories/12345') {
+      assert :page => "2"
+      json [
+        { :number => 7, :title => "Second" },
+      ]
+    }
+    """
+    When I successfully run `hub pr list --jsonl`
+    Then the output should contain exactly:
+      """
+      {"number":999,"title":"First"}
+      {"number":7,"title":"Second"}\n
+      """
+
   Scenario: Sort by number of comments ascending
     Given the GitHub API server:
     """
@@ -201,6 +224,33 @@ Feature: hub pr list
     When I successfully run `hub pr list -h mislav:patch-1`
     Then the output should contain exactly ""
 
+  Scenario: Filter by base and head with state and format
+    Given the GitHub API server:
+    """
+    get('/repos/github/hub/pulls') {
+      assert :base => "develop",
+             :head => "mislav:patch-1",
+             :state => "closed"
+
+      json [
+        { :number => 102,
+          :title => "Fix typo",
+          :state => "closed",
+          :base => { :ref => "develop", :label => "github:develop" },
+          :head => { :ref => "patch-1", :label => "mislav:patch-1" },
+          :user => { :login => "mislav" },
+        },
+      ]
+    }
+    """
+    When I successfully run `hub pr list --base develop --head mislav:patch-1 --state closed --format "%I %B %H%n"`
+    Then the output should contain exactly "102 develop mislav:patch-1\n"
+
+  Scenario: Invalid head filter
+    When I run `hub pr list --head mislav:`
+    Then the exit status should be 1
+    And the stderr should contain exactly "Error: invalid head `mislav:'; expected <BRANCH> or <OWNER>:<BRANCH>\n"
+
   Scenario: Filter by merged state
     Given the GitHub API server:
     """
@@ -241,3 +291,102 @@ Feature: hub pr list
           #999  First
            #13  Third\n
       """
+
+  Scenario: Filter by all of the given labels
+    Given the GitHub API server:
+    """
+    get('/repos/github/hub/pulls') {
+      json [
+        { :number => 999,
+          :title => "First",
+          :state => "open",
+          :labels => [{ :name => "bug" }, { :name => "Needs Review" }],
+          :base => { :ref => "master", :label => "github:master" },
+          :head => { :ref => "patch-1", :label => "octocat:patch-1" },
+          :user => { :login => "octocat" },
+        },
+        { :number => 102,
+          :title => "Second",
+          :state => "open",
+          :labels => [{ :name => "bug" }],
+          :base => { :ref => "master", :label => "github:master" },
+          :head => { :ref => "patch-2", :label => "octocat:patch-2" },
+          :user => { :login => "octocat" },
+        },
+      ]
+    }
+    """
+    When I successfully run `hub pr list -l bug,"needs review" -f "%I %L%n"`
+    Then the output should contain exactly:
+      """
+      999 bug, Needs Review\n
+      """
+
+  Scenario: Filter by any of the given labels
+    Given the GitHub API server:
+    """
+    get('/search/issues') {
+      assert :q => %(repo:github/hub is:pr is:closed base:develop label:"bug","needs review" sort:updated-asc)
+
+      json :total_count => 2,
+           :incomplete_results => false,
+           :items => [
+        { :number => 999,
+          :title => "First",
+          :state => "closed",
+          :labels => [{ :name => "bug" }],
+          :user => { :login => "octocat" },
+          :pull_request => { :merged_at => "2018-12-11T10:50:33Z" },
+        },
+        { :number => 102,
+          :title => "Second",
+          :state => "closed",
+          :labels => [{ :name => "needs review" }],
+          :user => { :login => "octocat" },
+          :pull_request => { :merged_at => nil },
+        },
+      ]
+    }
+    """
+    When I successfully run `hub pr list -s closed -b develop --label-any bug,"needs review" -o updated -^ -f "%I %pS %L%n"`
+    Then the output should contain exactly:
+      """
+      999 merged bug
+      102 closed needs review\n
+      """
+
+  Scenario: List selected fields as JSON
+    Given the GitHub API server:
+    """
+    post('/graphql') {
+      halt 400 unless params[:query].include?("nodes { number title author { login } labels(first: 100) { nodes { name } } headRepositoryOwner { login } }")
+      assert :variables => {
+        :owner => "github", :name => "hub",
+        :states => ["CLOSED", "MERGED"],
+        :headRefName => "patch-1",
+        :orderBy => { :field => "UPDATED_AT", :direction => "ASC" },
+        :first => 2,
+      }
+      json :data => { :repository => { :pullRequests => {
+        :nodes => [
+          { :number => 102, :title => "Fork", :author => { :login => "octocat" },
+            :labels => { :nodes => [] },
+            :headRepositoryOwner => { :login => "octocat" } },
+          { :number => 101, :title => "Mine", :author => nil,
+            :labels => { :nodes => [{ :name => "bug" }] },
+            :headRepositoryOwner => { :login => "github" } },
+        ],
+        :pageInfo => { :hasNextPage => false, :endCursor => "Y3Vyc29y" },
+      } } }
+    }
+    """
+    When I successfully run `hub pr list --json-fields number,title,author,labels -s closed -h patch-1 -o updated -^ -L 2`
+    Then the output should contain exactly:
+      """
+      [{"number":101,"title":"Mine","author":null,"labels":["bug"]}]\n
+      """
+
+  Scenario: Unknown JSON field
+    When I run `hub pr list --json-fields number,headRef`
+    Then the exit status should be 1
+    And the stderr should contain "Error: unknown field `headRef' for --json-fields; expected one of: additions, assignees,"
@@ -0,0 +1,79 @@
+Feature: hub prune-remote
+  Background:
+    Given I am in "dotfiles" git repo
+    And I make a commit
+    And the "origin" remote has url "git://github.com/lostisland/faraday.git"
+    And I am "mislav" on github.com with OAuth token "OTOKEN"
+    And the GitHub API server:
+      """
+      get('/repos/lostisland/faraday') {
+        json :full_name => 'lostisland/faraday', :default_branch => 'master'
+      }
+      """
+    And I am on the "fix-typo" branch pushed to "origin/fix-typo"
+    And I successfully run `git update-ref refs/remotes/origin/release/1.0 fix-typo`
+    And I successfully run `git checkout -q master`
+    And I successfully run `git merge -q --no-ff --no-edit fix-typo`
+    And I successfully run `git update-ref refs/remotes/origin/master master`
+    And I successfully run `git update-ref refs/remotes/origin/fresh master`
+    And I am on the "wip" branch pushed to "origin/wip"
+    And I successfully run `git checkout -q master`
+
+  Scenario: List merged branches without deleting them
+    When I successfully run `hub prune-remote --dry-run`
+    Then "git fetch --prune --quiet origin" should be run
+    And the output should contain exactly:
+      """
+      Would delete 1 merged branch from origin:
+        fix-typo\n
+      """
+    And "git push --delete origin fix-typo" should not be run
+
+  Scenario: Delete merged branches
+    When I successfully run `hub prune-remote --yes`
+    Then the output should contain exactly:
+      """
+      Merged branch on origin:
+        fix-typo
+      Deleted 1 branch from origin.\n
+      """
+    And "git push --delete origin fix-typo" should be run
+
+  Scenario: Configured protected branches
+    Given I successfully run `git config --add hub.protectedBranches "fix-*"`
+    When I successfully run `hub prune-remote --yes`
+    Then the output should contain exactly:
+      """
+      Merged branch on origin:
+        release/1.0
+      Deleted 1 branch from origin.\n
+      """
+    And "git push --delete origin release/1.0" should be run
+
+  Scenario: Keep branches without commits of their own
+    Given I successfully run `git update-ref refs/remotes/origin/older master^`
+    When I successfully run `hub prune-remote --dry-run`
+    Then the output should not contain "fresh"
+    And the output should not contain "older"
+
+  Scenario: Take the default branch from the API
+    Given the GitHub API server:
+      """
+      get('/repos/lostisland/faraday') {
+        json :full_name => 'lostisland/faraday', :default_branch => 'fix-typo'
+      }
+      """
+    When I successfully run `hub prune-remote --dry-run`
+    Then the output should contain exactly "No merged branches to delete from origin.\n"
+
+  Scenario: Nothing to delete
+    Given I successfully run `git update-ref -d refs/remotes/origin/fix-typo`
+    When I successfully run `hub prune-remote`
+    Then the output should contain exactly "No merged branches to delete from origin.\n"
+
+  Scenario: Declined confirmation
+    When I run `hub prune-remote` interactively
+    And I type "no"
+    Then the exit status should be 1
+    And the stderr should contain exactly "Please type 'yes' for confirmation.\n"
+    And "git push --delete origin fix-typo" should not be run
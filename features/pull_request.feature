@@ -51,7 +51,7 @@ Feature: hub pull-request
   Scenario: Invalid flag
     When I run `hub pull-request -yelp`
     Then the stderr should contain "unknown shorthand flag: 'y' in -yelp\n"
-    And the exit status should be 1
+    And the exit status should be 2
 
   Scenario: With Unicode characters in the changelog
     Given the text editor adds:
@@ -102,6 +102,30 @@ Feature: hub pull-request
     When I successfully run `hub pull-request`
     Then the output should contain exactly "the://url\n"
 
+  Scenario: Pull request description from commits
+    Given the GitHub API server:
+      """
+      post('/repos/mislav/coral/pulls') {
+        assert :title => 'Cleanups',
+               :body  => "First cleanup\n\nSecond cleanup\n\nMore details."
+        status 201
+        json :html_url => "the://url"
+      }
+      """
+    Given I am on the "master" branch pushed to "origin/master"
+    When I successfully run `git checkout --quiet -b topic`
+    And I make a commit with message "First cleanup"
+    And I make a commit with message:
+      """
+      Second cleanup
+
+      More details.
+      Signed-off-by: NAME <email@example.com>
+      """
+    And the "topic" branch is pushed to "origin/topic"
+    When I successfully run `hub pull-request -m Cleanups --body-from-commits`
+    Then the output should contain exactly "the://url\n"
+
   Scenario: Single-commit with pull request template
     Given the git commit editor is "true"
     Given the GitHub API server:
@@ -244,6 +268,94 @@ Feature: hub pull-request
       Aborted: no commits detected between origin/master and topic\n
       """
 
+  Scenario: Single-commit pull request with "--fill"
+    Given the GitHub API server:
+      """
+      post('/repos/mislav/coral/pulls') {
+        assert :title => 'Commit title 1',
+               :body => 'Commit body 1'
+        status 201
+        json :html_url => "the://url"
+      }
+      """
+    Given I am on the "master" branch pushed to "origin/master"
+    When I successfully run `git checkout --quiet -b topic`
+    Given I make a commit with message:
+      """
+      Commit title 1
+
+      Commit body 1
+      """
+    And the "topic" branch is pushed to "origin/topic"
+    When I successfully run `hub pull-request --fill`
+    Then the output should contain exactly "the://url\n"
+
+  Scenario: Multiple-commit pull request with "--fill"
+    Given the GitHub API server:
+      """
+      post('/repos/mislav/coral/pulls') {
+        assert :title => 'Fix login form',
+               :body => "- Commit title 1\n- Commit title 2"
+        status 201
+        json :html_url => "the://url"
+      }
+      """
+    Given I am on the "master" branch pushed to "origin/master"
+    When I successfully run `git checkout --quiet -b fix-login_form`
+    Given I make a commit with message:
+      """
+      Commit title 1
+
+      Commit body 1
+      """
+    Given I make a commit with message:
+      """
+      Commit title 2
+      """
+    And the "fix-login_form" branch is pushed to "origin/fix-login_form"
+    When I successfully run `hub pull-request --fill`
+    Then the output should contain exactly "the://url\n"
+
+  Scenario: "--fill" with a message
+    Given I am on the "master" branch pushed to "origin/master"
+    When I successfully run `git checkout --quiet -b topic`
+    And I run `hub pull-request --fill -m hello`
+    Then the exit status should be 1
+    And the stderr should contain exactly:
+      """
+      Error: --fill can't be combined with --message or --file\n
+      """
+
+  Scenario: Editor without the default message
+    Given the text editor adds:
+      """
+      Written from scratch
+      """
+    Given the GitHub API server:
+      """
+      post('/repos/mislav/coral/pulls') {
+        assert :title => 'Written from scratch',
+               :body  => ''
+        status 201
+        json :html_url => "the://url"
+      }
+      """
+    Given I am on the "master" branch pushed to "origin/master"
+    When I successfully run `git checkout --quiet -b topic`
+    And I make a commit with message:
+      """
+      Commit title
+
+      Commit body
+      """
+    And the "topic" branch is pushed to "origin/topic"
+    Given a file named "pull_request_template.md" with:
+      """
+      This is the pull request template
+      """
+    When I successfully run `hub pull-request --no-default-message`
+    Then the output should contain exactly "the://url\n"
+
   Scenario: Message template should include git log summary between base and head
     Given the text editor adds:
       """
@@ -297,7 +409,7 @@ Feature: hub pull-request
       post('/repos/origin/coral/pulls') { 404 }
       """
     When I run `hub pull-request -b origin:master -m here`
-    Then the exit status should be 1
+    Then the exit status should be 4
     Then the stderr should contain:
       """
       Error creating pull request: Not Found (HTTP 404)
@@ -336,6 +448,38 @@ Feature: hub pull-request
     Then the output should contain exactly "https://github.com/mislav/coral/pull/12\n"
     And the file ".git/PULLREQ_EDITMSG" should not exist
 
+  Scenario: Reopen the draft of a pull request that failed to be created
+    Given the text editor adds:
+      """
+      This title comes from vim!
+      """
+    Given the GitHub API server:
+      """
+      post('/repos/mislav/coral/pulls') {
+        status 502
+        json :message => "Server Error"
+      }
+      """
+    When I run `hub pull-request`
+    Then the exit status should be 1
+    And the file ".git/hub-drafts/PULLREQ_EDITMSG" should exist
+    Given the text editor adds:
+      """
+      Updated title
+      """
+    Given the GitHub API server:
+      """
+      post('/repos/mislav/coral/pulls') {
+        assert :title => 'Updated title',
+               :body  => 'This title comes from vim!'
+        status 201
+        json :html_url => "https://github.com/mislav/coral/pull/12"
+      }
+      """
+    When I successfully run `hub pull-request --edit-last`
+    Then the output should contain exactly "https://github.com/mislav/coral/pull/12\n"
+    And the file ".git/hub-drafts/PULLREQ_EDITMSG" should not exist
+
   Scenario: Text editor adds title and body with multiple lines
     Given the text editor adds:
       """
@@ -550,6 +694,110 @@ Feature: hub pull-request
     When I successfully run `hub pull-request -h mojombo:feature -m message`
     Then the output should contain exactly "the://url\n"
 
+  Scenario: Explicit head repository
+    Given I am on the "master" branch
+    Given the GitHub API server:
+      """
+      get('/repos/mojombo/coral-fork') {
+        json :name => "coral-fork", :full_name => "mojombo/coral-fork",
+             :owner => { :login => "mojombo" },
+             :source => { :full_name => "mislav/coral" }
+      }
+      get('/repos/mislav/coral') {
+        json :name => "coral", :full_name => "mislav/coral",
+             :owner => { :login => "mislav" }
+      }
+      post('/repos/mislav/coral/pulls') {
+        assert :head => 'mojombo:feature'
+        status 201
+        json :html_url => "the://url"
+      }
+      """
+    When I successfully run `hub pull-request -h someone:feature --head-repo mojombo/coral-fork -m message`
+    Then the output should contain exactly "the://url\n"
+
+  Scenario: Head repository metadata is cached between runs
+    Given I am on the "master" branch
+    Given the GitHub API server:
+      """
+      $lookups = Hash.new(0)
+      get('/repos/mojombo/coral-fork') {
+        halt 500 if ($lookups[:fork] += 1) == 2
+        json :name => "coral-fork", :full_name => "mojombo/coral-fork",
+             :owner => { :login => "mojombo" },
+             :source => { :full_name => "mislav/coral" }
+      }
+      get('/repos/mislav/coral') {
+        json :name => "coral", :full_name => "mislav/coral",
+             :owner => { :login => "mislav" }
+      }
+      post('/repos/mislav/coral/pulls') {
+        status 201
+        json :html_url => "the://url"
+      }
+      """
+    When I successfully run `hub pull-request -h feature --head-repo mojombo/coral-fork -m message`
+    And I successfully run `hub pull-request -h feature --head-repo mojombo/coral-fork -m message`
+    And I run `hub --no-cache pull-request -h feature --head-repo mojombo/coral-fork -m message`
+    Then the exit status should be 1
+    And the stderr should contain "Error getting repository info: Internal Server Error (HTTP 500)"
+
+  Scenario: Explicit head repository outside of the network
+    Given I am on the "master" branch
+    Given the GitHub API server:
+      """
+      get('/repos/mojombo/other') {
+        json :name => "other", :full_name => "mojombo/other",
+             :owner => { :login => "mojombo" }
+      }
+      get('/repos/mislav/coral') {
+        json :name => "coral", :full_name => "mislav/coral",
+             :owner => { :login => "mislav" }
+      }
+      """
+    When I run `hub pull-request -h feature --head-repo mojombo/other -m message`
+    Then the exit status should be 1
+    And the stderr should contain exactly "Error: mojombo/other is not in the same network as mislav/coral\n"
+
+  Scenario: Invalid head repository
+    Given I am on the "master" branch
+    When I run `hub pull-request -h feature --head-repo mojombo -m message`
+    Then the exit status should be 1
+    And the stderr should contain exactly "Error: invalid head repository `mojombo'; expected <OWNER>/<REPO>\n"
+
+  Scenario: Head and base repositories from remote names
+    Given the "upstream" remote has url "git://github.com/github/coral.git"
+    And the "mojombo" remote has url "git@github.com:mojombo/coral.git"
+    And I am on the "master" branch
+    Given the GitHub API server:
+      """
+      post('/repos/github/coral/pulls') {
+        assert :base => 'develop',
+               :head => 'mojombo:feature'
+        status 201
+        json :html_url => "the://url"
+      }
+      """
+    When I successfully run `hub pull-request --base-remote upstream -b develop --head-remote mojombo -h feature -m message`
+    Then the output should contain exactly "the://url\n"
+
+  Scenario: Base remote conflicts with an owner in base
+    Given the "upstream" remote has url "git://github.com/github/coral.git"
+    When I run `hub pull-request --base-remote upstream -b mislav:develop -m message`
+    Then the exit status should be 1
+    And the stderr should contain exactly "Error: --base-remote can't be combined with an owner in --base\n"
+
+  Scenario: Head remote must point to GitHub
+    Given the "gitlab" remote has url "https://gitlab.com/mojombo/coral.git"
+    When I run `hub pull-request --head-remote gitlab -h feature -m message`
+    Then the exit status should be 1
+    And the stderr should contain exactly "Error: git remote `gitlab' doesn't point to a GitHub repository\n"
+
+  Scenario: Head remote doesn't exist
+    When I run `hub pull-request --head-remote mojombo -h feature -m message`
+    Then the exit status should be 1
+    And the stderr should contain exactly "Error: no git remote named `mojombo'\n"
+
   Scenario: Explicit base
     Given I am on the "feature" branch
     Given the GitHub API server:
@@ -772,6 +1020,39 @@ Feature: hub pull-request
     When I successfully run `hub pull-request -m hereyougo`
     Then the output should contain exactly "the://url\n"
 
+  Scenario: Create pull request from a fork without maintainer edits
+    Given the "origin" remote has url "git://github.com/github/coral.git"
+    And the "doge" remote has url "git://github.com/mislav/coral.git"
+    And I am on the "feature" branch pushed to "doge/feature"
+    Given the GitHub API server:
+      """
+      post('/repos/github/coral/pulls') {
+        assert :base  => 'master',
+               :head  => 'mislav:feature',
+               :title => 'hereyougo',
+               :maintainer_can_modify => false
+        status 201
+        json :html_url => "the://url"
+      }
+      """
+    When I successfully run `hub pull-request -m hereyougo --no-maintainer-edits`
+    Then the output should contain exactly "the://url\n"
+
+  Scenario: Maintainer edits flag within the same repository
+    Given I am on the "feature" branch pushed to "origin/feature"
+    Given the GitHub API server:
+      """
+      post('/repos/mislav/coral/pulls') {
+        assert :head  => 'mislav:feature',
+               :maintainer_can_modify => :no
+        status 201
+        json :html_url => "the://url"
+      }
+      """
+    When I successfully run `hub pull-request -m hereyougo --no-maintainer-edits`
+    Then the stdout should contain exactly "the://url\n"
+    And the stderr should contain exactly "Warning: `--no-maintainer-edits' has no effect when the head and base branches are in the same repository\n"
+
   Scenario: Create pull request from branch on the personal fork, capitalized
     Given the "origin" remote has url "git://github.com/LightAlf/FirstRepo.git"
     And the "Kristinita" remote has url "git@github.com:Kristinita/FirstRepo.git"
@@ -851,6 +1132,155 @@ Feature: hub pull-request
     When I successfully run `hub pull-request -o -m hereyougo`
     Then "open the://url" should be run
 
+  Scenario: Idempotent pull request creation finds the already created pull request
+    Given I am on the "feature" branch pushed to "origin/feature"
+    Given the GitHub API server:
+      """
+      posted = false
+      post('/repos/mislav/coral/pulls') {
+        halt 400 if posted
+        posted = true
+        status 502
+        json :message => "Bad Gateway"
+      }
+      get('/repos/mislav/coral/pulls') {
+        assert :head => "mislav:feature",
+               :base => "master",
+               :state => "open"
+        json [
+          { :number => 12,
+            :title => "hereyougo",
+            :created_at => Time.now.utc.strftime("%Y-%m-%dT%H:%M:%SZ"),
+            :html_url => "the://url",
+          },
+        ]
+      }
+      """
+    When I successfully run `hub pull-request -m hereyougo --idempotent`
+    Then the output should contain exactly "the://url\n"
+
+  Scenario: Idempotent pull request creation retries the request
+    Given I am on the "feature" branch pushed to "origin/feature"
+    Given the GitHub API server:
+      """
+      posted = false
+      post('/repos/mislav/coral/pulls') {
+        if posted
+          status 201
+          json :html_url => "the://url"
+        else
+          posted = true
+          status 502
+          json :message => "Bad Gateway"
+        end
+      }
+      get('/repos/mislav/coral/pulls') {
+        json [
+          { :number => 11,
+            :title => "hereyougo",
+            :created_at => "2018-12-11T10:50:33Z",
+            :html_url => "the://old-url",
+          },
+        ]
+      }
+      """
+    When I successfully run `hub pull-request -m hereyougo --idempotent`
+    Then the output should contain exactly "the://url\n"
+
+  Scenario: Pull request creation isn't retried without the idempotent flag
+    Given I am on the "feature" branch pushed to "origin/feature"
+    Given the GitHub API server:
+      """
+      post('/repos/mislav/coral/pulls') {
+        status 502
+        json :message => "Bad Gateway"
+      }
+      get('/repos/mislav/coral/pulls') {
+        halt 400
+      }
+      """
+    When I run `hub pull-request -m hereyougo`
+    Then the exit status should be 1
+    And the stderr should contain "Error creating pull request: Bad Gateway (HTTP 502)"
+
+  Scenario: Pull request without commits
+    Given I am on the "feature" branch pushed to "origin/feature"
+    Given the GitHub API server:
+      """
+      post('/repos/mislav/coral/pulls') {
+        status 422
+        json :message => "Validation Failed",
+             :errors => [{
+               :resource => "PullRequest",
+               :code => "custom",
+               :message => "No commits between mislav:master and mislav:feature",
+             }]
+      }
+      """
+    When I run `hub pull-request -m hereyougo`
+    Then the exit status should be 1
+    And the stderr should contain exactly:
+      """
+      Error creating pull request: there are no commits between mislav:master and mislav:feature
+      (use `--allow-empty` to push an empty commit to feature and try again)\n
+      """
+
+  Scenario: Pull request without commits pushes an empty commit
+    Given I am on the "feature" branch pushed to "origin/feature"
+    Given the GitHub API server:
+      """
+      posted = false
+      post('/repos/mislav/coral/pulls') {
+        if posted
+          status 201
+          json :html_url => "the://url"
+        else
+          posted = true
+          status 422
+          json :message => "Validation Failed",
+               :errors => [{
+                 :resource => "PullRequest",
+                 :code => "custom",
+                 :message => "No commits between mislav:master and mislav:feature",
+               }]
+        end
+      }
+      """
+    When I successfully run `hub pull-request -m hereyougo --allow-empty`
+    Then the output should contain exactly "the://url\n"
+    And "git commit --allow-empty --quiet -m hereyougo" should be run
+    And "git push --quiet origin HEAD:feature" should be run
+
+  Scenario: Print the URL instead of opening it in web browser
+    Given the GitHub API server:
+      """
+      post('/repos/mislav/coral/pulls') {
+        status 201
+        json :html_url => "the://url"
+      }
+      """
+    When I successfully run `hub pull-request -o --dump-url -m hereyougo`
+    Then the output should contain exactly "the://url\n"
+    And "open the://url" should not be run
+
+  Scenario: Open the prefilled form for a pull request in web browser
+    Given I am on the "feature" branch pushed to "origin/feature"
+    When I successfully run `hub pull-request --web -m "Fix it" -m "Details & more" -r octocat,github/core -a mislav -l bug,ui -M "v2 release"`
+    Then "open https://github.com/mislav/coral/compare/master...feature?assignees=mislav&body=Details+%26+more&expand=1&labels=bug%2Cui&milestone=v2+release&reviewers=octocat%2Cgithub%2Fcore&title=Fix+it" should be run
+    And there should be no output
+
+  Scenario: Print the URL of the form for a pull request from a fork
+    Given the "mislav" remote has url "git@github.com:mislav/coral.git"
+    And the "origin" remote has url "git://github.com/github/coral.git"
+    And I am on the "feature" branch pushed to "mislav/feature"
+    When I successfully run `hub pull-request --web --dump-url`
+    Then the output should contain exactly "https://github.com/github/coral/compare/master...mislav:feature?expand=1\n"
+
+  Scenario: Web form can't convert an issue
+    When I run `hub pull-request --web -i 92`
+    Then the exit status should be 1
+    And the stderr should contain exactly "Error: --web can't be combined with --issue\n"
+
   Scenario: Current branch is tracking local branch
     Given git "push.default" is set to "upstream"
     And I make a commit
@@ -937,6 +1367,74 @@ Feature: hub pull-request
     When I successfully run `hub pull-request -m hereyougo -r mislav,josh -rgithub/robots -rpcorpet -r github/js`
     Then the output should contain exactly "the://url\n"
 
+  Scenario: Request reviews from the code owners of changed files
+    Given I am on the "master" branch pushed to "origin/master"
+    And I successfully run `git checkout --quiet -b feature`
+    Given a file named "CODEOWNERS" with:
+      """
+      # Go code
+      *.go    @gopher @github/go-team
+      /docs/  docs@example.com @josh
+      /vendor/
+      CODEOWNERS @mislav
+      """
+    And an empty file named "README.md"
+    And an empty file named "main.go"
+    And an empty file named "docs/guide.md"
+    And an empty file named "vendor/lib.go"
+    And I successfully run `git add .`
+    And I make a commit with message "Add files"
+    And the "feature" branch is pushed to "origin/feature"
+    Given the GitHub API server:
+      """
+      post('/repos/mislav/coral/pulls') {
+        assert :head  => "mislav:feature"
+        status 201
+        json :html_url => "the://url", :number => 1234
+      }
+      post('/repos/mislav/coral/pulls/1234/requested_reviewers') {
+        assert :reviewers => ["josh", "gopher"]
+        assert :team_reviewers => ["go-team"]
+        status 201
+        json :html_url => "the://url"
+      }
+      """
+    When I successfully run `hub pull-request -m hereyougo -r josh --reviewers-from-codeowners`
+    Then the output should contain exactly "the://url\n"
+    And the stderr should contain exactly "Warning: no code owners found for `README.md'\n"
+
+  Scenario: Request reviews from the code owners of a head branch that only exists on the remote
+    Given I am on the "master" branch pushed to "origin/master"
+    And a file named "CODEOWNERS" with:
+      """
+      *.go    @gopher
+      """
+    And I successfully run `git add CODEOWNERS`
+    And I make a commit with message "Add code owners"
+    And the "master" branch is pushed to "origin/master"
+    And I successfully run `git checkout --quiet -b topic`
+    And an empty file named "main.go"
+    And I successfully run `git add main.go`
+    And I make a commit with message "Add main.go"
+    And the "topic" branch is pushed to "origin/topic"
+    And I successfully run `git checkout --quiet master`
+    And I successfully run `git branch -D topic`
+    Given the GitHub API server:
+      """
+      post('/repos/mislav/coral/pulls') {
+        assert :head  => "mislav:topic"
+        status 201
+        json :html_url => "the://url", :number => 1234
+      }
+      post('/repos/mislav/coral/pulls/1234/requested_reviewers') {
+        assert :reviewers => ["gopher"]
+        status 201
+        json :html_url => "the://url"
+      }
+      """
+    When I successfully run `hub pull-request -m hereyougo -h topic --reviewers-from-codeowners`
+    Then the output should contain exactly "the://url\n"
+
   Scenario: Pull request avoids re-requesting reviewers
     Given I am on the "feature" branch with upstream "origin/feature"
     Given the GitHub API server:
@@ -968,7 +1466,33 @@ Feature: hub pull-request
       }
       """
     When I run `hub pull-request -m hereyougo -r pedrohc`
+    Then the exit status should be 7
+    And the stdout should contain exactly "the://url\n"
+    And the stderr should contain exactly:
+      """
+      Error requesting reviewer: Unprocessable Entity (HTTP 422)
+      Could not add requested reviewers to pull request.
+      Warning: the pull request was created, but some of its properties could not be applied\n
+      """
+
+  Scenario: Requesting reviewers failed in strict mode
+    Given I am on the "feature" branch with upstream "origin/feature"
+    Given the GitHub API server:
+      """
+      post('/repos/mislav/coral/pulls') {
+        status 201
+        json :html_url => "the://url", :number => 1234
+      }
+      post('/repos/mislav/coral/pulls/1234/requested_reviewers') {
+        status 422
+        json :message => "Validation Failed",
+          :errors => ["Could not add requested reviewers to pull request."],
+          :documentation_url => "https://developer.github.com/v3/pulls/review_requests/#create-a-review-request"
+      }
+      """
+    When I run `hub pull-request -m hereyougo -r pedrohc --strict`
     Then the exit status should be 1
+    And the stdout should contain exactly ""
     And the stderr should contain exactly:
       """
       Error requesting reviewer: Unprocessable Entity (HTTP 422)
@@ -1076,6 +1600,133 @@ Feature: hub pull-request
     When I successfully run `hub pull-request -m hereyougo -l feature,release -ldocs`
     Then the output should contain exactly "the://url\n"
 
+  Scenario: Pull request with checked labels
+    Given I am on the "feature" branch with upstream "origin/feature"
+    Given the GitHub API server:
+      """
+      get('/repos/mislav/coral/labels') {
+        json [
+          { :name => "feature", :color => "a2eeef" },
+          { :name => "release", :color => "ededed" },
+        ]
+      }
+      post('/repos/mislav/coral/pulls') {
+        status 201
+        json :html_url => "the://url", :number => 1234
+      }
+      patch('/repos/mislav/coral/issues/1234') {
+        assert :labels => ["feature"], :assignees => :no
+        json :html_url => "the://url"
+      }
+      """
+    When I successfully run `hub pull-request -m hereyougo -l feature,relase --check-labels`
+    Then the output should contain exactly "the://url\n"
+    And the stderr should contain exactly:
+      """
+      Warning: no such label: relase (did you mean release?)\n
+      """
+
+  Scenario: Pull request copying labels and milestone from an issue
+    Given I am on the "feature" branch with upstream "origin/feature"
+    Given the GitHub API server:
+      """
+      post('/repos/mislav/coral/pulls') {
+        status 201
+        json :html_url => "the://url", :number => 1234
+      }
+      get('/repos/mislav/coral/issues/12') {
+        json :number => 12,
+          :labels => [{ :name => "Bug" }, { :name => "backport" }],
+          :milestone => { :number => 3, :title => "v1.1" }
+      }
+      patch('/repos/mislav/coral/issues/1234') {
+        assert :labels => ["bug", "docs", "backport"], :milestone => 3
+        json :html_url => "the://url"
+      }
+      """
+    When I successfully run `hub pull-request -m hereyougo -l bug,docs --copy-labels-from 12 --copy-milestone-from '#12'`
+    Then the output should contain exactly "the://url\n"
+
+  Scenario: Pull request copying labels and milestone from another repository
+    Given I am on the "feature" branch with upstream "origin/feature"
+    Given the GitHub API server:
+      """
+      post('/repos/mislav/coral/pulls') {
+        status 201
+        json :html_url => "the://url", :number => 1234
+      }
+      get('/repos/octocat/coral-v1/issues/45') {
+        json :number => 45,
+          :labels => [{ :name => "bug" }, { :name => "needs-backport" }],
+          :milestone => { :number => 7, :title => "v2.0" }
+      }
+      get('/repos/mislav/coral/labels') {
+        json [{ :name => "Bug", :color => "ee0701" }]
+      }
+      get('/repos/mislav/coral/milestones') {
+        json [{ :number => 2, :title => "V2.0" }]
+      }
+      patch('/repos/mislav/coral/issues/1234') {
+        assert :labels => ["Bug"], :milestone => 2
+        json :html_url => "the://url"
+      }
+      """
+    When I successfully run `hub pull-request -m hereyougo --copy-labels-from octocat/coral-v1#45 --copy-milestone-from octocat/coral-v1#45`
+    Then the output should contain exactly "the://url\n"
+    And the stderr should contain exactly:
+      """
+      Warning: no such label: needs-backport\n
+      """
+
+  Scenario: Pull request with an invalid issue to copy labels from
+    Given I am on the "feature" branch with upstream "origin/feature"
+    When I run `hub pull-request -m hereyougo --copy-labels-from octocat#45`
+    Then the exit status should be 1
+    And the stderr should contain exactly:
+      """
+      Error: invalid issue reference `octocat#45'; expected a number or <OWNER>/<REPO>#<NUMBER>\n
+      """
+
+  Scenario: Applying labels without write access
+    Given I am on the "feature" branch with upstream "origin/feature"
+    Given the GitHub API server:
+      """
+      post('/repos/mislav/coral/pulls') {
+        status 201
+        json :html_url => "the://url", :number => 1234
+      }
+      patch('/repos/mislav/coral/issues/1234') {
+        status 403
+        json :message => "Must have push access to repository"
+      }
+      """
+    When I run `hub pull-request -m hereyougo -l feature`
+    Then the exit status should be 7
+    And the stdout should contain exactly "the://url\n"
+    And the stderr should contain exactly:
+      """
+      Error updating issue: Forbidden (HTTP 403)
+      Must have push access to repository
+      Warning: the pull request was created, but some of its properties could not be applied\n
+      """
+
+  Scenario: Pull request referencing cherry-picked changes
+    Given I am on the "feature" branch with upstream "origin/feature"
+    Given the GitHub API server:
+      """
+      get('/repos/mislav/coral/pulls/12') {
+        json :number => 12, :title => "Fix the flux capacitor"
+      }
+      post('/repos/mislav/coral/pulls') {
+        assert :title => "Backport fix",
+               :body => "Into the stable branch.\n\n<!-- hub:references -->\n- Cherry-picked from #12: Fix the flux capacitor\n- Cherry-picked from commit abc1234\n<!-- /hub:references -->"
+        status 201
+        json :html_url => "the://url"
+      }
+      """
+    When I successfully run `hub pull-request -m "Backport fix" -m "Into the stable branch." --references 12 --references abc1234`
+    Then the output should contain exactly "the://url\n"
+
   Scenario: Pull request to a fetch-only upstream
     Given the "upstream" remote has url "git://github.com/github/coral.git"
     And the "upstream" remote has push url "no_push"
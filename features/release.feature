@@ -215,6 +215,36 @@ Feature: hub release
       will_paginate 1.0.2 ()\n
       """
 
+  Scenario: List releases with asset information
+    Given the GitHub API server:
+      """
+      get('/repos/mislav/will_paginate/releases') {
+        json [
+          { tag_name: 'v1.2.0',
+            name: 'will_paginate 1.2.0',
+            draft: true,
+            prerelease: false,
+            assets: [
+              { name: 'will_paginate.tgz', size: 4213, browser_download_url: 'the://url' },
+              { name: 'checksums.txt', size: 98, browser_download_url: 'the://url2' },
+            ],
+          },
+          { tag_name: 'v1.2.0-pre',
+            name: 'will_paginate 1.2.0-pre',
+            draft: false,
+            prerelease: true,
+            assets: [],
+          },
+        ]
+      }
+      """
+    When I successfully run `hub release --include-drafts --format='%T %d %P [%an] [%aS]%n'`
+    Then the output should contain exactly:
+      """
+      v1.2.0 true false [will_paginate.tgz,checksums.txt] [will_paginate.tgz (4.2 kB), checksums.txt (98 B)]
+      v1.2.0-pre false true [] []\n
+      """
+
   Scenario: Repository not found when listing releases
     Given the GitHub API server:
       """
@@ -230,7 +260,7 @@ Feature: hub release
       Error fetching releases: Not Found (HTTP 404)
       Not Found\n
       """
-    And the exit status should be 1
+    And the exit status should be 4
 
   Scenario: Server error when listing releases
     Given the GitHub API server:
@@ -387,6 +417,65 @@ MARKDOWN
       https://github.com/mislav/will_paginate/releases/v1.2.0\n
       """
 
+  Scenario: Create a release with generated notes
+    Given the GitHub API server:
+      """
+      post('/repos/mislav/will_paginate/releases') {
+        assert :tag_name => "v1.2.0",
+               :name => :no,
+               :body => "",
+               :generate_release_notes => true
+
+        status 201
+        json :body => "## What's Changed\n* Fix pagination",
+             :html_url => "https://github.com/mislav/will_paginate/releases/v1.2.0"
+      }
+      """
+    When I successfully run `hub release create --generate-notes v1.2.0`
+    Then the output should contain exactly:
+      """
+      https://github.com/mislav/will_paginate/releases/v1.2.0\n
+      """
+
+  Scenario: Name a release with generated notes
+    Given the GitHub API server:
+      """
+      post('/repos/mislav/will_paginate/releases') {
+        assert :tag_name => "v1.2.0",
+               :name => "Pagination fixes",
+               :body => "",
+               :generate_release_notes => true
+
+        status 201
+        json :body => "## What's Changed\n* Fix pagination",
+             :html_url => "https://github.com/mislav/will_paginate/releases/v1.2.0"
+      }
+      """
+    When I successfully run `hub release create --name "Pagination fixes" --generate-notes v1.2.0`
+    Then the output should contain exactly:
+      """
+      https://github.com/mislav/will_paginate/releases/v1.2.0\n
+      """
+
+  Scenario: Create a release with a message and generated notes
+    Given the GitHub API server:
+      """
+      post('/repos/mislav/will_paginate/releases') {
+        assert :name => "Instant Gratification Monkey",
+               :body => "Highlights first.",
+               :generate_release_notes => true
+
+        status 201
+        json :body => "Highlights first.\n\n## What's Changed\n* Fix pagination",
+             :html_url => "https://github.com/mislav/will_paginate/releases/v1.2.0"
+      }
+      """
+    When I successfully run `hub release create -m "Instant Gratification Monkey" -m "Highlights first." --generate-notes v1.2.0`
+    Then the output should contain exactly:
+      """
+      https://github.com/mislav/will_paginate/releases/v1.2.0\n
+      """
+
   Scenario: Create a release with target commitish
     Given the GitHub API server:
       """
@@ -404,6 +493,69 @@ MARKDOWN
       https://github.com/mislav/will_paginate/releases/v1.2.0\n
       """
 
+  Scenario: Create a release with target commitish for a tag that points elsewhere
+    Given the GitHub API server:
+      """
+      get('/repos/mislav/will_paginate/git/ref/tags/v1.2.0') {
+        json :ref => "refs/tags/v1.2.0",
+             :object => { :type => "tag", :sha => "7777777777777777777777777777777777777777" }
+      }
+      get('/repos/mislav/will_paginate/git/tags/7777777777777777777777777777777777777777') {
+        json :object => { :type => "commit", :sha => "1111111111111111111111111111111111111111" }
+      }
+      get('/repos/mislav/will_paginate/commits/my-branch') {
+        json :sha => "2222222222222222222222222222222222222222"
+      }
+      """
+    When I run `hub release create -m hello v1.2.0 -t my-branch`
+    Then the exit status should be 1
+    And the stderr should contain exactly:
+      """
+      Error: tag `v1.2.0' already exists on GitHub at 1111111, not at 2222222
+      (use `--overwrite-tag` to move the tag to my-branch)\n
+      """
+
+  Scenario: Overwrite a tag that points elsewhere
+    Given the GitHub API server:
+      """
+      get('/repos/mislav/will_paginate/git/ref/tags/v1.2.0') {
+        json :ref => "refs/tags/v1.2.0",
+             :object => { :type => "commit", :sha => "1111111111111111111111111111111111111111" }
+      }
+      get('/repos/mislav/will_paginate/commits/my-branch') {
+        json :sha => "2222222222222222222222222222222222222222"
+      }
+      patch('/repos/mislav/will_paginate/git/refs/tags/v1.2.0') {
+        assert :sha => "2222222222222222222222222222222222222222",
+               :force => true
+        json :ref => "refs/tags/v1.2.0"
+      }
+      post('/repos/mislav/will_paginate/releases') {
+        assert :tag_name => "v1.2.0",
+               :target_commitish => "my-branch"
+
+        status 201
+        json :html_url => "https://github.com/mislav/will_paginate/releases/v1.2.0"
+      }
+      """
+    When I successfully run `hub release create -m hello v1.2.0 -t my-branch --overwrite-tag`
+    Then the output should contain exactly:
+      """
+      https://github.com/mislav/will_paginate/releases/v1.2.0\n
+      """
+
+  Scenario: Create a release with target commitish for a local tag that points elsewhere
+    Given there is a commit named "v1.2.0"
+    And the GitHub API server:
+      """
+      get('/repos/mislav/will_paginate/commits/master') {
+        json :sha => "2222222222222222222222222222222222222222"
+      }
+      """
+    When I run `hub release create -m hello v1.2.0 -t master`
+    Then the exit status should be 1
+    And the stderr should contain "Error: tag `v1.2.0' already exists locally at "
+
   Scenario: Create a release with assets
     Given the GitHub API server:
       """
@@ -631,8 +783,98 @@ MARKDOWN
         status 204
       }
       """
-    When I successfully run `hub release delete v1.2.0`
-    Then there should be no output
+    When I successfully run `hub release delete --yes v1.2.0`
+    Then the output should contain exactly "Deleted release `v1.2.0'\n"
+
+  Scenario: Refuse to delete a release without confirmation
+    Given the GitHub API server:
+      """
+      get('/repos/mislav/will_paginate/releases') {
+          json [
+            { url: 'https://api.github.com/repos/mislav/will_paginate/releases/123',
+              tag_name: 'v1.2.0',
+            },
+          ]
+      }
+      """
+    When I run `hub release delete v1.2.0`
+    Then the exit status should be 1
+    And the stderr should contain exactly:
+      """
+      Aborted: pass --yes to delete release `v1.2.0' when not running interactively\n
+      """
+
+  Scenario: Delete a release and its tag
+    Given the GitHub API server:
+      """
+      get('/repos/mislav/will_paginate/releases') {
+          json [
+            { url: 'https://api.github.com/repos/mislav/will_paginate/releases/123',
+              tag_name: 'v1.2.0',
+            },
+          ]
+      }
+
+      delete('/repos/mislav/will_paginate/releases/123') {
+        status 204
+      }
+
+      delete('/repos/mislav/will_paginate/git/refs/tags/v1.2.0') {
+        status 204
+      }
+      """
+    And I make a commit
+    And I successfully run `git tag v1.2.0`
+    When I successfully run `hub release delete --yes --delete-tag v1.2.0`
+    Then the output should contain exactly:
+      """
+      Deleted release `v1.2.0'
+      Deleted tag `v1.2.0' from mislav/will_paginate
+      Deleted local tag `v1.2.0'\n
+      """
+    When I run `git rev-parse -q --verify refs/tags/v1.2.0`
+    Then the exit status should be 1
+
+  Scenario: Delete the latest release
+    Given the GitHub API server:
+      """
+      get('/repos/mislav/will_paginate/releases/latest') {
+        json url: 'https://api.github.com/repos/mislav/will_paginate/releases/123',
+             tag_name: 'v1.2.0'
+      }
+
+      delete('/repos/mislav/will_paginate/releases/123') {
+        status 204
+      }
+      """
+    When I successfully run `hub release delete --yes latest`
+    Then the output should contain exactly "Deleted release `v1.2.0'\n"
+
+  Scenario: Show the latest release
+    Given the GitHub API server:
+      """
+      get('/repos/mislav/will_paginate/releases/latest') {
+        json tag_name: 'v1.2.0',
+             name: 'will_paginate 1.2.0'
+      }
+      """
+    When I successfully run `hub release show latest -f "%T %t%n"`
+    Then the output should contain exactly "v1.2.0 will_paginate 1.2.0\n"
+
+  Scenario: No published release to resolve latest to
+    Given the GitHub API server:
+      """
+      get('/repos/mislav/will_paginate/releases/latest') {
+        status 404
+        json :message => "Not Found"
+      }
+      """
+    When I run `hub release edit latest -m ""`
+    Then the exit status should be 1
+    And the stderr should contain exactly:
+      """
+      Error: mislav/will_paginate has no published releases\n
+      """
 
   Scenario: Release not found
     Given the GitHub API server:
@@ -678,3 +920,25 @@ MARKDOWN
       """
       v1.2.0\n
       """
+
+  Scenario: Enterprise host ignores generated notes
+    Given the "origin" remote has url "git@git.my.org:mislav/will_paginate.git"
+    And I am "mislav" on git.my.org with OAuth token "FITOKEN"
+    And "git.my.org" is a whitelisted Enterprise host
+    Given the GitHub API server:
+      """
+      post('/api/v3/repos/mislav/will_paginate/releases', :host_name => 'git.my.org') {
+        status 201
+        json :body => "",
+             :html_url => "https://git.my.org/mislav/will_paginate/releases/v1.2.0"
+      }
+      """
+    When I successfully run `hub release create --generate-notes v1.2.0`
+    Then the stdout should contain exactly:
+      """
+      https://git.my.org/mislav/will_paginate/releases/v1.2.0\n
+      """
+    And the stderr should contain exactly:
+      """
+      Warning: git.my.org did not generate release notes; this requires GitHub Enterprise Server 3.4 or later\n
+      """
@@ -153,6 +153,35 @@ Feature: hub remote add
     Then the url for "mislav" should be "https://github.com/mislav/dotfiles.git"
     And there should be no output
 
+  Scenario: Keep protocol of existing remotes
+    Given the GitHub API server:
+      """
+      get('/repos/mislav/dotfiles') {
+        json :private => false,
+             :name => 'dotfiles', :owner => { :login => 'mislav' },
+             :permissions => { :push => false }
+      }
+      """
+    Given HTTPS is preferred
+    And the "origin" remote has url "git://github.com/EvilChelu/dotfiles.git"
+    When I successfully run `hub --no-https-upgrade remote add mislav`
+    Then the url for "mislav" should be "git://github.com/mislav/dotfiles.git"
+    And there should be no output
+
+  Scenario: Keep plain HTTP of existing remotes for the API
+    Given the GitHub API server:
+      """
+      get('/api/v3/user', :host_name => 'git.my.org') {
+        json :login => 'mislav'
+      }
+      """
+    And "git.my.org" is a whitelisted Enterprise host
+    And the "origin" remote has url "http://git.my.org/evilchelu/dotfiles.git"
+    And $GITHUB_TOKEN is "OTOKEN"
+    And $HUB_VERBOSE is "1"
+    When I successfully run `hub --no-https-upgrade remote add mislav`
+    Then the stderr should contain "> GET http://git.my.org/api/v3/user"
+
   Scenario: Add named public remote
     Given the GitHub API server:
       """
@@ -180,6 +209,18 @@ Feature: hub remote add
     Then the url for "origin" should be "git://github.com/mislav/dotfiles.git"
     And there should be no output
 
+  Scenario: set-url with protocol
+    Given the "origin" remote has url "git://github.com/mislav/dotfiles.git"
+    When I successfully run `hub remote set-url -p https origin`
+    Then the url for "origin" should be "https://github.com/mislav/dotfiles.git"
+    And there should be no output
+
+  Scenario: set-url with protocol for non-GitHub remote
+    Given the "origin" remote has url "https://gitlab.com/mislav/dotfiles.git"
+    When I run `hub remote set-url -p ssh origin`
+    Then the exit status should be 1
+    And the stderr should contain exactly "Error: remote `origin' does not point to a GitHub repository: https://gitlab.com/mislav/dotfiles.git\n"
+
   Scenario: Add public remote including repo name
     Given the GitHub API server:
       """
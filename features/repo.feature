@@ -0,0 +1,59 @@
+Feature: hub repo
+  Background:
+    Given I am in "git://github.com/mislav/dotfiles.git" git repo
+    And I am "mislav" on github.com with OAuth token "OTOKEN"
+
+  Scenario: List topics
+    Given the GitHub API server:
+      """
+      get('/repos/mislav/dotfiles/topics') {
+        halt 415 unless request.env['HTTP_ACCEPT'] == 'application/vnd.github.mercy-preview+json;charset=utf-8'
+        json :names => ["vim", "zsh"]
+      }
+      """
+    When I successfully run `hub repo topics`
+    Then the output should contain exactly:
+      """
+      vim
+      zsh\n
+      """
+
+  Scenario: List topics of another repository
+    Given the GitHub API server:
+      """
+      get('/repos/github/hub/topics') {
+        json :names => ["go", "cli"]
+      }
+      """
+    When I successfully run `hub repo topics github/hub`
+    Then the output should contain exactly:
+      """
+      go
+      cli\n
+      """
+
+  Scenario: Add and remove topics
+    Given the GitHub API server:
+      """
+      get('/repos/mislav/dotfiles/topics') {
+        json :names => ["vim", "zsh", "bash"]
+      }
+      put('/repos/mislav/dotfiles/topics') {
+        halt 415 unless request.env['HTTP_ACCEPT'] == 'application/vnd.github.mercy-preview+json;charset=utf-8'
+        assert :names => ["vim", "zsh", "go", "cli"]
+        json :names => params[:names]
+      }
+      """
+    When I successfully run `hub repo topics --add go,cli --remove bash --add vim`
+    Then the output should contain exactly:
+      """
+      vim
+      zsh
+      go
+      cli\n
+      """
+
+  Scenario: Invalid repository name
+    When I run `hub repo topics dotfiles`
+    Then the exit status should be 2
+    And the stderr should contain "Usage: hub repo topics"
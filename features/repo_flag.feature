@@ -0,0 +1,101 @@
+Feature: hub --repo
+
+  Background:
+    Given I am "mislav" on github.com with OAuth token "OTOKEN"
+    And the current dir is not a repo
+
+  Scenario: List issues outside of a git repository
+    Given the GitHub API server:
+      """
+      get('/repos/github/hub/issues') {
+        json [
+          { :number => 102,
+            :title => "First issue",
+            :state => "open",
+            :user => { :login => "octocat" },
+          },
+        ]
+      }
+      """
+    When I successfully run `hub -R github/hub issue`
+    Then the output should contain exactly:
+      """
+          #102  First issue\n
+      """
+
+  Scenario: Give the repository after the command name
+    Given the GitHub API server:
+      """
+      get('/repos/github/hub/issues') {
+        json [
+          { :number => 102,
+            :title => "First issue",
+            :state => "open",
+            :user => { :login => "octocat" },
+          },
+        ]
+      }
+      """
+    When I successfully run `hub issue -R github/hub`
+    Then the output should contain exactly:
+      """
+          #102  First issue\n
+      """
+
+  Scenario: List pull requests with the long flag form
+    Given the GitHub API server:
+      """
+      get('/repos/github/hub/pulls') {
+        json [
+          { :number => 999,
+            :title => "First",
+            :state => "open",
+            :base => { :ref => "master", :label => "github:master" },
+            :head => { :ref => "patch-1", :label => "octocat:patch-1" },
+            :user => { :login => "octocat" },
+          },
+        ]
+      }
+      """
+    When I successfully run `hub --repo=github/hub pr list`
+    Then the output should contain exactly:
+      """
+          #999  First\n
+      """
+
+  Scenario: Fetch CI status of a branch outside of a git repository
+    Given the remote commit state of "github/hub" "master" is "success"
+    When I successfully run `hub --repo github/hub ci-status master`
+    Then the output should contain exactly "success\n"
+
+  Scenario: Use --repo inside of an unrelated git repository
+    Given I am in "git://github.com/mislav/dotfiles.git" git repo
+    And the GitHub API server:
+      """
+      get('/repos/github/hub/releases') {
+        json [
+          { tag_name: 'v1.2.0',
+            name: 'hub 1.2.0',
+            draft: false,
+            prerelease: false,
+          },
+        ]
+      }
+      """
+    When I successfully run `hub -R github/hub release`
+    Then the output should contain exactly "v1.2.0\n"
+
+  Scenario: Reject --repo for commands that need a local checkout
+    When I run `hub -R github/hub sync`
+    Then the stderr should contain exactly "Error: the `--repo' flag cannot be used with `sync'\n"
+    And the exit status should be 1
+
+  Scenario: Reject --repo for git commands
+    When I run `hub -R github/hub log`
+    Then the stderr should contain exactly "Error: the `--repo' flag cannot be used with `log'\n"
+    And the exit status should be 1
+
+  Scenario: Invalid repository
+    When I run `hub -R hub issue`
+    Then the stderr should contain exactly "Error: invalid repository `hub'; expected [<HOST>/]<OWNER>/<REPO>\n"
+    And the exit status should be 1
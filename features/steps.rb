@@ -39,6 +39,13 @@ Given(/^I am "([^"]*)" on ([\S]+)(?: with OAuth token "([^"]*)")?$/) do |name, h
   end
 end
 
+Given(/^the "([^"]*)" header is configured as "([^"]*)" for ([\S]+)$/) do |name, value, host|
+  edit_hub_config do |cfg|
+    entry = cfg.fetch(host.downcase).first
+    (entry['headers'] ||= {})[name] = value
+  end
+end
+
 Given(/^\$(\w+) is "([^"]*)"$/) do |name, value|
   set_env name, value.gsub(/\$([A-Z_]+)/) { ENV.fetch($1) }
 end
@@ -22,6 +22,8 @@ Before do
   # avoids reading from current user's "~/.gitconfig"
   set_env 'HOME', File.expand_path(File.join(current_dir, 'home'))
   set_env 'TMPDIR', File.expand_path(File.join(current_dir, 'tmp'))
+  # GIT_CONFIG_GLOBAL would otherwise take precedence over HOME
+  set_env 'GIT_CONFIG_GLOBAL', nil
   # https://specifications.freedesktop.org/basedir-spec/basedir-spec-latest.html#variables
   set_env 'XDG_CONFIG_HOME', nil
   set_env 'XDG_CONFIG_DIRS', nil
@@ -9,6 +9,16 @@ Feature: hub sync
     Then the output should contain exactly ""
     And "git fetch --prune --quiet --progress origin" should be run
 
+  Scenario: Fetches tags
+    When I successfully run `hub sync --tags`
+    Then the output should contain exactly "Fetched 0 new tags.\n"
+    And "git fetch --prune --quiet --progress --tags origin" should be run
+
+  Scenario: Fetches all remotes
+    When I successfully run `hub sync --fetch-all`
+    Then the output should contain exactly ""
+    And "git fetch --prune --quiet --progress --all" should be run
+
   Scenario: Fast-forwards currently checked out local branch
     Given I am on the "feature" branch pushed to "origin/feature"
     And I successfully run `git reset -q --hard HEAD^`
@@ -26,6 +36,22 @@ Feature: hub sync
     Then the output should contain "Updated branch feature"
     And the output should contain "Updated branch bugfix"
 
+  Scenario: Quiet sync
+    Given I am on the "feature" branch pushed to "origin/feature"
+    And I successfully run `git reset -q --hard HEAD^`
+    When I successfully run `hub -q sync --tags`
+    Then the output should contain exactly ""
+    And "git fetch --prune --quiet --tags origin" should be run
+    And "git merge --ff-only --quiet refs/remotes/origin/feature" should be run
+
+  Scenario: Silent sync still reports warnings
+    Given I am on the "feature" branch pushed to "origin/feature"
+    And I successfully run `git reset -q --hard HEAD^`
+    And I make a commit
+    When I successfully run `hub --silent sync`
+    Then the stdout should contain exactly ""
+    And the stderr should contain "warning: `feature' and origin/feature have diverged"
+
   Scenario: Refuses to update local branch which has diverged from upstream
     Given I am on the "feature" branch pushed to "origin/feature"
     And I make a commit with message "diverge"
@@ -35,6 +61,34 @@ Feature: hub sync
       warning: `feature' seems to contain unpushed commits\n
       """
 
+  Scenario: Explains a local branch that has diverged from upstream
+    Given I am on the "feature" branch pushed to "origin/feature"
+    And I make a commit with message "local work"
+    And I successfully run `git checkout -q -b other feature^`
+    And I make a commit with message "remote work"
+    And I successfully run `git update-ref refs/remotes/origin/feature other`
+    And I successfully run `git checkout -q feature`
+    When I successfully run `hub sync`
+    Then the stderr should contain exactly:
+      """
+      warning: `feature' and origin/feature have diverged; each has commits the other doesn't
+      (use `git pull --rebase` to replay local commits on top of origin/feature, or `git reset --hard @{u}` to discard them)\n
+      """
+
+  Scenario: Explains a local branch whose upstream was force-pushed
+    Given I am on the "feature" branch pushed to "origin/feature"
+    And I make a commit with message "local work"
+    And I successfully run `git checkout -q -b rewritten feature~2`
+    And I make a commit with message "rewritten history"
+    And I successfully run `git update-ref refs/remotes/origin/feature rewritten`
+    And I successfully run `git checkout -q master`
+    When I successfully run `hub sync`
+    Then the stderr should contain:
+      """
+      warning: `feature' can't be fast-forwarded because origin/feature was force-pushed
+      (use `git checkout feature`, then `git pull --rebase` to replay local commits on top of origin/feature, or `git reset --hard @{u}` to discard them)\n
+      """
+
   Scenario: Deletes local branch that had its upstream deleted
     Given I am on the "feature" branch with upstream "origin/feature"
     And I successfully run `git checkout -q master`
@@ -54,3 +108,131 @@ Feature: hub sync
       """
       warning: `feature' was deleted on origin, but appears not merged into master\n
       """
+
+  Scenario: Porcelain output
+    Given I am on the "feature" branch pushed to "origin/feature"
+    And I successfully run `git reset -q --hard HEAD^`
+    And I am on the "topic" branch pushed to "origin/topic"
+    And I make a commit with message "diverge"
+    And I am on the "uptodate" branch pushed to "origin/uptodate"
+    And I successfully run `git checkout -q master`
+    And I successfully run `git update-ref refs/remotes/origin/master master`
+    When I successfully run `hub sync --porcelain`
+    Then the stdout should contain exactly:
+      """
+      ff feature
+      uptodate master
+      conflict topic
+      uptodate uptodate\n
+      """
+    And the stderr should contain exactly ""
+    And "git fetch --prune --quiet origin" should be run
+
+  Scenario: Porcelain output for branches whose upstream was deleted
+    Given I am on the "gone" branch with upstream "origin/gone"
+    And I successfully run `git checkout -q master`
+    And I successfully run `git merge --no-ff --no-edit gone`
+    And I successfully run `git update-ref refs/remotes/origin/master HEAD`
+    And I successfully run `rm .git/refs/remotes/origin/gone`
+    And I am on the "stale" branch with upstream "origin/stale"
+    And I successfully run `rm .git/refs/remotes/origin/stale`
+    And I am on the "local" branch
+    When I successfully run `hub sync --porcelain`
+    Then the stdout should contain exactly:
+      """
+      deleted gone
+      untracked local
+      uptodate master
+      unmerged stale\n
+      """
+    And the stderr should contain exactly ""
+
+  Scenario: Syncs against a specified remote
+    Given the "fork" remote has url "git://github.com/mislav/faraday.git"
+    When I successfully run `hub sync fork`
+    Then the output should contain exactly ""
+    And "git fetch --prune --quiet --progress fork" should be run
+
+  Scenario: Syncs against a remote given with --remote
+    Given the "fork" remote has url "git://github.com/mislav/faraday.git"
+    When I successfully run `hub sync --remote fork`
+    Then "git fetch --prune --quiet --progress fork" should be run
+
+  Scenario: Refuses to sync against a nonexistent remote
+    When I run `hub sync nope`
+    Then the exit status should be 1
+    And the stderr should contain exactly "Error: no git remote named `nope'\n"
+
+  Scenario: Updates only the branch given with --branch
+    Given I am on the "feature" branch pushed to "origin/feature"
+    And I successfully run `git reset -q --hard HEAD^`
+    And I am on the "bugfix" branch pushed to "origin/bugfix"
+    And I successfully run `git reset -q --hard HEAD^`
+    And I successfully run `git checkout -q master`
+    When I successfully run `hub sync --branch feature`
+    Then the output should contain "Updated branch feature"
+    And the output should not contain "bugfix"
+
+  Scenario: Reports a single branch that is up to date
+    Given I am on the "feature" branch pushed to "origin/feature"
+    When I successfully run `hub sync --branch feature`
+    Then the output should contain exactly "Branch feature is up to date.\n"
+
+  Scenario: Rebases a single diverged branch onto its upstream
+    Given I am on the "feature" branch pushed to "origin/feature"
+    And I make a commit with message "local work"
+    And I successfully run `git checkout -q -b other feature^`
+    And I make a commit with message "remote work"
+    And I successfully run `git update-ref refs/remotes/origin/feature other`
+    And I successfully run `git checkout -q master`
+    When I successfully run `hub sync --branch feature --rebase`
+    Then the output should contain "Rebased branch feature onto origin/feature"
+    And the stderr should contain exactly ""
+    When I successfully run `git merge-base --is-ancestor origin/feature feature`
+    And I successfully run `git rev-parse --abbrev-ref HEAD`
+    Then the output should contain "master"
+
+  Scenario: Returns to the current branch after a failed rebase
+    Given I am on the "feature" branch pushed to "origin/feature"
+    And a file named "README" with:
+      """
+      local
+      """
+    And I successfully run `git add README`
+    And I successfully run `git commit -q -m local`
+    And I successfully run `git checkout -q -b other feature^`
+    And a file named "README" with:
+      """
+      remote
+      """
+    And I successfully run `git add README`
+    And I successfully run `git commit -q -m remote`
+    And I successfully run `git update-ref refs/remotes/origin/feature other`
+    And I successfully run `git checkout -q master`
+    When I successfully run `hub sync --branch feature --rebase`
+    Then the stderr should contain "warning: rebasing `feature' onto origin/feature failed"
+    When I successfully run `git rev-parse --abbrev-ref HEAD`
+    Then the output should contain exactly "master\n"
+
+  Scenario: Leaves a branch that is only ahead of its upstream unchanged
+    Given I am on the "feature" branch pushed to "origin/feature"
+    And I make a commit with message "local work"
+    And I successfully run `git checkout -q master`
+    When I successfully run `hub sync --branch feature --rebase`
+    Then the output should contain exactly "Branch feature is ahead of origin/feature, so it was left unchanged.\n"
+
+  Scenario: Refuses to sync a branch without upstream
+    Given I am on the "feature" branch
+    When I run `hub sync --branch feature`
+    Then the exit status should be 1
+    And the stderr should contain exactly "Error: branch `feature' doesn't track an upstream branch on origin\n"
+
+  Scenario: Refuses to sync a nonexistent branch
+    When I run `hub sync --branch nope`
+    Then the exit status should be 1
+    And the stderr should contain exactly "Error: no local branch named `nope'\n"
+
+  Scenario: Rebase requires a single branch
+    When I run `hub sync --rebase`
+    Then the exit status should be 1
+    And the stderr should contain exactly "Error: --rebase can only be used with --branch\n"
@@ -0,0 +1,18 @@
+Feature: hub version
+
+  Scenario: Show the versions of git and hub
+    When I successfully run `hub version`
+    Then the output should match /\Agit version .+\nhub version .+\n\z/
+
+  Scenario: Show only the hub version
+    When I successfully run `hub version --short`
+    Then the output should match /\A\d+\.\d+\.\d+\S*\n\z/
+
+  Scenario: Show the versions as JSON
+    When I successfully run `hub --version --json`
+    Then the output should match /\A\{"hub":"\d+\.\d+\.\d+\S*","git":"\d+\.\d+[^"]*"\}\n\z/
+
+  Scenario: Conflicting output flags
+    When I run `hub version --short --json`
+    Then the exit status should be 1
+    And the stderr should contain exactly "Error: the `--short' and `--json' flags can't be used together\n"
@@ -0,0 +1,125 @@
+Feature: hub whoami
+
+  Scenario: Show the authenticated user
+    Given I am "mislav" on github.com with OAuth token "OTOKENABCD1234"
+    Given the GitHub API server:
+      """
+      get('/user') {
+        halt 401 unless request.env['HTTP_AUTHORIZATION'] == 'token OTOKENABCD1234'
+        response.headers['X-OAuth-Scopes'] = 'repo, gist'
+        json :login => 'mislav'
+      }
+      """
+    When I successfully run `hub whoami`
+    Then the output should contain exactly:
+      """
+      github.com
+        User: mislav
+        Scopes: repo, gist
+        Token: ********1234
+        URL: https://github.com\n
+      """
+
+  Scenario: Show all hosts
+    Given I am "mislav" on github.com with OAuth token "OTOKENABCD1234"
+    And I am "octokitten" on git.my.org with OAuth token "FITOKENWXYZ6789"
+    Given the GitHub API server:
+      """
+      get('/user') {
+        json :login => 'mislav'
+      }
+      get('/api/v3/user', :host_name => 'git.my.org') {
+        response.headers['X-OAuth-Scopes'] = 'repo'
+        json :login => 'octokitten'
+      }
+      """
+    When I successfully run `hub whoami --all`
+    Then the output should contain exactly:
+      """
+      github.com
+        User: mislav
+        Scopes: (none)
+        Token: ********1234
+        URL: https://github.com
+
+      git.my.org
+        User: octokitten
+        Scopes: repo
+        Token: ********6789
+        URL: https://git.my.org\n
+      """
+
+  Scenario: Reuse the authenticated user
+    Given I am "mislav" on github.com with OAuth token "OTOKENABCD1234"
+    Given the GitHub API server:
+      """
+      count = 0
+      get('/user') {
+        count += 1
+        json :login => "mislav#{count}"
+      }
+      """
+    When I successfully run `hub whoami`
+    And I successfully run `hub whoami`
+    And I successfully run `hub --no-cache whoami`
+    Then the output should contain "User: mislav1\n"
+    And the output should not contain "User: mislav2\n"
+    And the output should contain "User: mislav3\n"
+
+  Scenario: Don't reuse a rejected token
+    Given I am "mislav" on github.com with OAuth token "OTOKENABCD1234"
+    Given the GitHub API server:
+      """
+      count = 0
+      get('/user') {
+        count += 1
+        halt 401, json(:message => "Bad credentials") if count == 1
+        json :login => "mislav"
+      }
+      """
+    When I run `hub whoami`
+    Then the exit status should be 1
+    When I successfully run `hub whoami`
+    Then the output should contain "User: mislav\n"
+
+  Scenario: Only send GITHUB_TOKEN to the default host
+    Given I am "mislav" on github.com with OAuth token "OTOKENABCD1234"
+    And I am "octokitten" on git.my.org with OAuth token "FITOKENWXYZ6789"
+    Given the GitHub API server:
+      """
+      get('/user') {
+        halt 401 unless request.env['HTTP_AUTHORIZATION'] == 'token ENVTOKEN'
+        json :login => 'mislav'
+      }
+      get('/api/v3/user', :host_name => 'git.my.org') {
+        halt 401 unless request.env['HTTP_AUTHORIZATION'] == 'token FITOKENWXYZ6789'
+        json :login => 'octokitten'
+      }
+      """
+    And $GITHUB_TOKEN is "ENVTOKEN"
+    When I successfully run `hub whoami --all`
+    Then the output should contain "User: mislav\n"
+    And the output should contain "User: octokitten\n"
+
+  Scenario: Not logged in
+    When I run `hub whoami`
+    Then the stderr should contain exactly "Error: not logged in to github.com\n"
+    And the exit status should be 1
+
+  Scenario: Invalid token
+    Given I am "mislav" on github.com with OAuth token "OTOKENABCD1234"
+    Given the GitHub API server:
+      """
+      get('/user') {
+        status 401
+        json :message => "Bad credentials"
+      }
+      """
+    When I run `hub whoami`
+    Then the stderr should contain exactly:
+      """
+      Error: not logged in to github.com
+      Error getting current user: Unauthorized (HTTP 401)
+      Bad credentials\n
+      """
+    And the exit status should be 1
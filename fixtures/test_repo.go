@@ -52,6 +52,8 @@ func SetupTestRepo() *TestRepo {
 	}
 
 	overrideEnv("HOME", home)
+	// a GIT_CONFIG_GLOBAL from the environment would take precedence over HOME
+	overrideEnv("GIT_CONFIG_GLOBAL", filepath.Join(home, ".gitconfig"))
 	overrideEnv("XDG_CONFIG_HOME", "")
 	overrideEnv("XDG_CONFIG_DIRS", "")
 
@@ -0,0 +1,75 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/github/hub/cmd"
+)
+
+// credentialHelper is a git credential helper that answers requests with the
+// username and token found in the environment variables with the given suffix,
+// so that the token never shows up in command-line arguments
+const credentialHelper = `!f() { test "$1" = get && echo "username=$HUB_GIT_USERNAME_%[1]d" && echo "password=$HUB_GIT_TOKEN_%[1]d"; }; f`
+
+type credential struct {
+	baseURL  string
+	username string
+	token    string
+}
+
+var credentials []credential
+
+// credentialCommands are the git commands that get to authenticate with the
+// credentials registered by UseCredential
+var credentialCommands = map[string]bool{
+	"clone": true,
+	"fetch": true,
+	"pull":  true,
+	"push":  true,
+}
+
+// UseCredential makes the git commands that hub runs to talk to remotes under
+// baseURL, such as "https://github.com", authenticate with username and token.
+// git only consults this helper after the ones that are configured already.
+// The configuration only ends up in the environment of those git commands,
+// which requires git 2.31 or newer, and isn't persisted anywhere.
+func UseCredential(baseURL, username, token string) {
+	credentials = append(credentials, credential{baseURL, username, token})
+}
+
+// WithCredentials passes the credentials registered by UseCredential to c if
+// it's a git command that talks to remotes
+func WithCredentials(c *cmd.Cmd) *cmd.Cmd {
+	if len(credentials) == 0 || c.Name != "git" || !credentialCommands[subcommand(c.Args)] {
+		return c
+	}
+
+	count, _ := strconv.Atoi(os.Getenv("GIT_CONFIG_COUNT"))
+	for i, cred := range credentials {
+		c.WithEnv(fmt.Sprintf("HUB_GIT_USERNAME_%d", i), cred.username)
+		c.WithEnv(fmt.Sprintf("HUB_GIT_TOKEN_%d", i), cred.token)
+		c.WithEnv(fmt.Sprintf("GIT_CONFIG_KEY_%d", count), fmt.Sprintf("credential.%s.helper", cred.baseURL))
+		c.WithEnv(fmt.Sprintf("GIT_CONFIG_VALUE_%d", count), fmt.Sprintf(credentialHelper, i))
+		count++
+	}
+	c.WithEnv("GIT_CONFIG_COUNT", strconv.Itoa(count))
+
+	return c
+}
+
+// subcommand finds the name of the git command among args, skipping the
+// global flags that come before it
+func subcommand(args []string) string {
+	for i := 0; i < len(args); i++ {
+		switch arg := args[i]; {
+		case arg == "-C" || arg == "-c" || arg == "--git-dir" || arg == "--work-tree" || arg == "--namespace":
+			i++
+		case !strings.HasPrefix(arg, "-"):
+			return arg
+		}
+	}
+	return ""
+}
@@ -0,0 +1,40 @@
+package git
+
+import (
+	"os"
+	"testing"
+
+	"github.com/bmizerany/assert"
+	"github.com/github/hub/cmd"
+	"github.com/github/hub/fixtures"
+)
+
+func TestWithCredentials(t *testing.T) {
+	repo := fixtures.SetupTestRepo()
+	defer func() {
+		repo.TearDown()
+		credentials = nil
+	}()
+
+	UseCredential("https://github.com", "mislav", "OTOKEN")
+	UseCredential("https://git.my.org", "octokitten", "ETOKEN")
+	assert.Equal(t, "", os.Getenv("HUB_GIT_TOKEN_0"))
+	assert.Equal(t, 0, len(WithCredentials(cmd.New("git").WithArgs("-c", "push.default=simple", "log")).Env))
+	assert.Equal(t, 0, len(WithCredentials(cmd.New("sh").WithArgs("-c", "push")).Env))
+
+	push := WithCredentials(cmd.New("git").WithArgs("-C", ".", "push", "origin"))
+	assert.Equal(t, "GIT_CONFIG_COUNT=2", push.Env[len(push.Env)-1])
+	for _, arg := range push.Args {
+		assert.NotEqual(t, "OTOKEN", arg)
+	}
+
+	fill := func(host string) string {
+		c := cmd.New("git").WithArgs("credential", "fill")
+		c.Env = push.Env
+		output, err := c.FilterOutput("protocol=https\nhost=" + host + "\n\n")
+		assert.Equal(t, nil, err)
+		return output
+	}
+	assert.Equal(t, "protocol=https\nhost=github.com\nusername=mislav\npassword=OTOKEN\n", fill("github.com"))
+	assert.Equal(t, "protocol=https\nhost=git.my.org\nusername=octokitten\npassword=ETOKEN\n", fill("git.my.org"))
+}
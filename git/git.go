@@ -169,6 +169,39 @@ func RefList(a, b string) ([]string, error) {
 	return output, nil
 }
 
+// FirstParentList lists the commits on the first-parent history of ref, which
+// are the ones that were made on ref itself rather than merged into it
+func FirstParentList(ref string) ([]string, error) {
+	output, err := gitOutput("rev-list", "--first-parent", ref)
+	if err != nil {
+		return []string{}, fmt.Errorf("Can't load rev-list for %s", ref)
+	}
+
+	return output, nil
+}
+
+// ChangedFiles lists the paths of files changed on b since it diverged from a
+func ChangedFiles(a, b string) ([]string, error) {
+	ref := fmt.Sprintf("%s...%s", a, b)
+	output, err := gitOutput("-c", "core.quotePath=false", "diff", "--name-only", ref)
+	if err != nil {
+		return []string{}, fmt.Errorf("Can't load changed files for %s", ref)
+	}
+
+	return output, nil
+}
+
+// RecentCommits lists up to limit non-merge commits reachable from HEAD,
+// newest first
+func RecentCommits(limit int) ([]string, error) {
+	output, err := gitOutput("rev-list", "--no-merges", fmt.Sprintf("--max-count=%d", limit), "HEAD")
+	if err != nil {
+		return []string{}, fmt.Errorf("Can't load recent commits")
+	}
+
+	return output, nil
+}
+
 func NewRange(a, b string) (*Range, error) {
 	output, err := gitOutput("rev-parse", "-q", a, b)
 	if err != nil {
@@ -266,6 +299,11 @@ func GlobalConfig(name string) (string, error) {
 	return gitGetConfig("--global", name)
 }
 
+func SetConfig(name, value string) error {
+	_, err := gitConfig(name, value)
+	return err
+}
+
 func SetGlobalConfig(name, value string) error {
 	_, err := gitConfig("--global", name, value)
 	return err
@@ -329,6 +367,32 @@ func LocalBranches() ([]string, error) {
 	return lines, err
 }
 
+// RemoteBranches lists the remote-tracking branches of remote by their names
+// on that remote, leaving out its symbolic HEAD
+func RemoteBranches(remote string) ([]string, error) {
+	prefix := fmt.Sprintf("refs/remotes/%s/", remote)
+	lines, err := gitOutput("for-each-ref", "--format=%(refname)", prefix)
+	if err != nil {
+		return []string{}, fmt.Errorf("Can't load branches of remote %s", remote)
+	}
+
+	branches := []string{}
+	for _, line := range lines {
+		if name := strings.TrimPrefix(line, prefix); name != "HEAD" {
+			branches = append(branches, name)
+		}
+	}
+	return branches, nil
+}
+
+func SubmoduleStatus() ([]string, error) {
+	return gitOutput("submodule", "status", "--recursive")
+}
+
+func Tags() ([]string, error) {
+	return gitOutput("tag", "--list")
+}
+
 func gitOutput(input ...string) (outputs []string, err error) {
 	cmd := gitCmd(input...)
 
@@ -353,7 +417,7 @@ func gitCmd(args ...string) *cmd.Cmd {
 		cmd.WithArg(a)
 	}
 
-	return cmd
+	return WithCredentials(cmd)
 }
 
 func IsBuiltInGitCommand(command string) bool {
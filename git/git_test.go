@@ -2,7 +2,9 @@ package git
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -72,6 +74,15 @@ func TestGitRefList(t *testing.T) {
 	assert.Equal(t, "9b5a719a3d76ac9dc2fa635d9b1f34fd73994c06", refList[0])
 }
 
+func TestGitChangedFiles(t *testing.T) {
+	repo := fixtures.SetupTestRepo()
+	defer repo.TearDown()
+
+	files, err := ChangedFiles("08f4b7b6513dffc6245857e497cfd6101dc47818", "9b5a719a3d76ac9dc2fa635d9b1f34fd73994c06")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []string{"test_file"}, files)
+}
+
 func TestGitShow(t *testing.T) {
 	repo := fixtures.SetupTestRepo()
 	defer repo.TearDown()
@@ -99,6 +110,27 @@ func TestGitConfig(t *testing.T) {
 	assert.Equal(t, "", v)
 }
 
+func TestGitConfig_GlobalConfigEnv(t *testing.T) {
+	repo := fixtures.SetupTestRepo()
+	defer repo.TearDown()
+
+	configFile := filepath.Join(os.Getenv("HOME"), "scoped.gitconfig")
+	ioutil.WriteFile(configFile, []byte("[hub]\n\tprotocol = https\n"), 0644)
+	os.Setenv("GIT_CONFIG_GLOBAL", configFile)
+
+	v, err := Config("hub.protocol")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "https", v)
+
+	v, err = GlobalConfig("hub.protocol")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "https", v)
+
+	SetGlobalConfig("hub.host", "git.my.org")
+	content, _ := ioutil.ReadFile(configFile)
+	assert.T(t, strings.Contains(string(content), "host = git.my.org"))
+}
+
 func TestRemotes(t *testing.T) {
 	repo := fixtures.SetupTestRepo()
 	defer repo.TearDown()
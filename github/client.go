@@ -14,6 +14,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/github/hub/utils"
 	"github.com/github/hub/version"
 )
 
@@ -29,14 +30,42 @@ func NewClient(h string) *Client {
 }
 
 func NewClientWithHost(host *Host) *Client {
-	return &Client{host}
+	return &Client{Host: host}
 }
 
 type Client struct {
 	Host *Host
+
+	// Idempotent makes creating issues and pull requests look for an item that
+	// was already created before retrying a POST request that failed in a way
+	// that leaves it unknown whether the server has processed it
+	Idempotent bool
+}
+
+// idempotencyWindow is how recently an issue or pull request has to have been
+// created for it to be considered the result of a failed-looking POST request
+const idempotencyWindow = 10 * time.Minute
+
+// isAmbiguousFailure reports whether a request failed without a definitive
+// answer from GitHub, such as a network error or a server error
+func isAmbiguousFailure(res *simpleResponse, err error) bool {
+	if res == nil {
+		return err != nil
+	}
+	return res.StatusCode >= 500
 }
 
 func (client *Client) FetchPullRequests(project *Project, filterParams map[string]interface{}, limit int, filter func(*PullRequest) bool) (pulls []PullRequest, err error) {
+	pulls = []PullRequest{}
+	err = client.EachPullRequest(project, filterParams, limit, filter, func(pr PullRequest, _ json.RawMessage) {
+		pulls = append(pulls, pr)
+	})
+	return
+}
+
+// EachPullRequest fetches pull requests page by page and calls fn with each
+// pull request that passes filter, along with its raw JSON representation.
+func (client *Client) EachPullRequest(project *Project, filterParams map[string]interface{}, limit int, filter func(*PullRequest) bool, fn func(PullRequest, json.RawMessage)) (err error) {
 	api, err := client.simpleApi()
 	if err != nil {
 		return
@@ -54,7 +83,7 @@ func (client *Client) FetchPullRequests(project *Project, filterParams map[strin
 		path += "&" + query.Encode()
 	}
 
-	pulls = []PullRequest{}
+	count := 0
 	var res *simpleResponse
 
 	for path != "" {
@@ -64,14 +93,19 @@ func (client *Client) FetchPullRequests(project *Project, filterParams map[strin
 		}
 		path = res.Link("next")
 
-		pullsPage := []PullRequest{}
+		pullsPage := []json.RawMessage{}
 		if err = res.Unmarshal(&pullsPage); err != nil {
 			return
 		}
-		for _, pr := range pullsPage {
+		for _, raw := range pullsPage {
+			pr := PullRequest{}
+			if err = json.Unmarshal(raw, &pr); err != nil {
+				return
+			}
 			if filter == nil || filter(&pr) {
-				pulls = append(pulls, pr)
-				if limit > 0 && len(pulls) == limit {
+				fn(pr, raw)
+				count++
+				if limit > 0 && count == limit {
 					path = ""
 					break
 				}
@@ -119,11 +153,18 @@ func (client *Client) CreatePullRequest(project *Project, params map[string]inte
 		return
 	}
 
-	res, err := api.PostJSON(fmt.Sprintf("repos/%s/%s/pulls", project.Owner, project.Name), params)
+	path := fmt.Sprintf("repos/%s/%s/pulls", project.Owner, project.Name)
+	res, err := api.PostJSON(path, params)
+	if client.Idempotent && isAmbiguousFailure(res, err) {
+		if existing := client.recentPullRequest(project, params); existing != nil {
+			return existing, nil
+		}
+		res, err = api.PostJSON(path, params)
+	}
 	if err = checkStatus(201, "creating pull request", res, err); err != nil {
 		if res != nil && res.StatusCode == 404 {
 			projectUrl := strings.SplitN(project.WebURL("", "", ""), "://", 2)[1]
-			err = fmt.Errorf("%s\nAre you sure that %s exists?", err, projectUrl)
+			err = newHTTPError(res.Response, fmt.Sprintf("%s\nAre you sure that %s exists?", err, projectUrl))
 		}
 		return
 	}
@@ -134,6 +175,33 @@ func (client *Client) CreatePullRequest(project *Project, params map[string]inte
 	return
 }
 
+// recentPullRequest finds an open pull request created within the idempotency
+// window that matches the head, base, and title (or converted issue) of params
+func (client *Client) recentPullRequest(project *Project, params map[string]interface{}) *PullRequest {
+	filters := map[string]interface{}{"state": "open"}
+	for _, key := range []string{"head", "base"} {
+		if value, ok := params[key].(string); ok {
+			filters[key] = value
+		}
+	}
+	title, _ := params["title"].(string)
+	issueNumber, _ := params["issue"].(int)
+
+	pulls, err := client.FetchPullRequests(project, filters, 1, func(pr *PullRequest) bool {
+		if time.Since(pr.CreatedAt) > idempotencyWindow {
+			return false
+		}
+		if issueNumber > 0 {
+			return pr.Number == issueNumber
+		}
+		return pr.Title == title
+	})
+	if err != nil || len(pulls) == 0 {
+		return nil
+	}
+	return &pulls[0]
+}
+
 func (client *Client) RequestReview(project *Project, prNumber int, params map[string]interface{}) (err error) {
 	api, err := client.simpleApi()
 	if err != nil {
@@ -215,18 +283,13 @@ func (client *Client) Repository(project *Project) (repo *Repository, err error)
 	return
 }
 
-func (client *Client) CreateRepository(project *Project, description, homepage string, isPrivate bool) (repo *Repository, err error) {
+func (client *Client) CreateRepository(project *Project, params map[string]interface{}) (repo *Repository, err error) {
 	repoURL := "user/repos"
 	if project.Owner != client.Host.User {
 		repoURL = fmt.Sprintf("orgs/%s/repos", project.Owner)
 	}
 
-	params := map[string]interface{}{
-		"name":        project.Name,
-		"description": description,
-		"homepage":    homepage,
-		"private":     isPrivate,
-	}
+	params["name"] = project.Name
 
 	api, err := client.simpleApi()
 	if err != nil {
@@ -254,13 +317,53 @@ func (client *Client) DeleteRepository(project *Project) error {
 	return checkStatus(204, "deleting repository", res, err)
 }
 
+type repositoryTopics struct {
+	Names []string `json:"names"`
+}
+
+func (client *Client) RepositoryTopics(project *Project) (topics []string, err error) {
+	api, err := client.simpleApi()
+	if err != nil {
+		return
+	}
+
+	res, err := api.GetFile(fmt.Sprintf("repos/%s/%s/topics", project.Owner, project.Name), topicsType)
+	if err = checkStatus(200, "getting repository topics", res, err); err != nil {
+		return
+	}
+
+	result := repositoryTopics{}
+	err = res.Unmarshal(&result)
+	topics = result.Names
+	return
+}
+
+func (client *Client) ReplaceRepositoryTopics(project *Project, names []string) (topics []string, err error) {
+	api, err := client.simpleApi()
+	if err != nil {
+		return
+	}
+
+	params := repositoryTopics{Names: names}
+	res, err := api.PutJSONPreview(fmt.Sprintf("repos/%s/%s/topics", project.Owner, project.Name), params, topicsType)
+	if err = checkStatus(200, "updating repository topics", res, err); err != nil {
+		return
+	}
+
+	result := repositoryTopics{}
+	err = res.Unmarshal(&result)
+	topics = result.Names
+	return
+}
+
 type Release struct {
-	Name            string         `json:"name"`
+	Name            string         `json:"name,omitempty"`
 	TagName         string         `json:"tag_name"`
 	TargetCommitish string         `json:"target_commitish"`
 	Body            string         `json:"body"`
 	Draft           bool           `json:"draft"`
 	Prerelease      bool           `json:"prerelease"`
+	GenerateNotes   bool           `json:"generate_release_notes,omitempty"`
 	Assets          []ReleaseAsset `json:"assets"`
 	TarballUrl      string         `json:"tarball_url"`
 	ZipballUrl      string         `json:"zipball_url"`
@@ -276,6 +379,7 @@ type ReleaseAsset struct {
 	Label       string `json:"label"`
 	DownloadUrl string `json:"browser_download_url"`
 	ApiUrl      string `json:"url"`
+	Size        int64  `json:"size"`
 }
 
 func (client *Client) FetchReleases(project *Project, limit int, filter func(*Release) bool) (releases []Release, err error) {
@@ -330,6 +434,27 @@ func (client *Client) FetchRelease(project *Project, tagName string) (*Release,
 	}
 }
 
+// LatestRelease returns the most recent published release of project, or nil
+// if the project has no published releases.
+func (client *Client) LatestRelease(project *Project) (release *Release, err error) {
+	api, err := client.simpleApi()
+	if err != nil {
+		return
+	}
+
+	res, err := api.Get(fmt.Sprintf("repos/%s/%s/releases/latest", project.Owner, project.Name))
+	if err == nil && res.StatusCode == 404 {
+		return
+	}
+	if err = checkStatus(200, "fetching latest release", res, err); err != nil {
+		return
+	}
+
+	release = &Release{}
+	err = res.Unmarshal(release)
+	return
+}
+
 func (client *Client) CreateRelease(project *Project, releaseParams *Release) (release *Release, err error) {
 	api, err := client.simpleApi()
 	if err != nil {
@@ -362,6 +487,100 @@ func (client *Client) EditRelease(release *Release, releaseParams map[string]int
 	return
 }
 
+type GitObject struct {
+	Sha  string `json:"sha"`
+	Type string `json:"type"`
+}
+
+type gitRef struct {
+	Ref    string    `json:"ref"`
+	Object GitObject `json:"object"`
+}
+
+// TagCommitSha returns the SHA of the commit that the tag tagName points to
+// in project, or an empty string if the tag does not exist.
+func (client *Client) TagCommitSha(project *Project, tagName string) (sha string, err error) {
+	api, err := client.simpleApi()
+	if err != nil {
+		return
+	}
+
+	res, err := api.Get(fmt.Sprintf("repos/%s/%s/git/ref/tags/%s", project.Owner, project.Name, tagName))
+	if err == nil && res.StatusCode == 404 {
+		return
+	}
+	if err = checkStatus(200, "getting tag", res, err); err != nil {
+		return
+	}
+
+	ref := gitRef{}
+	if err = res.Unmarshal(&ref); err != nil {
+		return
+	}
+
+	object := ref.Object
+	for object.Type == "tag" {
+		res, err = api.Get(fmt.Sprintf("repos/%s/%s/git/tags/%s", project.Owner, project.Name, object.Sha))
+		if err = checkStatus(200, "getting tag", res, err); err != nil {
+			return
+		}
+		tag := struct {
+			Object GitObject `json:"object"`
+		}{}
+		if err = res.Unmarshal(&tag); err != nil {
+			return
+		}
+		object = tag.Object
+	}
+
+	sha = object.Sha
+	return
+}
+
+func (client *Client) CommitSha(project *Project, ref string) (sha string, err error) {
+	api, err := client.simpleApi()
+	if err != nil {
+		return
+	}
+
+	res, err := api.Get(fmt.Sprintf("repos/%s/%s/commits/%s", project.Owner, project.Name, ref))
+	if err = checkStatus(200, "getting commit", res, err); err != nil {
+		return
+	}
+
+	commit := GitObject{}
+	err = res.Unmarshal(&commit)
+	sha = commit.Sha
+	return
+}
+
+func (client *Client) UpdateTag(project *Project, tagName, sha string) (err error) {
+	api, err := client.simpleApi()
+	if err != nil {
+		return
+	}
+
+	params := map[string]interface{}{
+		"sha":   sha,
+		"force": true,
+	}
+	res, err := api.PatchJSON(fmt.Sprintf("repos/%s/%s/git/refs/tags/%s", project.Owner, project.Name, tagName), params)
+	err = checkStatus(200, "updating tag", res, err)
+	return
+}
+
+// DeleteTag removes the git tag tagName from project
+func (client *Client) DeleteTag(project *Project, tagName string) (err error) {
+	api, err := client.simpleApi()
+	if err != nil {
+		return
+	}
+
+	res, err := api.Delete(fmt.Sprintf("repos/%s/%s/git/refs/tags/%s", project.Owner, project.Name, tagName))
+	err = checkStatus(204, "deleting tag", res, err)
+	return
+}
+
 func (client *Client) DeleteRelease(release *Release) (err error) {
 	api, err := client.simpleApi()
 	if err != nil {
@@ -512,6 +731,7 @@ type Repository struct {
 	Name          string                 `json:"name"`
 	FullName      string                 `json:"full_name"`
 	Parent        *Repository            `json:"parent"`
+	Source        *Repository            `json:"source"`
 	Owner         *User                  `json:"owner"`
 	Private       bool                   `json:"private"`
 	HasWiki       bool                   `json:"has_wiki"`
@@ -551,6 +771,7 @@ type Comment struct {
 }
 
 type Issue struct {
+	Id     int64  `json:"id"`
 	Number int    `json:"number"`
 	State  string `json:"state"`
 	Title  string `json:"title"`
@@ -572,12 +793,14 @@ type Issue struct {
 	CreatedAt time.Time    `json:"created_at"`
 	UpdatedAt time.Time    `json:"updated_at"`
 	MergedAt  time.Time    `json:"merged_at"`
+	ClosedAt  time.Time    `json:"closed_at"`
 
 	RequestedReviewers []User `json:"requested_reviewers"`
 	RequestedTeams     []Team `json:"requested_teams"`
 
 	ApiUrl  string `json:"url"`
 	HtmlUrl string `json:"html_url"`
+	NodeId  string `json:"node_id"`
 
 	ClosedBy *User `json:"closed_by"`
 }
@@ -635,6 +858,16 @@ type Milestone struct {
 }
 
 func (client *Client) FetchIssues(project *Project, filterParams map[string]interface{}, limit int, filter func(*Issue) bool) (issues []Issue, err error) {
+	issues = []Issue{}
+	err = client.EachIssue(project, filterParams, limit, filter, func(issue Issue, _ json.RawMessage) {
+		issues = append(issues, issue)
+	})
+	return
+}
+
+// EachIssue fetches issues page by page and calls fn with each issue that
+// passes filter, along with its raw JSON representation.
+func (client *Client) EachIssue(project *Project, filterParams map[string]interface{}, limit int, filter func(*Issue) bool, fn func(Issue, json.RawMessage)) (err error) {
 	api, err := client.simpleApi()
 	if err != nil {
 		return
@@ -652,7 +885,7 @@ func (client *Client) FetchIssues(project *Project, filterParams map[string]inte
 		path += "&" + query.Encode()
 	}
 
-	issues = []Issue{}
+	count := 0
 	var res *simpleResponse
 
 	for path != "" {
@@ -662,19 +895,97 @@ func (client *Client) FetchIssues(project *Project, filterParams map[string]inte
 		}
 		path = res.Link("next")
 
-		issuesPage := []Issue{}
+		issuesPage := []json.RawMessage{}
 		if err = res.Unmarshal(&issuesPage); err != nil {
 			return
 		}
-		for _, issue := range issuesPage {
+		for _, raw := range issuesPage {
+			issue := Issue{}
+			if err = json.Unmarshal(raw, &issue); err != nil {
+				return
+			}
+			if filter == nil || filter(&issue) {
+				fn(issue, raw)
+				count++
+				if limit > 0 && count == limit {
+					path = ""
+					break
+				}
+			}
+		}
+	}
+
+	return
+}
+
+// SearchResultsLimit is the maximum number of results that the search API
+// will return for a single query, regardless of pagination.
+const SearchResultsLimit = 1000
+
+type issueSearchResults struct {
+	TotalCount        int               `json:"total_count"`
+	IncompleteResults bool              `json:"incomplete_results"`
+	Items             []json.RawMessage `json:"items"`
+}
+
+func (client *Client) SearchIssues(project *Project, query string, limit int, filter func(*Issue) bool) (issues []Issue, total int, err error) {
+	issues = []Issue{}
+	total, err = client.EachSearchIssue(project, query, limit, filter, func(issue Issue, _ json.RawMessage) {
+		issues = append(issues, issue)
+	})
+	return
+}
+
+// EachSearchIssue runs query through the search API scoped to project and
+// calls fn with each matching issue that passes filter. It returns the total
+// number of matches reported by the API, which can exceed SearchResultsLimit.
+func (client *Client) EachSearchIssue(project *Project, query string, limit int, filter func(*Issue) bool, fn func(Issue, json.RawMessage)) (total int, err error) {
+	api, err := client.simpleApi()
+	if err != nil {
+		return
+	}
+
+	q := fmt.Sprintf("repo:%s/%s %s", project.Owner, project.Name, query)
+	params := url.Values{}
+	params.Add("q", strings.TrimSpace(q))
+	path := fmt.Sprintf("search/issues?per_page=%d&%s", perPage(limit, 100), params.Encode())
+
+	count := 0
+	seen := 0
+	var res *simpleResponse
+
+	for path != "" {
+		res, err = api.Get(path)
+		if err = checkStatus(200, "searching issues", res, err); err != nil {
+			return
+		}
+		path = res.Link("next")
+
+		results := issueSearchResults{}
+		if err = res.Unmarshal(&results); err != nil {
+			return
+		}
+		total = results.TotalCount
+
+		for _, raw := range results.Items {
+			seen++
+			issue := Issue{}
+			if err = json.Unmarshal(raw, &issue); err != nil {
+				return
+			}
 			if filter == nil || filter(&issue) {
-				issues = append(issues, issue)
-				if limit > 0 && len(issues) == limit {
+				fn(issue, raw)
+				count++
+				if limit > 0 && count == limit {
 					path = ""
 					break
 				}
 			}
 		}
+
+		if len(results.Items) == 0 || seen >= SearchResultsLimit {
+			path = ""
+		}
 	}
 
 	return
@@ -696,6 +1007,175 @@ func (client *Client) FetchIssue(project *Project, number string) (issue *Issue,
 	return
 }
 
+// reactionsPreview is required for the reactions API on older GitHub
+// Enterprise versions
+const reactionsPreview = "application/vnd.github.squirrel-girl-preview+json"
+
+type Reaction struct {
+	Id      int    `json:"id"`
+	Content string `json:"content"`
+	User    *User  `json:"user"`
+}
+
+// Reactions is the summary of reactions that an issue received
+type Reactions struct {
+	TotalCount int `json:"total_count"`
+	PlusOne    int `json:"+1"`
+	MinusOne   int `json:"-1"`
+	Laugh      int `json:"laugh"`
+	Confused   int `json:"confused"`
+	Heart      int `json:"heart"`
+	Hooray     int `json:"hooray"`
+	Rocket     int `json:"rocket"`
+	Eyes       int `json:"eyes"`
+}
+
+// Count returns the number of reactions of the given content
+func (r *Reactions) Count(content string) int {
+	switch content {
+	case "+1":
+		return r.PlusOne
+	case "-1":
+		return r.MinusOne
+	case "laugh":
+		return r.Laugh
+	case "confused":
+		return r.Confused
+	case "heart":
+		return r.Heart
+	case "hooray":
+		return r.Hooray
+	case "rocket":
+		return r.Rocket
+	case "eyes":
+		return r.Eyes
+	}
+	return 0
+}
+
+// ReactToIssue adds a reaction of the authenticated user to an issue or pull
+// request. Reacting again with the same content returns the existing reaction.
+func (client *Client) ReactToIssue(project *Project, number int, content string) (reaction *Reaction, err error) {
+	api, err := client.simpleApi()
+	if err != nil {
+		return
+	}
+
+	params := map[string]interface{}{"content": content}
+	res, err := api.PostJSONPreview(fmt.Sprintf("repos/%s/%s/issues/%d/reactions", project.Owner, project.Name, number), params, reactionsPreview)
+	if err == nil && res.StatusCode == 200 {
+		// the reaction existed already
+	} else if err = checkStatus(201, "adding reaction", res, err); err != nil {
+		return
+	}
+
+	reaction = &Reaction{}
+	err = res.Unmarshal(reaction)
+	return
+}
+
+// IssueReactions lists the reactions of the given content to an issue
+func (client *Client) IssueReactions(project *Project, number int, content string) (reactions []Reaction, err error) {
+	api, err := client.simpleApi()
+	if err != nil {
+		return
+	}
+
+	path := fmt.Sprintf("repos/%s/%s/issues/%d/reactions?content=%s&per_page=100", project.Owner, project.Name, number, url.QueryEscape(content))
+	reactions = []Reaction{}
+	var res *simpleResponse
+
+	for path != "" {
+		res, err = api.GetPreview(path, reactionsPreview)
+		if err = checkStatus(200, "fetching reactions", res, err); err != nil {
+			return
+		}
+		path = res.Link("next")
+
+		reactionsPage := []Reaction{}
+		if err = res.Unmarshal(&reactionsPage); err != nil {
+			return
+		}
+		reactions = append(reactions, reactionsPage...)
+	}
+
+	return
+}
+
+func (client *Client) DeleteIssueReaction(project *Project, number, reactionId int) error {
+	api, err := client.simpleApi()
+	if err != nil {
+		return err
+	}
+
+	res, err := api.DeletePreview(fmt.Sprintf("repos/%s/%s/issues/%d/reactions/%d", project.Owner, project.Name, number, reactionId), reactionsPreview)
+	return checkStatus(204, "removing reaction", res, err)
+}
+
+// LockIssue locks the conversation of an issue or pull request so that only
+// collaborators can comment. reason is optional.
+func (client *Client) LockIssue(project *Project, number int, reason string) error {
+	api, err := client.simpleApi()
+	if err != nil {
+		return err
+	}
+
+	params := map[string]interface{}{}
+	if reason != "" {
+		params["lock_reason"] = reason
+	}
+	res, err := api.PutJSON(fmt.Sprintf("repos/%s/%s/issues/%d/lock", project.Owner, project.Name, number), params)
+	return checkStatus(204, "locking conversation", res, err)
+}
+
+// UnlockIssue unlocks the conversation of an issue or pull request
+func (client *Client) UnlockIssue(project *Project, number int) error {
+	api, err := client.simpleApi()
+	if err != nil {
+		return err
+	}
+
+	res, err := api.Delete(fmt.Sprintf("repos/%s/%s/issues/%d/lock", project.Owner, project.Name, number))
+	return checkStatus(204, "unlocking conversation", res, err)
+}
+
+// IssueReactionCounts fetches the summary of reactions to an issue
+func (client *Client) IssueReactionCounts(project *Project, number int) (*Reactions, error) {
+	api, err := client.simpleApi()
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := api.GetPreview(fmt.Sprintf("repos/%s/%s/issues/%d", project.Owner, project.Name, number), reactionsPreview)
+	if err = checkStatus(200, "fetching issue", res, err); err != nil {
+		return nil, err
+	}
+
+	issue := struct {
+		Reactions *Reactions `json:"reactions"`
+	}{}
+	if err = res.Unmarshal(&issue); err != nil {
+		return nil, err
+	}
+	if issue.Reactions == nil {
+		issue.Reactions = &Reactions{}
+	}
+	return issue.Reactions, nil
+}
+
+// AddSubIssue links the issue with the given ID, as opposed to its number, as a
+// sub-issue of the parent issue
+func (client *Client) AddSubIssue(project *Project, parentNumber string, issueId int64) error {
+	api, err := client.simpleApi()
+	if err != nil {
+		return err
+	}
+
+	params := map[string]interface{}{"sub_issue_id": issueId}
+	res, err := api.PostJSON(fmt.Sprintf("repos/%s/%s/issues/%s/sub_issues", project.Owner, project.Name, parentNumber), params)
+	return checkStatus(201, "adding sub-issue", res, err)
+}
+
 func (client *Client) FetchComments(project *Project, number string) (comments []Comment, err error) {
 	api, err := client.simpleApi()
 	if err != nil {
@@ -718,7 +1198,14 @@ func (client *Client) CreateIssue(project *Project, params interface{}) (issue *
 		return
 	}
 
-	res, err := api.PostJSON(fmt.Sprintf("repos/%s/%s/issues", project.Owner, project.Name), params)
+	path := fmt.Sprintf("repos/%s/%s/issues", project.Owner, project.Name)
+	res, err := api.PostJSON(path, params)
+	if client.Idempotent && isAmbiguousFailure(res, err) {
+		if existing := client.recentIssue(project, params); existing != nil {
+			return existing, nil
+		}
+		res, err = api.PostJSON(path, params)
+	}
 	if err = checkStatus(201, "creating issue", res, err); err != nil {
 		return
 	}
@@ -728,6 +1215,33 @@ func (client *Client) CreateIssue(project *Project, params interface{}) (issue *
 	return
 }
 
+// recentIssue finds an issue opened by the current user within the idempotency
+// window that has the same title as given in params
+func (client *Client) recentIssue(project *Project, params interface{}) *Issue {
+	paramsMap, ok := params.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	title, _ := paramsMap["title"].(string)
+
+	filters := map[string]interface{}{
+		"sort":      "created",
+		"direction": "desc",
+		"since":     time.Now().Add(-idempotencyWindow).UTC().Format(time.RFC3339),
+	}
+	if client.Host != nil && client.Host.User != "" {
+		filters["creator"] = client.Host.User
+	}
+
+	issues, err := client.FetchIssues(project, filters, 1, func(issue *Issue) bool {
+		return issue.PullRequest == nil && issue.Title == title && time.Since(issue.CreatedAt) <= idempotencyWindow
+	})
+	if err != nil || len(issues) == 0 {
+		return nil
+	}
+	return &issues[0]
+}
+
 func (client *Client) UpdateIssue(project *Project, issueNumber int, params map[string]interface{}) (err error) {
 	api, err := client.simpleApi()
 	if err != nil {
@@ -785,6 +1299,35 @@ func (client *Client) FetchLabels(project *Project) (labels []IssueLabel, err er
 	return
 }
 
+// FetchTeamMembers lists the members of the team identified by its slug
+func (client *Client) FetchTeamMembers(org, teamSlug string) (members []User, err error) {
+	api, err := client.simpleApi()
+	if err != nil {
+		return
+	}
+
+	path := fmt.Sprintf("orgs/%s/teams/%s/members?per_page=100", org, teamSlug)
+
+	members = []User{}
+	var res *simpleResponse
+
+	for path != "" {
+		res, err = api.Get(path)
+		if err = checkStatus(200, "fetching team members", res, err); err != nil {
+			return
+		}
+		path = res.Link("next")
+
+		membersPage := []User{}
+		if err = res.Unmarshal(&membersPage); err != nil {
+			return
+		}
+		members = append(members, membersPage...)
+	}
+
+	return
+}
+
 func (client *Client) FetchMilestones(project *Project) (milestones []Milestone, err error) {
 	api, err := client.simpleApi()
 	if err != nil {
@@ -840,12 +1383,112 @@ func (client *Client) GenericAPIRequest(method, path string, data interface{}, h
 		if body != nil {
 			req.Header.Set("Content-Type", "application/json; charset=utf-8")
 		}
+		// uploads require the length of the body to be known up front
+		if file, ok := body.(*os.File); ok {
+			if info, err := file.Stat(); err == nil && info.Mode().IsRegular() {
+				req.ContentLength = info.Size()
+			}
+		}
 		for key, value := range headers {
 			req.Header.Set(key, value)
 		}
 	})
 }
 
+type graphQLError struct {
+	Message string `json:"message"`
+}
+
+type graphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []graphQLError  `json:"errors"`
+}
+
+// GraphQL performs a query against the GitHub GraphQL API and decodes the
+// "data" portion of the response into data.
+func (client *Client) GraphQL(query string, variables map[string]interface{}, data interface{}) (err error) {
+	api, err := client.simpleApi()
+	if err != nil {
+		return
+	}
+
+	params := map[string]interface{}{"query": query}
+	if len(variables) > 0 {
+		params["variables"] = variables
+	}
+
+	res, err := api.PostJSON("graphql", params)
+	if err = checkStatus(200, "performing GraphQL query", res, err); err != nil {
+		return
+	}
+
+	result := graphQLResponse{}
+	if err = res.Unmarshal(&result); err != nil {
+		return
+	}
+	if len(result.Errors) > 0 {
+		messages := []string{}
+		for _, e := range result.Errors {
+			messages = append(messages, e.Message)
+		}
+		err = fmt.Errorf("Error performing GraphQL query: %s", strings.Join(messages, "\n"))
+		return
+	}
+
+	if data != nil {
+		err = json.Unmarshal(result.Data, data)
+	}
+	return
+}
+
+// ProjectV2ID looks up the node ID of the project numbered number owned by the
+// user or organization owner.
+func (client *Client) ProjectV2ID(owner string, number int) (id string, err error) {
+	query := `query($owner: String!, $number: Int!) {
+  repositoryOwner(login: $owner) {
+    ... on ProjectV2Owner {
+      projectV2(number: $number) { id }
+    }
+  }
+}`
+	data := struct {
+		RepositoryOwner *struct {
+			ProjectV2 *struct {
+				ID string `json:"id"`
+			} `json:"projectV2"`
+		} `json:"repositoryOwner"`
+	}{}
+
+	err = client.GraphQL(query, map[string]interface{}{
+		"owner":  owner,
+		"number": number,
+	}, &data)
+	if err != nil {
+		return
+	}
+
+	if data.RepositoryOwner == nil || data.RepositoryOwner.ProjectV2 == nil {
+		err = fmt.Errorf("Error finding project: %s/%d does not exist", owner, number)
+		return
+	}
+	id = data.RepositoryOwner.ProjectV2.ID
+	return
+}
+
+// AddProjectV2Item adds the issue or pull request with node ID contentID to
+// the project with node ID projectID.
+func (client *Client) AddProjectV2Item(projectID, contentID string) error {
+	query := `mutation($projectId: ID!, $contentId: ID!) {
+  addProjectV2ItemById(input: {projectId: $projectId, contentId: $contentId}) {
+    item { id }
+  }
+}`
+	return client.GraphQL(query, map[string]interface{}{
+		"projectId": projectID,
+		"contentId": contentID,
+	}, nil)
+}
+
 func (client *Client) CurrentUser() (user *User, err error) {
 	api, err := client.simpleApi()
 	if err != nil {
@@ -862,6 +1505,41 @@ func (client *Client) CurrentUser() (user *User, err error) {
 	return
 }
 
+// currentUserCacheTTL is how many seconds the authenticated user of a token is
+// reused for
+const currentUserCacheTTL = 60
+
+// CachedCurrentUserScopes fetches the authenticated user along with the OAuth
+// scopes that were granted to the access token. The response is reused for
+// the same token for a minute unless `--no-cache` is given.
+func (client *Client) CachedCurrentUserScopes() (user *User, scopes []string, err error) {
+	api, err := client.simpleApi()
+	if err != nil {
+		return
+	}
+	if !NoRepositoryCache {
+		api.CacheTTL = currentUserCacheTTL
+	}
+
+	res, err := api.Get("user")
+	if err = checkStatus(200, "getting current user", res, err); err != nil {
+		return
+	}
+
+	user = &User{}
+	if err = res.Unmarshal(user); err != nil {
+		return
+	}
+
+	scopes = []string{}
+	for _, scope := range strings.Split(res.Header.Get("X-OAuth-Scopes"), ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			scopes = append(scopes, scope)
+		}
+	}
+	return
+}
+
 type AuthorizationEntry struct {
 	Token string `json:"token"`
 }
@@ -959,6 +1637,9 @@ func (client *Client) simpleApi() (c *simpleClient, err error) {
 		requestHost := strings.ToLower(req.URL.Host)
 		if requestHost == clientDomain || strings.HasSuffix(requestHost, "."+clientDomain) {
 			req.Header.Set("Authorization", "token "+client.Host.AccessToken)
+			for name, value := range client.Host.Headers {
+				req.Header.Set(name, value)
+			}
 		}
 	}
 	return
@@ -966,7 +1647,7 @@ func (client *Client) simpleApi() (c *simpleClient, err error) {
 
 func (client *Client) apiClient() *simpleClient {
 	unixSocket := os.ExpandEnv(client.Host.UnixSocket)
-	httpClient := newHttpClient(os.Getenv("HUB_TEST_HOST"), os.Getenv("HUB_VERBOSE") != "", unixSocket)
+	httpClient := newHttpClient(os.Getenv("HUB_TEST_HOST"), utils.VerboseLevel(), unixSocket)
 	apiRoot := client.absolute(normalizeHost(client.Host.Host))
 	if !strings.HasPrefix(apiRoot.Host, "api.github.") {
 		apiRoot.Path = "/api/v3/"
@@ -1000,15 +1681,66 @@ func normalizeHost(host string) string {
 	}
 }
 
+// HTTPError is an unexpected API response. Its exit code tells apart
+// authentication failures, missing resources, and rate limiting.
+type HTTPError struct {
+	StatusCode  int
+	RateLimited bool
+	message     string
+}
+
+func newHTTPError(response *http.Response, message string) *HTTPError {
+	rateLimited := response.StatusCode == 429 ||
+		(response.StatusCode == 403 && response.Header.Get("X-RateLimit-Remaining") == "0")
+	return &HTTPError{
+		StatusCode:  response.StatusCode,
+		RateLimited: rateLimited,
+		message:     message,
+	}
+}
+
+func (e *HTTPError) Error() string {
+	return e.message
+}
+
+func (e *HTTPError) ExitCode() int {
+	switch {
+	case e.RateLimited:
+		return utils.ExitRateLimited
+	case e.StatusCode == 401:
+		return utils.ExitAuth
+	case e.StatusCode == 404:
+		return utils.ExitNotFound
+	default:
+		return utils.ExitError
+	}
+}
+
+// NetworkError is an API request that failed without getting a response,
+// for example because of a timeout or a failed connection
+type NetworkError struct {
+	Err error
+}
+
+func (e *NetworkError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *NetworkError) ExitCode() int {
+	return utils.ExitNetwork
+}
+
 func checkStatus(expectedStatus int, action string, response *simpleResponse, err error) error {
-	if err != nil {
+	if _, ok := err.(*NetworkError); ok {
+		return &NetworkError{fmt.Errorf("Error %s: %s", action, err.Error())}
+	} else if err != nil {
 		return fmt.Errorf("Error %s: %s", action, err.Error())
 	} else if response.StatusCode != expectedStatus {
 		errInfo, err := response.ErrorInfo()
 		if err == nil {
 			return FormatError(action, errInfo)
 		} else {
-			return fmt.Errorf("Error %s: %s (HTTP %d)", action, err.Error(), response.StatusCode)
+			return newHTTPError(response.Response, fmt.Sprintf("Error %s: %s (HTTP %d)", action, err.Error(), response.StatusCode))
 		}
 	} else {
 		return nil
@@ -1055,7 +1787,7 @@ func FormatError(action string, err error) (ee error) {
 			errStr = fmt.Sprintf("%s\n%s", errStr, errorMessage)
 		}
 
-		ee = fmt.Errorf(errStr)
+		ee = newHTTPError(e.Response, errStr)
 	}
 
 	return
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/bmizerany/assert"
+	"github.com/github/hub/utils"
 )
 
 func TestClient_FormatError(t *testing.T) {
@@ -31,6 +32,22 @@ func TestClient_FormatError(t *testing.T) {
 	assert.Equal(t, "Error action: Unprocessable Entity (HTTP 422)\nerror message", fmt.Sprintf("%s", err))
 }
 
+func TestClient_FormatError_ExitCode(t *testing.T) {
+	exitCode := func(statusCode int, header http.Header) int {
+		err := FormatError("action", &errorInfo{
+			Response: &http.Response{StatusCode: statusCode, Header: header},
+		})
+		return utils.ExitCode(err)
+	}
+
+	assert.Equal(t, utils.ExitAuth, exitCode(401, nil))
+	assert.Equal(t, utils.ExitNotFound, exitCode(404, nil))
+	assert.Equal(t, utils.ExitError, exitCode(403, nil))
+	assert.Equal(t, utils.ExitRateLimited, exitCode(403, http.Header{"X-Ratelimit-Remaining": {"0"}}))
+	assert.Equal(t, utils.ExitError, exitCode(422, nil))
+	assert.Equal(t, utils.ExitNetwork, utils.ExitCode(&NetworkError{fmt.Errorf("timeout")}))
+}
+
 func TestAuthTokenNote(t *testing.T) {
 	note, err := authTokenNote(1)
 	assert.Equal(t, nil, err)
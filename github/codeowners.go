@@ -0,0 +1,127 @@
+package github
+
+import (
+	"bufio"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// codeOwnersLocations are searched in order, like GitHub does
+var codeOwnersLocations = []string{
+	filepath.Join(githubTemplateDir, "CODEOWNERS"),
+	"CODEOWNERS",
+	filepath.Join(docsDir, "CODEOWNERS"),
+}
+
+type codeOwnersRule struct {
+	pattern *regexp.Regexp
+	owners  []string
+}
+
+// CodeOwners matches file paths against the rules of a CODEOWNERS file
+type CodeOwners struct {
+	rules []codeOwnersRule
+}
+
+// ReadCodeOwners parses the CODEOWNERS file of the working tree at workdir.
+// It returns nil if there is no such file.
+func ReadCodeOwners(workdir string) (*CodeOwners, error) {
+	for _, location := range codeOwnersLocations {
+		content, err := ioutil.ReadFile(filepath.Join(workdir, location))
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		return ParseCodeOwners(string(content)), nil
+	}
+	return nil, nil
+}
+
+// ParseCodeOwners parses the contents of a CODEOWNERS file. Lines that can't
+// be turned into a pattern are skipped.
+func ParseCodeOwners(content string) *CodeOwners {
+	codeOwners := &CodeOwners{}
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if i := strings.Index(line, " #"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		pattern, err := codeOwnersPattern(strings.Replace(fields[0], `\#`, "#", -1))
+		if err != nil {
+			continue
+		}
+		codeOwners.rules = append(codeOwners.rules, codeOwnersRule{
+			pattern: pattern,
+			owners:  fields[1:],
+		})
+	}
+	return codeOwners
+}
+
+// Owners returns the owners of the file at path, relative to the root of the
+// repository. The last matching rule takes precedence. The second return value
+// reports whether any rule matched; a rule without owners leaves the file
+// without any.
+func (c *CodeOwners) Owners(path string) ([]string, bool) {
+	path = strings.TrimPrefix(filepath.ToSlash(path), "/")
+	for i := len(c.rules) - 1; i >= 0; i-- {
+		if c.rules[i].pattern.MatchString(path) {
+			return c.rules[i].owners, true
+		}
+	}
+	return nil, false
+}
+
+// codeOwnersPattern translates a CODEOWNERS pattern, which mostly follows the
+// rules of gitignore files, to a regular expression that matches file paths. A
+// pattern that matches a directory matches every file within it.
+func codeOwnersPattern(pattern string) (*regexp.Regexp, error) {
+	dirOnly := strings.HasSuffix(pattern, "/")
+	pattern = strings.TrimSuffix(pattern, "/")
+	anchored := strings.Contains(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+
+	expr := "^"
+	if !anchored {
+		expr += "(?:.*/)?"
+	}
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			expr += "(?:.*/)?"
+			i += 2
+		case strings.HasPrefix(pattern[i:], "/**") && i+3 == len(pattern):
+			expr += "/.*"
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			expr += ".*"
+			i++
+		case c == '*':
+			expr += "[^/]*"
+		case c == '?':
+			expr += "[^/]"
+		default:
+			expr += regexp.QuoteMeta(string(c))
+		}
+	}
+	if dirOnly {
+		expr += "/.*$"
+	} else if strings.HasSuffix(pattern, "/*") {
+		// unlike in gitignore, "docs/*" doesn't match files in subdirectories
+		expr += "$"
+	} else {
+		expr += "(?:/.*)?$"
+	}
+
+	return regexp.Compile(expr)
+}
@@ -0,0 +1,77 @@
+package github
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestCodeOwners_Owners(t *testing.T) {
+	codeOwners := ParseCodeOwners(`# default owners
+*       @global-owner
+
+*.js    @js-owner # inline comment
+/build/logs/ @doctocat
+docs/*  docs@example.com
+apps/   @octocat
+**/logs @monalisa
+/scripts/ @doctocat @octocat
+/apps/github
+\#notes @hashtag
+`)
+
+	for path, expected := range map[string][]string{
+		"README.md":              {"@global-owner"},
+		"src/index.js":           {"@js-owner"},
+		"build/logs/out.txt":     {"@monalisa"},
+		"docs/getting-started":   {"docs@example.com"},
+		"docs/build-app/intro":   {"@global-owner"},
+		"apps/web/index.html":    {"@octocat"},
+		"nested/apps/config.yml": {"@octocat"},
+		"deploy/logs/today.log":  {"@monalisa"},
+		"scripts/bootstrap":      {"@doctocat", "@octocat"},
+		"apps/github/index.html": {},
+		"#notes":                 {"@hashtag"},
+	} {
+		owners, matched := codeOwners.Owners(path)
+		assert.Equal(t, true, matched)
+		assert.Equal(t, len(expected), len(owners))
+		for i := range expected {
+			assert.Equal(t, expected[i], owners[i])
+		}
+	}
+}
+
+func TestCodeOwners_NoMatch(t *testing.T) {
+	codeOwners := ParseCodeOwners("/src/ @octocat\n*.go @gopher\n")
+
+	_, matched := codeOwners.Owners("README.md")
+	assert.Equal(t, false, matched)
+	_, matched = codeOwners.Owners("lib/src/main.c")
+	assert.Equal(t, false, matched)
+
+	owners, matched := codeOwners.Owners("lib/main.go")
+	assert.Equal(t, true, matched)
+	assert.Equal(t, []string{"@gopher"}, owners)
+}
+
+func TestReadCodeOwners(t *testing.T) {
+	workdir, _ := ioutil.TempDir("", "codeowners")
+	defer os.RemoveAll(workdir)
+
+	codeOwners, err := ReadCodeOwners(workdir)
+	assert.Equal(t, nil, err)
+	assert.T(t, codeOwners == nil)
+
+	os.MkdirAll(filepath.Join(workdir, "docs"), 0755)
+	ioutil.WriteFile(filepath.Join(workdir, "docs", "CODEOWNERS"), []byte("* @docs\n"), 0644)
+	ioutil.WriteFile(filepath.Join(workdir, "CODEOWNERS"), []byte("* @root\n"), 0644)
+
+	codeOwners, err = ReadCodeOwners(workdir)
+	assert.Equal(t, nil, err)
+	owners, _ := codeOwners.Owners("main.go")
+	assert.Equal(t, []string{"@root"}, owners)
+}
@@ -11,7 +11,9 @@ import (
 	"strconv"
 	"strings"
 	"syscall"
+	"time"
 
+	"github.com/github/hub/git"
 	"github.com/github/hub/ui"
 	"github.com/github/hub/utils"
 	"github.com/mitchellh/go-homedir"
@@ -19,27 +21,45 @@ import (
 )
 
 type yamlHost struct {
-	User       string `yaml:"user"`
-	OAuthToken string `yaml:"oauth_token"`
-	Protocol   string `yaml:"protocol"`
-	UnixSocket string `yaml:"unix_socket,omitempty"`
+	User       string            `yaml:"user"`
+	OAuthToken string            `yaml:"oauth_token"`
+	Protocol   string            `yaml:"protocol"`
+	UnixSocket string            `yaml:"unix_socket,omitempty"`
+	Headers    map[string]string `yaml:"headers,omitempty"`
 }
 
 type Host struct {
-	Host        string `toml:"host"`
-	User        string `toml:"user"`
-	AccessToken string `toml:"access_token"`
-	Protocol    string `toml:"protocol"`
-	UnixSocket  string `toml:"unix_socket,omitempty"`
+	Host        string            `toml:"host"`
+	User        string            `toml:"user"`
+	AccessToken string            `toml:"access_token"`
+	Protocol    string            `toml:"protocol"`
+	UnixSocket  string            `toml:"unix_socket,omitempty"`
+	Headers     map[string]string `toml:"headers,omitempty"`
+
+	keyringToken bool
 }
 
 type Config struct {
 	Hosts []*Host `toml:"hosts"`
 }
 
+// ForcedScheme is set by the "--scheme" global flag. It skips probing the
+// protocol of hosts that hub authenticates with for the first time.
+var ForcedScheme string
+
+// probeTimeout bounds each attempt to reach the API of a new host
+var probeTimeout = 3 * time.Second
+
 func (c *Config) PromptForHost(host string) (h *Host, err error) {
 	token := c.DetectToken()
 	tokenFromEnv := token != ""
+	if !tokenFromEnv {
+		if existing := c.Find(host); existing == nil || existing.AccessToken == "" {
+			// like GITHUB_TOKEN, a token from .netrc is never saved to the config
+			token = netrcToken(host)
+			tokenFromEnv = token != ""
+		}
+	}
 
 	if host != GitHubHost {
 		if _, e := url.Parse("https://" + host); e != nil {
@@ -60,7 +80,7 @@ func (c *Config) PromptForHost(host string) (h *Host, err error) {
 				utils.Check(fmt.Errorf("missing user"))
 			}
 			h.User = user
-			err := newConfigService().Save(configsFile(), c)
+			err := c.save()
 			utils.Check(err)
 		}
 		if tokenFromEnv {
@@ -69,10 +89,18 @@ func (c *Config) PromptForHost(host string) (h *Host, err error) {
 			return
 		}
 	} else {
+		protocol := "https"
+		if ForcedScheme != "" {
+			protocol = ForcedScheme
+		} else if remoteProtocol := mainRemoteAPIProtocol(host); remoteProtocol != "" {
+			protocol = remoteProtocol
+		} else if !tokenFromEnv && host != GitHubHost {
+			protocol = c.probeAPI(host)
+		}
 		h = &Host{
 			Host:        host,
 			AccessToken: token,
-			Protocol:    "https",
+			Protocol:    protocol,
 		}
 		c.Hosts = append(c.Hosts, h)
 	}
@@ -94,12 +122,42 @@ func (c *Config) PromptForHost(host string) (h *Host, err error) {
 	h.User = currentUser.Login
 
 	if !tokenFromEnv {
-		err = newConfigService().Save(configsFile(), c)
+		err = c.save()
 	}
 
 	return
 }
 
+// probeAPI checks over which protocol the API of an Enterprise host answers,
+// so that it can be stored with the host. It never switches to HTTP on its
+// own, since the token would then be sent unencrypted; if only HTTP answers,
+// it asks before using it.
+func (c *Config) probeAPI(host string) string {
+	httpClient := newHttpClient(os.Getenv("HUB_TEST_HOST"), utils.VerboseLevel(), "")
+	httpClient.Timeout = probeTimeout
+
+	reachable := func(protocol string) bool {
+		res, err := httpClient.Get(fmt.Sprintf("%s://%s/api/v3/meta", protocol, host))
+		if err == nil {
+			res.Body.Close()
+		}
+		return err == nil
+	}
+
+	if reachable("https") {
+		return "https"
+	} else if !reachable("http") {
+		ui.Errorf("Warning: could not reach the API of %s over HTTPS\n", host)
+		return "https"
+	}
+
+	ui.Printf("The API of %s only answers over HTTP, which sends the token unencrypted. Use HTTP anyway (y/N)? ", host)
+	if answer := strings.TrimSpace(c.scanLine()); strings.EqualFold(answer, "y") || strings.EqualFold(answer, "yes") {
+		return "http"
+	}
+	return "https"
+}
+
 func (c *Config) authorizeClient(client *Client, host string) (err error) {
 	user := c.PromptForUser(host)
 	pass := c.PromptForPassword(host, user)
@@ -132,6 +190,41 @@ func (c *Config) DetectToken() string {
 	return os.Getenv("GITHUB_TOKEN")
 }
 
+// UseTokensForGit hands the tokens of the configured hosts, or GITHUB_TOKEN for
+// the default host, to the git commands that hub runs so that git doesn't have
+// to prompt for credentials of HTTPS remotes
+func (c *Config) UseTokensForGit() {
+	envToken := c.DetectToken()
+	envHost := GitHubHostEnv
+	if envHost == "" {
+		envHost = GitHubHost
+	}
+
+	for _, h := range c.Hosts {
+		token := h.AccessToken
+		if envToken != "" && h.Host == envHost {
+			token = envToken
+			envToken = ""
+		}
+		if token == "" {
+			continue
+		}
+		user := h.User
+		if user == "" {
+			user = "x-access-token"
+		}
+		protocol := h.Protocol
+		if protocol == "" {
+			protocol = "https"
+		}
+		git.UseCredential(protocol+"://"+h.Host, user, token)
+	}
+
+	if envToken != "" {
+		git.UseCredential("https://"+envHost, "x-access-token", envToken)
+	}
+}
+
 func (c *Config) PromptForUser(host string) (user string) {
 	user = os.Getenv("GITHUB_USER")
 	if user != "" {
@@ -226,6 +319,14 @@ func (c *Config) selectHost() *Host {
 		return c.Hosts[0]
 	}
 
+	if !ui.IsTerminal(os.Stdin) {
+		hostnames := []string{}
+		for _, host := range c.Hosts {
+			hostnames = append(hostnames, "  "+host.Host)
+		}
+		utils.Check(fmt.Errorf("Error: multiple hosts are configured; choose one with `--host' or GITHUB_HOST:\n%s", strings.Join(hostnames, "\n")))
+	}
+
 	prompt := "Select host:\n"
 	for idx, host := range c.Hosts {
 		prompt += fmt.Sprintf(" %d. %s\n", idx+1, host.Host)
@@ -312,6 +413,7 @@ func CurrentConfig() *Config {
 		currentConfig = &Config{}
 		newConfigService().Load(filename, currentConfig)
 		configLoadedFrom = filename
+		currentConfig.loadKeyringTokens()
 	}
 
 	return currentConfig
@@ -332,15 +434,19 @@ func (c *Config) DefaultHost() (host *Host, err error) {
 }
 
 func (c *Config) DefaultHostNoPrompt() (*Host, error) {
+	// HACK: forces host to inherit GITHUB_TOKEN if applicable
+	return c.PromptForHost(c.DefaultHostName())
+}
+
+// DefaultHostName is the name of the host that DefaultHostNoPrompt picks,
+// without asking for credentials
+func (c *Config) DefaultHostName() string {
 	if GitHubHostEnv != "" {
-		return c.PromptForHost(GitHubHostEnv)
+		return GitHubHostEnv
 	} else if len(c.Hosts) > 0 {
-		host := c.Hosts[0]
-		// HACK: forces host to inherit GITHUB_TOKEN if applicable
-		return c.PromptForHost(host.Host)
-	} else {
-		return c.PromptForHost(GitHubHost)
+		return c.Hosts[0].Host
 	}
+	return GitHubHost
 }
 
 // CheckWriteable checks if config file is writeable. This should
@@ -1,6 +1,7 @@
 package github
 
 import (
+	"fmt"
 	"io"
 	"io/ioutil"
 
@@ -52,6 +53,13 @@ func (y *yamlConfigDecoder) Decode(r io.Reader, c *Config) error {
 				host.Protocol = prop.Value.(string)
 			case "unix_socket":
 				host.UnixSocket = prop.Value.(string)
+			case "headers":
+				if headers, ok := prop.Value.(yaml.MapSlice); ok {
+					host.Headers = make(map[string]string)
+					for _, header := range headers {
+						host.Headers[fmt.Sprint(header.Key)] = fmt.Sprint(header.Value)
+					}
+				}
 			}
 		}
 		c.Hosts = append(c.Hosts, host)
@@ -33,6 +33,7 @@ func (y *yamlConfigEncoder) Encode(w io.Writer, c *Config) error {
 					OAuthToken: h.AccessToken,
 					Protocol:   h.Protocol,
 					UnixSocket: h.UnixSocket,
+					Headers:    h.Headers,
 				},
 			},
 		})
@@ -207,3 +207,61 @@ func TestConfigService_YamlSave_UnixSocket(t *testing.T) {
   unix_socket: /tmp/go.sock`
 	assert.Equal(t, content, strings.TrimSpace(string(b)))
 }
+
+func TestConfigService_YamlLoad_Headers(t *testing.T) {
+	file, _ := ioutil.TempFile("", "test-gh-config-")
+	defer os.RemoveAll(file.Name())
+
+	content := `---
+github.example.com:
+- user: jingweno
+  oauth_token: "123"
+  protocol: https
+  headers:
+    Accept: application/vnd.github.preview+json
+    X-Foo: bar`
+	ioutil.WriteFile(file.Name(), []byte(content), os.ModePerm)
+
+	cc := &Config{}
+	cs := &configService{
+		Encoder: &yamlConfigEncoder{},
+		Decoder: &yamlConfigDecoder{},
+	}
+	err := cs.Load(file.Name(), cc)
+	assert.Equal(t, nil, err)
+
+	assert.Equal(t, 1, len(cc.Hosts))
+	host := cc.Hosts[0]
+	assert.Equal(t, "application/vnd.github.preview+json", host.Headers["Accept"])
+	assert.Equal(t, "bar", host.Headers["X-Foo"])
+}
+
+func TestConfigService_YamlSave_Headers(t *testing.T) {
+	file, _ := ioutil.TempFile("", "test-gh-config-")
+	defer os.RemoveAll(file.Name())
+
+	host := &Host{
+		Host:        "github.com",
+		User:        "jingweno",
+		AccessToken: "123",
+		Protocol:    "https",
+		Headers:     map[string]string{"X-Foo": "bar"},
+	}
+	c := &Config{Hosts: []*Host{host}}
+
+	cs := &configService{
+		Encoder: &yamlConfigEncoder{},
+		Decoder: &yamlConfigDecoder{},
+	}
+	err := cs.Save(file.Name(), c)
+	assert.Equal(t, nil, err)
+
+	b, _ := ioutil.ReadFile(file.Name())
+	content := `github.com:
+- user: jingweno
+  oauth_token: "123"
+  protocol: https
+  headers:
+    X-Foo: bar`
+	assert.Equal(t, content, strings.TrimSpace(string(b)))
+}
@@ -149,3 +149,42 @@ func openTextEditor(program, file string) error {
 
 	return editCmd.Spawn()
 }
+
+// draftFile is where the last composed message for filename is kept. It lives
+// in the git directory so that each repository has its own drafts.
+func draftFile(filename string) (string, error) {
+	gitDir, err := git.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(gitDir, "hub-drafts", filename), nil
+}
+
+func saveDraft(filename, content string) error {
+	file, err := draftFile(filename)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, []byte(content), 0600)
+}
+
+func readDraft(filename string) (string, error) {
+	file, err := draftFile(filename)
+	if err != nil {
+		return "", err
+	}
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
+
+func deleteDraft(filename string) {
+	if file, err := draftFile(filename); err == nil {
+		os.Remove(file)
+	}
+}
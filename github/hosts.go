@@ -4,14 +4,16 @@ import (
 	"fmt"
 	"net/url"
 	"os"
+	"regexp"
 	"strings"
 
 	"github.com/github/hub/git"
 )
 
 var (
-	GitHubHostEnv = os.Getenv("GITHUB_HOST")
-	cachedHosts   []string
+	GitHubHostEnv     = os.Getenv("GITHUB_HOST")
+	cachedHosts       []string
+	cachedHostAliases map[string]string
 )
 
 type GithubHostError struct {
@@ -49,11 +51,53 @@ func knownGitHubHosts() []string {
 			hosts = append(hosts, ghHost)
 		}
 	}
+	for _, aliasHost := range HostAliases() {
+		hosts = append(hosts, aliasHost)
+	}
 
 	cachedHosts = hosts
 	return hosts
 }
 
+var hostAliasConfigRe = regexp.MustCompile(`^hub\.hostAlias\.([^.\s]+) (.+)$`)
+
+// HostAliases returns the hostnames configured with "hub.hostAlias.<NAME>" in
+// git config, keyed by the lowercase alias name. Names that contain a dot are
+// ignored so that an alias can never be confused with a real hostname.
+func HostAliases() map[string]string {
+	if cachedHostAliases != nil {
+		return cachedHostAliases
+	}
+
+	aliases := map[string]string{}
+	lines, _ := git.ConfigAll("hub.hostAlias.*")
+	for _, line := range lines {
+		if matches := hostAliasConfigRe.FindStringSubmatch(line); matches != nil {
+			if hostname := strings.TrimSpace(matches[2]); hostname != "" {
+				aliases[strings.ToLower(matches[1])] = hostname
+			}
+		}
+	}
+
+	cachedHostAliases = aliases
+	return aliases
+}
+
+var hostAliasRe = regexp.MustCompile(`^([a-zA-Z0-9][\w-]*):([^/:]+(?:/[^/:]+)?)$`)
+
+// ExpandHostAlias splits s in the "<ALIAS>:<OWNER>/<REPO>" format into the
+// hostname that <ALIAS> stands for and the "<OWNER>/<REPO>" part. The result
+// is ok only if <ALIAS> is a configured host alias.
+func ExpandHostAlias(s string) (hostname, nameWithOwner string, ok bool) {
+	matches := hostAliasRe.FindStringSubmatch(s)
+	if matches == nil {
+		return
+	}
+	hostname, ok = HostAliases()[strings.ToLower(matches[1])]
+	nameWithOwner = matches[2]
+	return
+}
+
 func DefaultGitHubHost() string {
 	defaultHost := GitHubHostEnv
 	if defaultHost == "" {
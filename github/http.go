@@ -12,7 +12,6 @@ import (
 	"net/http"
 	"net/url"
 	"os"
-	"path"
 	"path/filepath"
 	"regexp"
 	"sort"
@@ -29,7 +28,10 @@ const patchMediaType = "application/vnd.github.v3.patch;charset=utf-8"
 const textMediaType = "text/plain;charset=utf-8"
 const checksType = "application/vnd.github.antiope-preview+json;charset=utf-8"
 const draftsType = "application/vnd.github.shadow-cat-preview+json;charset=utf-8"
+const topicsType = "application/vnd.github.mercy-preview+json;charset=utf-8"
 
+// inspectHeaders are dumped first, in this order, followed by the rest of the
+// headers sorted by name
 var inspectHeaders = []string{
 	"Authorization",
 	"X-GitHub-OTP",
@@ -38,16 +40,40 @@ var inspectHeaders = []string{
 	"Accept",
 }
 
+// secretHeaders never have their values dumped
+var secretHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"X-GitHub-OTP",
+	"Cookie",
+	"Set-Cookie",
+}
+
+// Levels of HUB_VERBOSE; each level includes everything from the ones below
+const (
+	verboseRequests = 1
+	verboseHeaders  = 2
+	verboseBodies   = 3
+)
+
+const verboseBodyLimit = 4096
+
+var (
+	authorizationRe = regexp.MustCompile("(?i)^(basic|token|bearer) (.+)")
+	secretParamRe   = regexp.MustCompile("(?i)((?:access_token|client_secret)=)[^&]+")
+	secretFieldRe   = regexp.MustCompile(`("(?:token|hashed_token|password|client_secret)"\s*:\s*)"[^"]*"`)
+)
+
 type verboseTransport struct {
 	Transport   *http.Transport
-	Verbose     bool
+	Verbosity   int
 	OverrideURL *url.URL
 	Out         io.Writer
 	Colorized   bool
 }
 
 func (t *verboseTransport) RoundTrip(req *http.Request) (resp *http.Response, err error) {
-	if t.Verbose {
+	if t.Verbosity >= verboseRequests {
 		t.dumpRequest(req)
 	}
 
@@ -67,7 +93,7 @@ func (t *verboseTransport) RoundTrip(req *http.Request) (resp *http.Response, er
 
 	resp, err = t.Transport.RoundTrip(req)
 
-	if err == nil && t.Verbose {
+	if err == nil && t.Verbosity >= verboseRequests {
 		t.dumpResponse(resp)
 	}
 
@@ -75,48 +101,79 @@ func (t *verboseTransport) RoundTrip(req *http.Request) (resp *http.Response, er
 }
 
 func (t *verboseTransport) dumpRequest(req *http.Request) {
-	info := fmt.Sprintf("> %s %s://%s%s", req.Method, req.URL.Scheme, req.URL.Host, req.URL.RequestURI())
+	requestURI := secretParamRe.ReplaceAllString(req.URL.RequestURI(), "$1[REDACTED]")
+	info := fmt.Sprintf("> %s %s://%s%s", req.Method, req.URL.Scheme, req.URL.Host, requestURI)
 	t.verbosePrintln(info)
-	t.dumpHeaders(req.Header, ">")
-	body := t.dumpBody(req.Body)
-	if body != nil {
-		// reset body since it's been read
-		req.Body = body
+	if t.Verbosity >= verboseHeaders {
+		t.dumpHeaders(req.Header, ">")
+	}
+	if t.Verbosity >= verboseBodies {
+		body := t.dumpBody(req.Body)
+		if body != nil {
+			// reset body since it's been read
+			req.Body = body
+		}
 	}
 }
 
 func (t *verboseTransport) dumpResponse(resp *http.Response) {
 	info := fmt.Sprintf("< HTTP %d", resp.StatusCode)
 	t.verbosePrintln(info)
-	t.dumpHeaders(resp.Header, "<")
-	body := t.dumpBody(resp.Body)
-	if body != nil {
-		// reset body since it's been read
-		resp.Body = body
+	if t.Verbosity >= verboseHeaders {
+		t.dumpHeaders(resp.Header, "<")
+	}
+	if t.Verbosity >= verboseBodies {
+		body := t.dumpBody(resp.Body)
+		if body != nil {
+			// reset body since it's been read
+			resp.Body = body
+		}
 	}
 }
 
 func (t *verboseTransport) dumpHeaders(header http.Header, indent string) {
-	for _, listed := range inspectHeaders {
-		for name, vv := range header {
-			if !strings.EqualFold(name, listed) {
-				continue
-			}
-			for _, v := range vv {
-				if v != "" {
-					r := regexp.MustCompile("(?i)^(basic|token) (.+)")
-					if r.MatchString(v) {
-						v = r.ReplaceAllString(v, "$1 [REDACTED]")
-					}
-
-					info := fmt.Sprintf("%s %s: %s", indent, name, v)
-					t.verbosePrintln(info)
-				}
+	names := []string{}
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return inspectOrder(names[i]) < inspectOrder(names[j]) ||
+			(inspectOrder(names[i]) == inspectOrder(names[j]) && names[i] < names[j])
+	})
+
+	for _, name := range names {
+		for _, v := range header[name] {
+			if v != "" {
+				info := fmt.Sprintf("%s %s: %s", indent, name, redactHeader(name, v))
+				t.verbosePrintln(info)
 			}
 		}
 	}
 }
 
+// inspectOrder ranks headers listed in inspectHeaders before all others
+func inspectOrder(name string) int {
+	for i, listed := range inspectHeaders {
+		if strings.EqualFold(name, listed) {
+			return i
+		}
+	}
+	return len(inspectHeaders)
+}
+
+func redactHeader(name, value string) string {
+	for _, secret := range secretHeaders {
+		if !strings.EqualFold(name, secret) {
+			continue
+		}
+		if authorizationRe.MatchString(value) {
+			return authorizationRe.ReplaceAllString(value, "$1 [REDACTED]")
+		}
+		return "[REDACTED]"
+	}
+	return value
+}
+
 func (t *verboseTransport) dumpBody(body io.ReadCloser) io.ReadCloser {
 	if body == nil {
 		return nil
@@ -128,7 +185,11 @@ func (t *verboseTransport) dumpBody(body io.ReadCloser) io.ReadCloser {
 	utils.Check(err)
 
 	if buf.Len() > 0 {
-		t.verbosePrintln(buf.String())
+		dump := secretFieldRe.ReplaceAllString(buf.String(), `$1"[REDACTED]"`)
+		if len(dump) > verboseBodyLimit {
+			dump = fmt.Sprintf("%s\n[%d more bytes]", dump[:verboseBodyLimit], len(dump)-verboseBodyLimit)
+		}
+		t.verbosePrintln(dump)
 	}
 
 	return ioutil.NopCloser(buf)
@@ -142,7 +203,41 @@ func (t *verboseTransport) verbosePrintln(msg string) {
 	fmt.Fprintln(t.Out, msg)
 }
 
-func newHttpClient(testHost string, verbose bool, unixSocket string) *http.Client {
+// ConnectTimeout bounds the time that establishing a connection to the API may
+// take. It's set by `hub api --connect-timeout`; otherwise the
+// HUB_CONNECT_TIMEOUT environment variable or a 30 second default is used.
+var ConnectTimeout time.Duration
+
+const defaultConnectTimeout = 30 * time.Second
+
+func connectTimeout() time.Duration {
+	if ConnectTimeout > 0 {
+		return ConnectTimeout
+	}
+	if value := os.Getenv("HUB_CONNECT_TIMEOUT"); value != "" {
+		if timeout, err := ParseConnectTimeout(value); err == nil {
+			return timeout
+		}
+	}
+	return defaultConnectTimeout
+}
+
+// ParseConnectTimeout reads a duration such as "500ms" or "2s", or a plain
+// number of seconds
+func ParseConnectTimeout(value string) (time.Duration, error) {
+	timeout, err := time.ParseDuration(value)
+	if err != nil {
+		var seconds int
+		seconds, err = strconv.Atoi(value)
+		timeout = time.Duration(seconds) * time.Second
+	}
+	if err != nil || timeout <= 0 {
+		return 0, fmt.Errorf("invalid connect timeout `%s'; expected a duration such as \"5s\"", value)
+	}
+	return timeout, nil
+}
+
+func newHttpClient(testHost string, verbosity int, unixSocket string) *http.Client {
 	var testURL *url.URL
 	if testHost != "" {
 		testURL, _ = url.Parse(testHost)
@@ -166,7 +261,7 @@ func newHttpClient(testHost string, verbose bool, unixSocket string) *http.Clien
 		httpTransport = &http.Transport{
 			Proxy: proxyFromEnvironment,
 			DialContext: (&net.Dialer{
-				Timeout:   30 * time.Second,
+				Timeout:   connectTimeout(),
 				KeepAlive: 30 * time.Second,
 			}).DialContext,
 			TLSHandshakeTimeout: 10 * time.Second,
@@ -174,7 +269,7 @@ func newHttpClient(testHost string, verbose bool, unixSocket string) *http.Clien
 	}
 	tr := &verboseTransport{
 		Transport:   httpTransport,
-		Verbose:     verbose,
+		Verbosity:   verbosity,
 		OverrideURL: testURL,
 		Out:         ui.Stderr,
 		Colorized:   ui.IsTerminal(os.Stderr),
@@ -242,11 +337,11 @@ func (c *simpleClient) performRequestUrl(method string, url *url.URL, body io.Re
 	if err != nil {
 		return
 	}
+	req.Header.Set("User-Agent", UserAgent)
+	req.Header.Set("Accept", apiPayloadVersion)
 	if c.PrepareRequest != nil {
 		c.PrepareRequest(req)
 	}
-	req.Header.Set("User-Agent", UserAgent)
-	req.Header.Set("Accept", apiPayloadVersion)
 
 	if configure != nil {
 		configure(req)
@@ -260,6 +355,7 @@ func (c *simpleClient) performRequestUrl(method string, url *url.URL, body io.Re
 
 	httpResponse, err := c.httpClient.Do(req)
 	if err != nil {
+		err = &NetworkError{err}
 		return
 	}
 
@@ -279,11 +375,14 @@ func canCache(req *http.Request) bool {
 
 func (c *simpleClient) cacheRead(key string, req *http.Request) (res *http.Response) {
 	if c.CacheTTL > 0 && canCache(req) {
-		f := cacheFile(key)
-		cacheInfo, err := os.Stat(f)
+		f, err := cacheFile(key)
 		if err != nil {
 			return
 		}
+		cacheInfo, err := os.Stat(f)
+		if err != nil || !isPrivateFile(cacheInfo) {
+			return
+		}
 		if time.Since(cacheInfo.ModTime()).Seconds() > float64(c.CacheTTL) {
 			return
 		}
@@ -328,15 +427,14 @@ func (c *simpleClient) cacheRead(key string, req *http.Request) (res *http.Respo
 }
 
 func (c *simpleClient) cacheWrite(key string, res *http.Response) {
-	if c.CacheTTL > 0 && canCache(res.Request) && res.StatusCode < 500 && res.StatusCode != 403 {
+	if c.CacheTTL > 0 && canCache(res.Request) && res.StatusCode < 500 && res.StatusCode != 401 && res.StatusCode != 403 {
 		bodyCopy := &bytes.Buffer{}
 		bodyReplacement := readCloserCallback{
 			Reader: io.TeeReader(res.Body, bodyCopy),
 			Closer: res.Body,
 			Callback: func() {
-				f := cacheFile(key)
-				err := os.MkdirAll(filepath.Dir(f), 0771)
-				if err != nil {
+				f, err := cacheFile(key)
+				if err != nil || os.MkdirAll(filepath.Dir(f), 0700) != nil {
 					return
 				}
 				cf, err := os.OpenFile(f, os.O_WRONLY|os.O_CREATE, 0600)
@@ -394,8 +492,14 @@ func cacheKey(req *http.Request) string {
 	return fmt.Sprintf("%s/%s_%x", host, path, hash.Sum(nil))
 }
 
-func cacheFile(key string) string {
-	return path.Join(os.TempDir(), "hub", "api", key)
+// cacheFile is where the response for key is cached, in the cache directory
+// of the current user like the repository cache
+func cacheFile(key string) (string, error) {
+	dir, err := userCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "hub", "api", filepath.FromSlash(key)), nil
 }
 
 func (c *simpleClient) jsonRequest(method, path string, body interface{}, configure func(*http.Request)) (*simpleResponse, error) {
@@ -435,6 +539,34 @@ func (c *simpleClient) PatchJSON(path string, payload interface{}) (*simpleRespo
 	return c.jsonRequest("PATCH", path, payload, nil)
 }
 
+func (c *simpleClient) PutJSON(path string, payload interface{}) (*simpleResponse, error) {
+	return c.jsonRequest("PUT", path, payload, nil)
+}
+
+func (c *simpleClient) GetPreview(path string, mimeType string) (*simpleResponse, error) {
+	return c.performRequest("GET", path, nil, func(req *http.Request) {
+		req.Header.Set("Accept", mimeType)
+	})
+}
+
+func (c *simpleClient) DeletePreview(path string, mimeType string) (*simpleResponse, error) {
+	return c.performRequest("DELETE", path, nil, func(req *http.Request) {
+		req.Header.Set("Accept", mimeType)
+	})
+}
+
+func (c *simpleClient) PostJSONPreview(path string, payload interface{}, mimeType string) (*simpleResponse, error) {
+	return c.jsonRequest("POST", path, payload, func(req *http.Request) {
+		req.Header.Set("Accept", mimeType)
+	})
+}
+
+func (c *simpleClient) PutJSONPreview(path string, payload interface{}, mimeType string) (*simpleResponse, error) {
+	return c.jsonRequest("PUT", path, payload, func(req *http.Request) {
+		req.Header.Set("Accept", mimeType)
+	})
+}
+
 func (c *simpleClient) PostFile(path, filename string) (*simpleResponse, error) {
 	stat, err := os.Stat(filename)
 	if err != nil {
@@ -10,7 +10,9 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/bmizerany/assert"
 )
@@ -55,7 +57,7 @@ func TestNewHttpClient_OverrideURL(t *testing.T) {
 		assert.Equal(t, "example.com", r.Host)
 	})
 
-	c := newHttpClient(s.URL.String(), false, "")
+	c := newHttpClient(s.URL.String(), 0, "")
 	c.Get("https://example.com/override")
 
 	s.HandleFunc("/not-override", func(w http.ResponseWriter, r *http.Request) {
@@ -63,7 +65,7 @@ func TestNewHttpClient_OverrideURL(t *testing.T) {
 		assert.Equal(t, s.URL.Host, r.Host)
 	})
 
-	c = newHttpClient("", false, "")
+	c = newHttpClient("", 0, "")
 	c.Get(fmt.Sprintf("%s/not-override", s.URL.String()))
 }
 
@@ -75,7 +77,7 @@ func TestNewHttpClient_UnixSocket(t *testing.T) {
 	s.HandleFunc("/unix-socket", func(w http.ResponseWriter, r *http.Request) {
 		w.Write([]byte("unix-socket-works"))
 	})
-	c := newHttpClient("", false, sock)
+	c := newHttpClient("", 0, sock)
 	resp, err := c.Get(fmt.Sprintf("%s/unix-socket", s.URL.String()))
 	assert.Equal(t, nil, err)
 	result, _ := ioutil.ReadAll(resp.Body)
@@ -92,3 +94,76 @@ func TestVerboseTransport_VerbosePrintln(t *testing.T) {
 	tr.verbosePrintln("foo")
 	assert.Equal(t, "\033[36mfoo\033[0m\n", b.String())
 }
+
+func TestVerboseTransport_DumpHeaders(t *testing.T) {
+	var b bytes.Buffer
+	tr := &verboseTransport{
+		Out: &b,
+	}
+
+	tr.dumpHeaders(http.Header{"Authorization": []string{"token SECRET"}}, ">")
+	tr.dumpHeaders(http.Header{"Authorization": []string{"custom-SECRET"}}, ">")
+	assert.Equal(t, "> Authorization: token [REDACTED]\n> Authorization: [REDACTED]\n", b.String())
+}
+
+func TestVerboseTransport_DumpBody(t *testing.T) {
+	var b bytes.Buffer
+	tr := &verboseTransport{
+		Out: &b,
+	}
+
+	body := tr.dumpBody(ioutil.NopCloser(strings.NewReader(`{"token": "SECRET", "note": "hub"}`)))
+	assert.Equal(t, "{\"token\": \"[REDACTED]\", \"note\": \"hub\"}\n", b.String())
+	content, _ := ioutil.ReadAll(body)
+	assert.Equal(t, `{"token": "SECRET", "note": "hub"}`, string(content))
+
+	b.Reset()
+	tr.dumpBody(ioutil.NopCloser(strings.NewReader(strings.Repeat("a", verboseBodyLimit+10))))
+	assert.Equal(t, strings.Repeat("a", verboseBodyLimit)+"\n[10 more bytes]\n", b.String())
+}
+
+func TestVerboseTransport_Verbosity(t *testing.T) {
+	var b bytes.Buffer
+	tr := &verboseTransport{
+		Out:       &b,
+		Verbosity: verboseRequests,
+	}
+
+	req, _ := http.NewRequest("GET", "https://api.github.com/user?access_token=SECRET&page=2", nil)
+	req.Header.Set("Authorization", "token SECRET")
+	tr.dumpRequest(req)
+	assert.Equal(t, "> GET https://api.github.com/user?access_token=[REDACTED]&page=2\n", b.String())
+
+	b.Reset()
+	tr.Verbosity = verboseHeaders
+	req.Header.Set("User-Agent", "Hub")
+	tr.dumpRequest(req)
+	assert.Equal(t, "> GET https://api.github.com/user?access_token=[REDACTED]&page=2\n> Authorization: token [REDACTED]\n> User-Agent: Hub\n", b.String())
+}
+
+func TestParseConnectTimeout(t *testing.T) {
+	timeout, err := ParseConnectTimeout("500ms")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 500*time.Millisecond, timeout)
+
+	timeout, err = ParseConnectTimeout("3")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 3*time.Second, timeout)
+
+	_, err = ParseConnectTimeout("-1s")
+	assert.Equal(t, "invalid connect timeout `-1s'; expected a duration such as \"5s\"", err.Error())
+}
+
+func TestConnectTimeout(t *testing.T) {
+	defer os.Setenv("HUB_CONNECT_TIMEOUT", os.Getenv("HUB_CONNECT_TIMEOUT"))
+	defer func() { ConnectTimeout = 0 }()
+
+	os.Setenv("HUB_CONNECT_TIMEOUT", "")
+	assert.Equal(t, 30*time.Second, connectTimeout())
+
+	os.Setenv("HUB_CONNECT_TIMEOUT", "2s")
+	assert.Equal(t, 2*time.Second, connectTimeout())
+
+	ConnectTimeout = time.Second
+	assert.Equal(t, time.Second, connectTimeout())
+}
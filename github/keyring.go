@@ -0,0 +1,173 @@
+package github
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/github/hub/cmd"
+	"github.com/github/hub/git"
+	"github.com/github/hub/ui"
+)
+
+// keyringBackend stores OAuth tokens outside of the hub config file
+type keyringBackend interface {
+	Get(host, user string) (string, error)
+	Set(host, user, token string) error
+}
+
+func keyringEnabled() bool {
+	enabled, _ := git.Config("hub.keyring")
+	return enabled == "true"
+}
+
+// newKeyringBackend picks the secure storage of the current platform. Both
+// backends shell out to the platform's CLI tool so that hub doesn't need cgo.
+func newKeyringBackend() (keyringBackend, error) {
+	switch runtime.GOOS {
+	case "darwin":
+		if _, err := exec.LookPath("security"); err != nil {
+			return nil, fmt.Errorf("the `security' tool was not found")
+		}
+		return &macKeychain{}, nil
+	case "linux":
+		if _, err := exec.LookPath("secret-tool"); err != nil {
+			return nil, fmt.Errorf("the `secret-tool' tool was not found; install libsecret-tools")
+		}
+		return &secretService{}, nil
+	default:
+		return nil, fmt.Errorf("no keyring is supported on %s", runtime.GOOS)
+	}
+}
+
+type macKeychain struct{}
+
+func (k *macKeychain) service(host string) string {
+	return "hub: " + host
+}
+
+func (k *macKeychain) Get(host, user string) (string, error) {
+	output, err := keyringToolOutput(cmd.New("security").WithArgs("find-generic-password", "-s", k.service(host), "-a", user, "-w"), "")
+	if err != nil {
+		return "", fmt.Errorf("no token found in the Keychain: %s", err)
+	}
+	return strings.TrimSpace(output), nil
+}
+
+// Set feeds the command to `security -i' over stdin so that the token never
+// shows up in the process list or in verbose logging of the command line.
+func (k *macKeychain) Set(host, user, token string) error {
+	command := keychainCommand("add-generic-password", "-U", "-s", k.service(host), "-a", user, "-w", token)
+	output, err := keyringToolOutput(cmd.New("security").WithArg("-i"), command)
+	if err != nil {
+		return fmt.Errorf("could not store the token in the Keychain: %s", err)
+	}
+	if output = strings.TrimSpace(output); output != "" {
+		return fmt.Errorf("%s", output)
+	}
+	return nil
+}
+
+func keychainCommand(args ...string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		arg = strings.Replace(arg, `\`, `\\`, -1)
+		arg = strings.Replace(arg, `"`, `\"`, -1)
+		quoted[i] = `"` + arg + `"`
+	}
+	return strings.Join(quoted, " ") + "\n"
+}
+
+type secretService struct{}
+
+func (k *secretService) Get(host, user string) (string, error) {
+	output, err := keyringToolOutput(cmd.New("secret-tool").WithArgs("lookup", "service", "hub", "host", host, "user", user), "")
+	if err != nil || output == "" {
+		return "", fmt.Errorf("no token found by secret-tool")
+	}
+	return strings.TrimSpace(output), nil
+}
+
+func (k *secretService) Set(host, user, token string) error {
+	store := cmd.New("secret-tool").WithArgs("store", "--label", "hub: "+host, "service", "hub", "host", host, "user", user)
+	_, err := keyringToolOutput(store, token)
+	return err
+}
+
+// keyringToolOutput runs c with input fed via stdin and returns its stdout.
+// Warnings that the tool prints to stderr must not end up in a token, so
+// stderr is only used as the error message if the tool fails.
+func keyringToolOutput(c *cmd.Cmd, input string) (string, error) {
+	c.Stderr = nil
+	output, err := c.FilterOutput(input)
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		if message := strings.TrimSpace(string(exitErr.Stderr)); message != "" {
+			err = fmt.Errorf("%s", message)
+		}
+	}
+	return output, err
+}
+
+// withKeyring fills in tokens missing from the config file from the keyring
+// and moves tokens that are still stored in plaintext into the keyring. It
+// reports whether any token was moved.
+func (c *Config) withKeyring(keyring keyringBackend) (migrated bool) {
+	for _, h := range c.Hosts {
+		if h.AccessToken != "" {
+			if err := keyring.Set(h.Host, h.User, h.AccessToken); err != nil {
+				ui.Errorf("Warning: could not store the token for %s in the keyring: %s\n", h.Host, err)
+				continue
+			}
+			h.keyringToken = true
+			migrated = true
+		} else if token, err := keyring.Get(h.Host, h.User); err == nil {
+			h.AccessToken = token
+			h.keyringToken = true
+		}
+	}
+	return
+}
+
+// withoutKeyringTokens returns a copy of the config that omits tokens which
+// are kept in the keyring
+func (c *Config) withoutKeyringTokens() *Config {
+	plain := &Config{}
+	for _, h := range c.Hosts {
+		host := *h
+		if host.keyringToken {
+			host.AccessToken = ""
+		}
+		plain.Hosts = append(plain.Hosts, &host)
+	}
+	return plain
+}
+
+// save writes the config file, storing tokens in the keyring instead when
+// `hub.keyring` is enabled
+func (c *Config) save() error {
+	if keyringEnabled() {
+		if keyring, err := newKeyringBackend(); err == nil {
+			c.withKeyring(keyring)
+		} else {
+			ui.Errorf("Warning: storing the token in plaintext: %s\n", err)
+		}
+	}
+	return newConfigService().Save(configsFile(), c.withoutKeyringTokens())
+}
+
+func (c *Config) loadKeyringTokens() {
+	if !keyringEnabled() || len(c.Hosts) == 0 {
+		return
+	}
+	keyring, err := newKeyringBackend()
+	if err != nil {
+		ui.Errorf("Warning: reading tokens from the config file: %s\n", err)
+		return
+	}
+	if c.withKeyring(keyring) {
+		if err := newConfigService().Save(configsFile(), c.withoutKeyringTokens()); err != nil {
+			ui.Errorf("Warning: could not remove plaintext tokens from %s: %s\n", configsFile(), err)
+		}
+	}
+}
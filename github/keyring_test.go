@@ -0,0 +1,73 @@
+package github
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/bmizerany/assert"
+	"github.com/github/hub/cmd"
+)
+
+type fakeKeyring map[string]string
+
+func (k fakeKeyring) Get(host, user string) (string, error) {
+	if token, ok := k[user+"@"+host]; ok {
+		return token, nil
+	}
+	return "", fmt.Errorf("not found")
+}
+
+func (k fakeKeyring) Set(host, user, token string) error {
+	k[user+"@"+host] = token
+	return nil
+}
+
+func TestConfig_withKeyring(t *testing.T) {
+	keyring := fakeKeyring{"mislav@github.com": "KEYRINGTOKEN"}
+	c := &Config{Hosts: []*Host{
+		{Host: "github.com", User: "mislav"},
+		{Host: "git.my.org", User: "octokitten", AccessToken: "PLAINTOKEN"},
+		{Host: "git.other.org", User: "monalisa"},
+	}}
+
+	migrated := c.withKeyring(keyring)
+	assert.Equal(t, true, migrated)
+	assert.Equal(t, "KEYRINGTOKEN", c.Hosts[0].AccessToken)
+	assert.Equal(t, "PLAINTOKEN", c.Hosts[1].AccessToken)
+	assert.Equal(t, "", c.Hosts[2].AccessToken)
+	assert.Equal(t, "PLAINTOKEN", keyring["octokitten@git.my.org"])
+
+	plain := c.withoutKeyringTokens()
+	assert.Equal(t, 3, len(plain.Hosts))
+	assert.Equal(t, "", plain.Hosts[0].AccessToken)
+	assert.Equal(t, "", plain.Hosts[1].AccessToken)
+	assert.Equal(t, "octokitten", plain.Hosts[1].User)
+	assert.Equal(t, "PLAINTOKEN", c.Hosts[1].AccessToken)
+}
+
+func TestConfig_withKeyring_NothingToMigrate(t *testing.T) {
+	c := &Config{Hosts: []*Host{{Host: "github.com", User: "mislav"}}}
+
+	migrated := c.withKeyring(fakeKeyring{"mislav@github.com": "KEYRINGTOKEN"})
+	assert.Equal(t, false, migrated)
+	assert.Equal(t, "KEYRINGTOKEN", c.Hosts[0].AccessToken)
+}
+
+func TestKeychainCommand(t *testing.T) {
+	command := keychainCommand("add-generic-password", "-a", `mis"lav`, "-w", `TO\KEN`)
+	assert.Equal(t, `"add-generic-password" "-a" "mis\"lav" "-w" "TO\\KEN"`+"\n", command)
+}
+
+func TestKeyringToolOutput(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs sh")
+	}
+
+	output, err := keyringToolOutput(cmd.New("sh").WithArgs("-c", "cat; echo warning >&2"), "TOKEN")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "TOKEN", output)
+
+	_, err = keyringToolOutput(cmd.New("sh").WithArgs("-c", "echo 'no such item' >&2; exit 1"), "")
+	assert.Equal(t, "no such item", err.Error())
+}
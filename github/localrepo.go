@@ -8,9 +8,18 @@ import (
 	"github.com/github/hub/git"
 )
 
+// RepoOverride is set by the "--repo" global flag. When present, it is used
+// as the target project instead of reading the git remotes, and commands don't
+// need to be run from within a git repository.
+var RepoOverride *Project
+
 func LocalRepo() (repo *GitHubRepo, err error) {
 	repo = &GitHubRepo{}
 
+	if RepoOverride != nil {
+		return
+	}
+
 	_, err = git.Dir()
 	if err != nil {
 		err = fmt.Errorf("fatal: Not a git repository")
@@ -203,6 +212,10 @@ func (r *GitHubRepo) MainRemote() (*Remote, error) {
 }
 
 func (r *GitHubRepo) MainProject() (*Project, error) {
+	if RepoOverride != nil {
+		return RepoOverride, nil
+	}
+
 	r.loadRemotes()
 
 	for _, remote := range r.remotes {
@@ -223,6 +236,10 @@ func (r *GitHubRepo) CurrentProject() (project *Project, err error) {
 }
 
 func (r *GitHubRepo) UpstreamProject() (project *Project, err error) {
+	if RepoOverride != nil {
+		return RepoOverride, nil
+	}
+
 	currentBranch, err := r.CurrentBranch()
 	if err != nil {
 		return
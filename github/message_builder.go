@@ -1,8 +1,12 @@
 package github
 
 import (
+	"fmt"
 	"regexp"
 	"strings"
+
+	"github.com/github/hub/cmd"
+	"github.com/github/hub/git"
 )
 
 type MessageBuilder struct {
@@ -10,6 +14,8 @@ type MessageBuilder struct {
 	Filename          string
 	Message           string
 	Edit              bool
+	KeepDraft         bool
+	EditLast          bool
 	commentedSections []string
 	editor            *Editor
 }
@@ -21,11 +27,24 @@ func (b *MessageBuilder) AddCommentedSection(section string) {
 func (b *MessageBuilder) Extract() (title, body string, err error) {
 	content := b.Message
 
+	if b.EditLast {
+		content, err = readDraft(b.Filename)
+		if err != nil {
+			err = fmt.Errorf("Aborted: no previous %s draft to edit", b.Title)
+			return
+		}
+		b.Edit = true
+	}
+
 	if b.Edit {
 		b.editor, err = NewEditor(b.Filename, b.Title, content)
 		if err != nil {
 			return
 		}
+		if b.EditLast {
+			// a stale message file would otherwise take precedence over the draft
+			b.editor.DeleteFile()
+		}
 		for _, section := range b.commentedSections {
 			b.editor.AddCommentedSection(section)
 		}
@@ -38,6 +57,17 @@ func (b *MessageBuilder) Extract() (title, body string, err error) {
 		content = nl.ReplaceAllString(content, "\n")
 	}
 
+	// an empty message means that the user aborted, which shouldn't wipe out
+	// the draft from the last attempt
+	if b.KeepDraft && strings.TrimSpace(content) != "" {
+		saveDraft(b.Filename, content)
+	}
+
+	content, err = prepareMessage(content)
+	if err != nil {
+		return
+	}
+
 	parts := strings.SplitN(content, "\n\n", 2)
 	if len(parts) >= 1 {
 		title = strings.TrimSpace(strings.Replace(parts[0], "\n", " ", -1))
@@ -46,15 +76,36 @@ func (b *MessageBuilder) Extract() (title, body string, err error) {
 		body = strings.TrimSpace(parts[1])
 	}
 
-	if title == "" {
-		defer b.Cleanup()
+	if title == "" && b.editor != nil {
+		defer b.editor.DeleteFile()
 	}
 
 	return
 }
 
+// Cleanup removes the message file and the saved draft once the message is
+// no longer needed
 func (b *MessageBuilder) Cleanup() {
 	if b.editor != nil {
 		b.editor.DeleteFile()
 	}
+	if b.KeepDraft {
+		deleteDraft(b.Filename)
+	}
+}
+
+// prepareMessage pipes the composed message through the filter command
+// configured in "hub.prepareMessage", if any.
+func prepareMessage(content string) (string, error) {
+	filter, _ := git.Config("hub.prepareMessage")
+	if filter == "" {
+		return content, nil
+	}
+
+	output, err := cmd.New(filter).FilterOutput(content)
+	if err != nil {
+		return "", fmt.Errorf("Aborted: hub.prepareMessage command `%s' failed: %s", filter, err)
+	}
+
+	return output, nil
 }
@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"github.com/bmizerany/assert"
+	"github.com/github/hub/cmd"
+	"github.com/github/hub/fixtures"
 )
 
 func TestMessageBuilder_multiline_title(t *testing.T) {
@@ -21,3 +23,60 @@ description`,
 	assert.Equal(t, "hello multiline text", title)
 	assert.Equal(t, "the rest is\ndescription", body)
 }
+
+func TestMessageBuilder_prepareMessage(t *testing.T) {
+	repo := fixtures.SetupTestRepo()
+	defer repo.TearDown()
+
+	_, err := cmd.New("git").WithArgs("config", "hub.prepareMessage", "tr a-z A-Z").CombinedOutput()
+	assert.Equal(t, nil, err)
+
+	builder := &MessageBuilder{
+		Message: "hello\n\nworld",
+	}
+
+	title, body, err := builder.Extract()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "HELLO", title)
+	assert.Equal(t, "WORLD", body)
+}
+
+func TestMessageBuilder_prepareMessageFailure(t *testing.T) {
+	repo := fixtures.SetupTestRepo()
+	defer repo.TearDown()
+
+	_, err := cmd.New("git").WithArgs("config", "hub.prepareMessage", "false").CombinedOutput()
+	assert.Equal(t, nil, err)
+
+	builder := &MessageBuilder{
+		Message: "hello\n\nworld",
+	}
+
+	_, _, err = builder.Extract()
+	assert.Equal(t, "Aborted: hub.prepareMessage command `false' failed: exit status 1", err.Error())
+}
+
+func TestMessageBuilder_KeepDraftOnAbort(t *testing.T) {
+	repo := fixtures.SetupTestRepo()
+	defer repo.TearDown()
+
+	builder := &MessageBuilder{
+		Filename:  "ISSUE_EDITMSG",
+		Message:   "hello\n\nworld",
+		KeepDraft: true,
+	}
+	_, _, err := builder.Extract()
+	assert.Equal(t, nil, err)
+
+	builder = &MessageBuilder{
+		Filename:  "ISSUE_EDITMSG",
+		KeepDraft: true,
+	}
+	title, _, err := builder.Extract()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "", title)
+
+	draft, err := readDraft("ISSUE_EDITMSG")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "hello\n\nworld", draft)
+}
@@ -0,0 +1,112 @@
+package github
+
+import (
+	"bufio"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/github/hub/git"
+	"github.com/mitchellh/go-homedir"
+)
+
+type netrcMachine struct {
+	Name     string
+	Login    string
+	Password string
+}
+
+func netrcEnabled() bool {
+	enabled, _ := git.Config("hub.netrc")
+	return enabled == "true"
+}
+
+// netrcFile is the location of the netrc file, which `NETRC` overrides
+func netrcFile() string {
+	if file := os.Getenv("NETRC"); file != "" {
+		return file
+	}
+	home, err := homedir.Dir()
+	if err != nil {
+		return ""
+	}
+	if runtime.GOOS == "windows" {
+		return filepath.Join(home, "_netrc")
+	}
+	return filepath.Join(home, ".netrc")
+}
+
+// netrcToken looks up the password for the API of host in the netrc file, if
+// reading it is enabled via `hub.netrc`. Entries for the API hostname take
+// precedence over entries for the hostname itself; "default" entries are
+// ignored.
+func netrcToken(host string) string {
+	if !netrcEnabled() {
+		return ""
+	}
+	file := netrcFile()
+	if file == "" {
+		return ""
+	}
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return ""
+	}
+
+	machines := parseNetrc(string(content))
+	for _, name := range []string{normalizeHost(host), host} {
+		for _, m := range machines {
+			if strings.EqualFold(m.Name, name) && m.Password != "" {
+				return m.Password
+			}
+		}
+	}
+	return ""
+}
+
+// parseNetrc extracts the "machine" entries of a netrc file. Macro
+// definitions are skipped.
+func parseNetrc(content string) (machines []netrcMachine) {
+	var current *netrcMachine
+	lines := strings.Split(content, "\n")
+lines:
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		scanner := bufio.NewScanner(strings.NewReader(line))
+		scanner.Split(bufio.ScanWords)
+		for scanner.Scan() {
+			switch token := scanner.Text(); token {
+			case "machine":
+				machines = append(machines, netrcMachine{})
+				current = &machines[len(machines)-1]
+				if scanner.Scan() {
+					current.Name = scanner.Text()
+				}
+			case "default":
+				current = nil
+			case "login", "password", "account":
+				if !scanner.Scan() || current == nil {
+					continue
+				}
+				if token == "login" {
+					current.Login = scanner.Text()
+				} else if token == "password" {
+					current.Password = scanner.Text()
+				}
+			case "macdef":
+				// the macro body runs until the next blank line
+				for i+1 < len(lines) && strings.TrimSpace(lines[i+1]) != "" {
+					i++
+				}
+				current = nil
+				continue lines
+			}
+		}
+	}
+	return
+}
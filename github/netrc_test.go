@@ -0,0 +1,26 @@
+package github
+
+import (
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestParseNetrc(t *testing.T) {
+	machines := parseNetrc(`# GitHub
+machine api.github.com login mislav password OTOKEN
+
+macdef init
+machine fake.example.com password NOPE
+
+machine git.my.org
+	login octokitten
+	account work
+	password ETOKEN
+default login anonymous password guest
+`)
+
+	assert.Equal(t, 2, len(machines))
+	assert.Equal(t, netrcMachine{Name: "api.github.com", Login: "mislav", Password: "OTOKEN"}, machines[0])
+	assert.Equal(t, netrcMachine{Name: "git.my.org", Login: "octokitten", Password: "ETOKEN"}, machines[1])
+}
@@ -0,0 +1,15 @@
+// +build !windows
+
+package github
+
+import (
+	"os"
+	"syscall"
+)
+
+// isPrivateFile reports whether the file described by info belongs to the
+// current user and can't be written by anyone else
+func isPrivateFile(info os.FileInfo) bool {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	return ok && int(stat.Uid) == os.Getuid() && info.Mode().Perm()&0022 == 0
+}
@@ -0,0 +1,12 @@
+// +build windows
+
+package github
+
+import "os"
+
+// isPrivateFile reports whether the file described by info belongs to the
+// current user. Files in the cache directory of a Windows user are only
+// accessible to that user already.
+func isPrivateFile(info os.FileInfo) bool {
+	return true
+}
@@ -70,17 +70,31 @@ func (p *Project) GitURL(name, owner string, isSSH bool) (url string) {
 		owner = p.Owner
 	}
 
-	host := rawHost(p.Host)
-
-	if preferredProtocol() == "https" {
-		url = fmt.Sprintf("https://%s/%s/%s.git", host, owner, name)
-	} else if isSSH || preferredProtocol() == "ssh" {
-		url = fmt.Sprintf("git@%s:%s/%s.git", host, owner, name)
-	} else {
-		url = fmt.Sprintf("git://%s/%s/%s.git", host, owner, name)
+	protocol := "git"
+	if preferred := preferredProtocol(); preferred == "https" || preferred == "http" {
+		protocol = preferred
+	} else if isSSH || preferred == "ssh" {
+		protocol = "ssh"
 	}
 
-	return url
+	return gitURLForProtocol(protocol, rawHost(p.Host), owner, name)
+}
+
+// ProtocolURL returns the git URL of the project using the given protocol:
+// one of "https", "http", "ssh", or "git".
+func (p *Project) ProtocolURL(protocol string) string {
+	return gitURLForProtocol(protocol, rawHost(p.Host), p.Owner, p.Name)
+}
+
+func gitURLForProtocol(protocol, host, owner, name string) string {
+	switch protocol {
+	case "https", "http":
+		return fmt.Sprintf("%s://%s/%s/%s.git", protocol, host, owner, name)
+	case "ssh":
+		return fmt.Sprintf("git@%s:%s/%s.git", host, owner, name)
+	default:
+		return fmt.Sprintf("git://%s/%s/%s.git", host, owner, name)
+	}
 }
 
 // Remove the scheme from host when the host url is absolute.
@@ -96,6 +110,12 @@ func rawHost(host string) string {
 }
 
 func preferredProtocol() string {
+	if httpsUpgradeDisabled() {
+		if protocol := mainRemoteProtocol(); protocol != "" {
+			return protocol
+		}
+	}
+
 	userProtocol := os.Getenv("HUB_PROTOCOL")
 	if userProtocol == "" {
 		userProtocol, _ = git.Config("hub.protocol")
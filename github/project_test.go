@@ -58,6 +58,29 @@ func TestProject_GitURL(t *testing.T) {
 	assert.Equal(t, "git@github.com:jingweno/gh.git", url)
 }
 
+func TestProject_GitURLNoHTTPSUpgrade(t *testing.T) {
+	repo := fixtures.SetupTestRepo()
+	defer repo.TearDown()
+	repo.AddRemote("upstream", "git://github.com/jingweno/gh.git", "")
+
+	os.Setenv("HUB_PROTOCOL", "https")
+	defer os.Setenv("HUB_PROTOCOL", "")
+	NoHTTPSUpgrade = true
+	defer func() { NoHTTPSUpgrade = false }()
+
+	project := Project{
+		Name:  "foo",
+		Owner: "bar",
+		Host:  "github.com",
+	}
+
+	url := project.GitURL("gh", "mislav", false)
+	assert.Equal(t, "git://github.com/mislav/gh.git", url)
+
+	url = project.GitURL("gh", "mislav", true)
+	assert.Equal(t, "git@github.com:mislav/gh.git", url)
+}
+
 func TestProject_GitURLEnterprise(t *testing.T) {
 	project := Project{
 		Name:  "foo",
@@ -11,6 +11,9 @@ import (
 
 var (
 	OriginNamesInLookupOrder = []string{"upstream", "github", "origin"}
+
+	// NoHTTPSUpgrade is set by the "--no-https-upgrade" global flag
+	NoHTTPSUpgrade bool
 )
 
 type Remote struct {
@@ -31,6 +34,74 @@ func (remote *Remote) Project() (*Project, error) {
 	return p, err
 }
 
+// Protocol returns the git protocol used by the remote, such as "https",
+// "http", "ssh", or "git", or "" if the remote has no URL.
+func (remote *Remote) Protocol() string {
+	u := remote.URL
+	if u == nil {
+		u = remote.PushURL
+	}
+	if u == nil {
+		return ""
+	}
+	return u.Scheme
+}
+
+// httpsUpgradeDisabled reports whether hub should keep using the protocol of
+// the existing git remotes when constructing URLs for new ones.
+func httpsUpgradeDisabled() bool {
+	if NoHTTPSUpgrade {
+		return true
+	}
+	value, _ := git.Config("hub.noHttpsUpgrade")
+	return value == "true"
+}
+
+// mainRemote finds the remote that the main project comes from: the first
+// one in lookup order that points to a GitHub repository, like in
+// MainProject(), or else the one returned by MainRemote()
+func mainRemote() *Remote {
+	repo, err := LocalRepo()
+	if err != nil || repo.loadRemotes() != nil {
+		return nil
+	}
+	for i := range repo.remotes {
+		if _, err := repo.remotes[i].Project(); err == nil {
+			return &repo.remotes[i]
+		}
+	}
+	remote, _ := repo.MainRemote()
+	return remote
+}
+
+func mainRemoteProtocol() string {
+	if remote := mainRemote(); remote != nil {
+		return remote.Protocol()
+	}
+	return ""
+}
+
+// mainRemoteAPIProtocol is the protocol to talk to the API of host over when
+// hub shouldn't upgrade the protocol of existing remotes: "http" if the main
+// remote points to host over plain HTTP, and "" otherwise
+func mainRemoteAPIProtocol(host string) string {
+	if !httpsUpgradeDisabled() {
+		return ""
+	}
+	remote := mainRemote()
+	if remote == nil {
+		return ""
+	}
+	project, err := remote.Project()
+	if err != nil || !strings.EqualFold(project.Host, host) || project.Protocol != "http" {
+		return ""
+	}
+	return "http"
+}
+
+// Remotes lists the git remotes of the current repository. The URLs are read
+// from `git remote -v`, which already applies "url.<base>.insteadOf" and
+// "url.<base>.pushInsteadOf" rewrites, so they match what git actually uses.
 func Remotes() (remotes []Remote, err error) {
 	re := regexp.MustCompile(`(.+)\s+(.+)\s+\((push|fetch)\)`)
 
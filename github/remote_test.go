@@ -5,6 +5,7 @@ import (
 
 	"github.com/bmizerany/assert"
 	"github.com/github/hub/fixtures"
+	"github.com/github/hub/git"
 )
 
 func TestGithubRemote_NoPush(t *testing.T) {
@@ -78,3 +79,81 @@ func TestGithubRemote_ColonSlash(t *testing.T) {
 	assert.Equal(t, remotes[1].Name, "origin")
 	assert.Equal(t, remotes[1].URL.Path, repo.Remote)
 }
+
+func TestGithubRemote_Protocol(t *testing.T) {
+	repo := fixtures.SetupTestRepo()
+	defer repo.TearDown()
+
+	repo.AddRemote("upstream", "git@github.com:test/project.git", "")
+	repo.AddRemote("github", "https://github.com/test/project.git", "")
+	repo.AddRemote("mirror", "git://github.com/test/project.git", "")
+	repo.AddRemote("plain", "http://github.com/test/project.git", "")
+
+	remotes, err := Remotes()
+	assert.Equal(t, nil, err)
+	protocols := map[string]string{}
+	for _, remote := range remotes {
+		protocols[remote.Name] = remote.Protocol()
+	}
+	assert.Equal(t, "ssh", protocols["upstream"])
+	assert.Equal(t, "https", protocols["github"])
+	assert.Equal(t, "git", protocols["mirror"])
+	assert.Equal(t, "http", protocols["plain"])
+
+	assert.Equal(t, "", (&Remote{Name: "empty"}).Protocol())
+}
+
+func TestGithubRemote_InsteadOf(t *testing.T) {
+	repo := fixtures.SetupTestRepo()
+	defer repo.TearDown()
+	cachedHosts = nil
+	defer func() { cachedHosts = nil }()
+
+	git.SetConfig("hub.host", "git.my.org")
+	git.SetConfig("url.ssh://git@git.my.org/.insteadOf", "corp:")
+	repo.AddRemote("upstream", "corp:mislav/dotfiles.git", "")
+
+	remotes, err := Remotes()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "upstream", remotes[0].Name)
+	assert.Equal(t, "git.my.org", remotes[0].URL.Host)
+	assert.Equal(t, "git.my.org", remotes[0].PushURL.Host)
+
+	project, err := remotes[0].Project()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "git.my.org", project.Host)
+	assert.Equal(t, "mislav", project.Owner)
+	assert.Equal(t, "dotfiles", project.Name)
+}
+
+func TestGithubRemote_PushInsteadOf(t *testing.T) {
+	repo := fixtures.SetupTestRepo()
+	defer repo.TearDown()
+	cachedHosts = nil
+	defer func() { cachedHosts = nil }()
+
+	git.SetConfig("hub.host", "git.my.org")
+	git.SetConfig("url.git@git.my.org:.pushInsteadOf", "https://mirror.example.com/")
+	repo.AddRemote("upstream", "https://mirror.example.com/mislav/dotfiles.git", "")
+
+	remotes, err := Remotes()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "mirror.example.com", remotes[0].URL.Host)
+	assert.Equal(t, "git.my.org", remotes[0].PushURL.Host)
+
+	project, err := remotes[0].Project()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "git.my.org", project.Host)
+	assert.Equal(t, "mislav/dotfiles", project.String())
+}
+
+func TestGithubRemote_MainRemoteProtocol(t *testing.T) {
+	repo := fixtures.SetupTestRepo()
+	defer repo.TearDown()
+
+	repo.AddRemote("upstream", "git://example.com/test/project.git", "")
+	repo.AddRemote("github", "git@github.com:test/project.git", "")
+
+	assert.Equal(t, "ssh", mainRemoteProtocol())
+	assert.Equal(t, "", mainRemoteAPIProtocol("github.com"))
+}
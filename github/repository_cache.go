@@ -0,0 +1,99 @@
+package github
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/go-homedir"
+)
+
+var (
+	// NoRepositoryCache is set by the "--no-cache" global flag
+	NoRepositoryCache bool
+
+	// repositoryCacheTTL is how long repository metadata, such as the default
+	// branch and the fork network, is reused before asking the API again
+	repositoryCacheTTL = 5 * time.Minute
+)
+
+// userCacheDir is the cache directory of the current user: $XDG_CACHE_HOME,
+// or "~/.cache" if that isn't set
+func userCacheDir() (string, error) {
+	if dir := os.Getenv("XDG_CACHE_HOME"); dir != "" {
+		return dir, nil
+	}
+	home, err := homedir.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".cache"), nil
+}
+
+// repositoryCacheFile is where the metadata of project is cached. The cache
+// lives in the cache directory of the current user rather than in the shared
+// temporary directory, so that other users can't plant it.
+func repositoryCacheFile(project *Project) (string, error) {
+	dir, err := userCacheDir()
+	if err != nil {
+		return "", err
+	}
+	host := strings.ToLower(project.Host)
+	if host == "" {
+		host = GitHubHost
+	}
+	return filepath.Join(dir, "hub", "repos", host, strings.ToLower(project.Owner), strings.ToLower(project.Name)+".json"), nil
+}
+
+func readRepositoryCache(project *Project) *Repository {
+	f, err := repositoryCacheFile(project)
+	if err != nil {
+		return nil
+	}
+	info, err := os.Stat(f)
+	if err != nil || time.Since(info.ModTime()) > repositoryCacheTTL || !isPrivateFile(info) {
+		return nil
+	}
+	content, err := ioutil.ReadFile(f)
+	if err != nil {
+		return nil
+	}
+	repo := &Repository{}
+	if json.Unmarshal(content, repo) != nil {
+		return nil
+	}
+	return repo
+}
+
+func writeRepositoryCache(project *Project, repo *Repository) {
+	content, err := json.Marshal(repo)
+	if err != nil {
+		return
+	}
+	f, err := repositoryCacheFile(project)
+	if err != nil || os.MkdirAll(filepath.Dir(f), 0700) != nil {
+		return
+	}
+	ioutil.WriteFile(f, content, 0600)
+}
+
+// CachedRepository is like Repository, but reuses the response for the same
+// repository that was fetched in the last few minutes. Only successful
+// responses are cached. With `--no-cache`, the cache is refreshed instead of
+// being read.
+func (client *Client) CachedRepository(project *Project) (*Repository, error) {
+	if !NoRepositoryCache {
+		if repo := readRepositoryCache(project); repo != nil {
+			return repo, nil
+		}
+	}
+
+	repo, err := client.Repository(project)
+	if err == nil {
+		writeRepositoryCache(project, repo)
+	}
+	return repo, err
+}
@@ -0,0 +1,44 @@
+package github
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestRepositoryCache(t *testing.T) {
+	tmpdir, _ := ioutil.TempDir("", "repository-cache")
+	defer os.RemoveAll(tmpdir)
+	for _, name := range []string{"HOME", "XDG_CACHE_HOME"} {
+		defer os.Setenv(name, os.Getenv(name))
+		os.Setenv(name, tmpdir)
+	}
+
+	project := NewProject("Mislav", "Dotfiles", "github.com")
+	assert.T(t, readRepositoryCache(project) == nil)
+
+	writeRepositoryCache(project, &Repository{
+		FullName:      "mislav/dotfiles",
+		DefaultBranch: "main",
+		Source:        &Repository{FullName: "github/dotfiles"},
+	})
+	repo := readRepositoryCache(NewProject("mislav", "dotfiles", "GitHub.com"))
+	assert.Equal(t, "main", repo.DefaultBranch)
+	assert.Equal(t, "github/dotfiles", repo.Source.FullName)
+
+	f, err := repositoryCacheFile(project)
+	assert.Equal(t, nil, err)
+	assert.T(t, strings.HasPrefix(f, tmpdir))
+
+	os.Chmod(f, 0666)
+	assert.T(t, readRepositoryCache(project) == nil)
+	os.Chmod(f, 0600)
+
+	expired := time.Now().Add(-repositoryCacheTTL - time.Second)
+	os.Chtimes(f, expired, expired)
+	assert.T(t, readRepositoryCache(project) == nil)
+}
@@ -1,6 +1,7 @@
 package github
 
 import (
+	"fmt"
 	"net/url"
 	"strings"
 )
@@ -20,6 +21,10 @@ func (url URL) ProjectPath() (projectPath string) {
 }
 
 func ParseURL(rawurl string) (*URL, error) {
+	if hostname, nameWithOwner, ok := ExpandHostAlias(rawurl); ok {
+		rawurl = fmt.Sprintf("https://%s/%s", hostname, nameWithOwner)
+	}
+
 	url, err := url.Parse(rawurl)
 	if err != nil {
 		return nil, err
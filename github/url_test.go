@@ -5,6 +5,7 @@ import (
 
 	"github.com/bmizerany/assert"
 	"github.com/github/hub/fixtures"
+	"github.com/github/hub/git"
 )
 
 func TestParseURL(t *testing.T) {
@@ -32,3 +33,24 @@ func TestParseURL(t *testing.T) {
 	assert.Equal(t, "gh", url.Name)
 	assert.Equal(t, "", url.ProjectPath())
 }
+
+func TestParseURL_HostAlias(t *testing.T) {
+	repo := fixtures.SetupTestRepo()
+	defer repo.TearDown()
+	testConfigs := fixtures.SetupTestConfigs()
+	defer testConfigs.TearDown()
+
+	git.SetConfig("hub.hostAlias.work", "git.my.org")
+	cachedHosts = nil
+	cachedHostAliases = nil
+	defer func() { cachedHosts, cachedHostAliases = nil, nil }()
+
+	url, err := ParseURL("work:myteam/myproject")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "git.my.org", url.Project.Host)
+	assert.Equal(t, "myteam", url.Owner)
+	assert.Equal(t, "myproject", url.Name)
+
+	_, _, ok := ExpandHostAlias("home:myteam/myproject")
+	assert.T(t, !ok)
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/github/hub/commands"
 	"github.com/github/hub/github"
 	"github.com/github/hub/ui"
+	"github.com/github/hub/utils"
 )
 
 func main() {
@@ -38,6 +39,6 @@ func handleError(err error) int {
 		if errString := err.Error(); errString != "" {
 			ui.Errorln(err)
 		}
-		return 1
+		return utils.ExitCode(err)
 	}
 }
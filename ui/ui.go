@@ -3,6 +3,7 @@ package ui
 import (
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
 
 	"github.com/mattn/go-colorable"
@@ -23,6 +24,32 @@ var (
 	Default UI = Console{Stdout: Stdout, Stderr: Stderr}
 )
 
+// Verbosity levels, as chosen by the "--quiet" and "--silent" global flags
+const (
+	VerbosityNormal = iota
+	// VerbosityQuiet skips status messages, but not results
+	VerbosityQuiet
+	// VerbositySilent also discards everything written to standard output
+	VerbositySilent
+)
+
+var verbosity = VerbosityNormal
+
+// SetVerbosity changes what gets printed for the rest of the process. Errors
+// and warnings are always printed.
+func SetVerbosity(level int) {
+	verbosity = level
+	if verbosity >= VerbositySilent {
+		Stdout = ioutil.Discard
+		Default = Console{Stdout: Stdout, Stderr: Stderr}
+	}
+}
+
+// IsQuiet reports whether status messages are being skipped
+func IsQuiet() bool {
+	return verbosity >= VerbosityQuiet
+}
+
 func Print(a ...interface{}) (n int) {
 	n, err := Default.Print(a...)
 	if err != nil {
@@ -68,6 +95,37 @@ func Errorln(a ...interface{}) (n int) {
 	return
 }
 
+// Infof prints a status message to standard output, such as about the
+// progress of a command, unless in quiet mode
+func Infof(format string, a ...interface{}) (n int) {
+	if IsQuiet() {
+		return
+	}
+	return Printf(format, a...)
+}
+
+func Infoln(a ...interface{}) (n int) {
+	if IsQuiet() {
+		return
+	}
+	return Println(a...)
+}
+
+// Noticef is like Infof, but prints to standard error
+func Noticef(format string, a ...interface{}) (n int) {
+	if IsQuiet() {
+		return
+	}
+	return Errorf(format, a...)
+}
+
+func Noticeln(a ...interface{}) (n int) {
+	if IsQuiet() {
+		return
+	}
+	return Errorln(a...)
+}
+
 func IsTerminal(f *os.File) bool {
 	return isatty.IsTerminal(f.Fd())
 }
@@ -199,3 +199,14 @@ func TestArgsParser_WithUsage(t *testing.T) {
 	equal(t, true, p.Bool("--draft"))
 	equal(t, "hello", p.Value("--message"))
 }
+
+func TestArgsParser_WithUsageSlashInValue(t *testing.T) {
+	p := NewArgsParserWithUsage(`
+		--head-repo <OWNER>/<REPO>
+			the repository of the head branch
+	`)
+	rest, err := p.Parse([]string{"--head-repo", "mojombo/coral", "feature"})
+	equal(t, nil, err)
+	equal(t, []string{"feature"}, rest)
+	equal(t, "mojombo/coral", p.Value("--head-repo"))
+}
@@ -7,6 +7,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 	"time"
 
@@ -15,19 +16,61 @@ import (
 
 var timeNow = time.Now
 
+// Exit statuses that hub uses to let scripts tell categories of errors apart
+const (
+	ExitError       = 1
+	ExitUsage       = 2
+	ExitAuth        = 3
+	ExitNotFound    = 4
+	ExitRateLimited = 5
+	ExitNetwork     = 6
+	ExitPartial     = 7
+)
+
+// ExitCode picks the exit status for err. Errors can choose their own status
+// by implementing `ExitCode() int`; everything else is a generic failure.
+func ExitCode(err error) int {
+	if e, ok := err.(interface{ ExitCode() int }); ok {
+		return e.ExitCode()
+	}
+	return ExitError
+}
+
 func Check(err error) {
 	if err != nil {
 		ui.Errorln(err)
-		os.Exit(1)
+		os.Exit(ExitCode(err))
+	}
+}
+
+// VerboseLevel parses HUB_VERBOSE. A number sets the level directly, while any
+// other non-empty value means level 1.
+func VerboseLevel() int {
+	value := os.Getenv("HUB_VERBOSE")
+	if value == "" {
+		return 0
+	}
+	if level, err := strconv.Atoi(value); err == nil {
+		if level < 0 {
+			return 0
+		}
+		return level
 	}
+	return 1
 }
 
 func ConcatPaths(paths ...string) string {
 	return strings.Join(paths, "/")
 }
 
+// BrowserLauncher returns the command that opens a URL in a web browser:
+// $HUB_BROWSER if set, then $BROWSER, then the first launcher found for the
+// current platform.
 func BrowserLauncher() ([]string, error) {
-	browser := os.Getenv("BROWSER")
+	browser := os.Getenv("HUB_BROWSER")
+	if browser == "" {
+		browser = os.Getenv("BROWSER")
+	}
 	if browser == "" {
 		browser = searchBrowserLauncher(runtime.GOOS)
 	}
@@ -1,9 +1,12 @@
 package utils
 
 import (
-	"github.com/bmizerany/assert"
+	"os"
+	"runtime"
 	"testing"
 	"time"
+
+	"github.com/bmizerany/assert"
 )
 
 func TestSearchBrowserLauncher(t *testing.T) {
@@ -69,3 +72,27 @@ func TestTimeAgo(t *testing.T) {
 	actual = TimeAgo(yearsAgo)
 	assert.Equal(t, "2 years ago", actual)
 }
+
+func TestBrowserLauncher(t *testing.T) {
+	defer os.Setenv("HUB_BROWSER", os.Getenv("HUB_BROWSER"))
+	defer os.Setenv("BROWSER", os.Getenv("BROWSER"))
+	defer os.Setenv("PATH", os.Getenv("PATH"))
+
+	os.Setenv("HUB_BROWSER", "firefox --new-window")
+	os.Setenv("BROWSER", "open")
+	launcher, err := BrowserLauncher()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []string{"firefox", "--new-window"}, launcher)
+
+	os.Setenv("HUB_BROWSER", "")
+	launcher, err = BrowserLauncher()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []string{"open"}, launcher)
+
+	if runtime.GOOS != "darwin" && runtime.GOOS != "windows" {
+		os.Setenv("BROWSER", "")
+		os.Setenv("PATH", "")
+		_, err = BrowserLauncher()
+		assert.NotEqual(t, nil, err)
+	}
+}